@@ -0,0 +1,6 @@
+package config
+
+type ConfigGroup struct {
+	Rollback RollbackCmd `cmd:"" help:"Restore a config file from its latest (or a given) backup"`
+	Backups  BackupsCmd  `cmd:"" help:"List the backups of a config file"`
+}
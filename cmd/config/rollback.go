@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	formatters "github.com/williampsena/ebenezer-cli/internal/cmd/formatters"
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+type ConfigCmd struct {
+	cmd.BaseCmd
+	File   string            `arg:"" help:"Config file written by ebenezer-cli (e.g. ~/.config/hypr/hyprlock.conf)"`
+	writer configfile.Writer `kong:"-"`
+}
+
+func (c *ConfigCmd) setup(ctx *cmd.Context) {
+	c.SetupContext(ctx)
+	c.File = core.ResolvePath(c.File)
+
+	if c.writer == nil {
+		c.writer = configfile.NewWriter(c.Logger)
+	}
+}
+
+type RollbackCmd struct {
+	ConfigCmd
+	Backup string `help:"Backup ID to restore, as listed by 'config backups'" default:""`
+}
+
+func (c *RollbackCmd) Run(ctx *cmd.Context) error {
+	c.setup(ctx)
+
+	backup, err := c.writer.Rollback(c.File, c.Backup)
+	if err != nil {
+		c.Logger.Error("Error restoring config file", "file", c.File, "error", err)
+		return err
+	}
+
+	return formatters.WriteToStdout(fmt.Sprintf("Restored %s from backup %s (%s)\n", c.File, backup.ID, backup.Time.Format("2006-01-02 15:04:05")))
+}
+
+type BackupsCmd struct {
+	ConfigCmd
+}
+
+func (c *BackupsCmd) Run(ctx *cmd.Context) error {
+	c.setup(ctx)
+
+	backups, err := c.writer.Backups(c.File)
+	if err != nil {
+		c.Logger.Error("Error listing backups", "file", c.File, "error", err)
+		return err
+	}
+
+	if len(backups) == 0 {
+		return formatters.WriteToStdout(fmt.Sprintf("No backups found for %s\n", c.File))
+	}
+
+	var lines []string
+	for _, backup := range backups {
+		lines = append(lines, fmt.Sprintf("%s  %s", backup.ID, backup.Time.Format("2006-01-02 15:04:05")))
+	}
+
+	return formatters.WriteToStdout(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func TestRollbackCmd_Run(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hyprpaper.conf")
+	writer := configfile.NewWriterWithOptions(core.BuildSilentLogger(), configfile.Options{BackupDir: filepath.Join(dir, "backups")})
+
+	for _, content := range []string{"good", "bad"} {
+		if err := writer.Write(path, []byte(content)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	rollbackCmd := &RollbackCmd{ConfigCmd: ConfigCmd{File: path, writer: writer}}
+	if err := rollbackCmd.Run(&cmd.Context{Silent: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != "good" {
+		t.Errorf("Expected 'good' to be restored, got '%s'", data)
+	}
+
+	rollbackCmd.Backup = "unknown"
+	if err := rollbackCmd.Run(&cmd.Context{Silent: true}); err == nil {
+		t.Error("Expected error for an unknown backup")
+	}
+}
+
+func TestBackupsCmd_Run(t *testing.T) {
+	dir := t.TempDir()
+	writer := configfile.NewWriterWithOptions(core.BuildSilentLogger(), configfile.Options{BackupDir: dir})
+
+	backupsCmd := &BackupsCmd{ConfigCmd: ConfigCmd{File: filepath.Join(dir, "missing.conf"), writer: writer}}
+	if err := backupsCmd.Run(&cmd.Context{Silent: true}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
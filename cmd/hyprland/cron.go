@@ -1,15 +1,21 @@
 package hyprland
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"maps"
 	"os"
-	"strings"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/go-co-op/gocron/v2"
 	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	"github.com/williampsena/ebenezer-cli/internal/control"
 	core "github.com/williampsena/ebenezer-cli/internal/core"
-	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	jokes "github.com/williampsena/ebenezer-cli/internal/jokes"
 	"github.com/williampsena/ebenezer-cli/internal/shell"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -25,11 +31,51 @@ type CronJob struct {
 	Command     string                 `yaml:"command"`
 	Args        map[string]interface{} `yaml:"args,omitempty"`
 	Interval    time.Duration          `yaml:"interval"`
+	// Schedule is a cron expression (5 fields, or 6 with seconds first) or a descriptor such as @daily.
+	Schedule string `yaml:"schedule"`
+	// At runs the job once, at a date and time or the next occurrence of a time of day.
+	At string `yaml:"at"`
+	// Timezone is the IANA zone of Schedule and At, the local one by default.
+	Timezone string `yaml:"timezone"`
+	// Timeout cancels the context of a run, which kills its commands and cancels its web requests.
+	// Shell jobs default to 10s.
+	Timeout time.Duration `yaml:"timeout"`
+	// Retries is the number of times a failed run is retried, waiting Backoff and doubling it each time.
+	Retries int           `yaml:"retries"`
+	Backoff time.Duration `yaml:"backoff"`
+	// Overlap decides what happens when a run is due while the previous one is still running:
+	// allow, skip-if-running or queue. singleton is an alias of skip-if-running.
+	Overlap string `yaml:"overlap"`
+	// RunOnStart also runs the job when the daemon starts or the job is (re)scheduled.
+	RunOnStart bool `yaml:"run_on_start"`
+	// Jitter delays each run by a random duration up to Jitter; interval jobs vary around the interval instead.
+	Jitter time.Duration `yaml:"jitter"`
+	// When holds the conditions checked before each scheduled run.
+	When *CronConditions `yaml:"when"`
+	// Shell runs Command through sh -c instead of splitting it into words, for pipes and redirections.
+	Shell bool `yaml:"shell"`
+	// Env adds variables to the environment of shell jobs; values may use templates and $VAR.
+	Env map[string]string `yaml:"env,omitempty"`
+	// Dir is the working directory of shell jobs.
+	Dir string `yaml:"dir"`
+	// After runs the job once every job it names has succeeded since its last run.
+	After []string `yaml:"after,omitempty"`
+	// OnSuccess and OnFailure name the jobs to run when this one succeeds or fails.
+	OnSuccess []string `yaml:"on_success,omitempty"`
+	OnFailure []string `yaml:"on_failure,omitempty"`
+	// NotifyAfter raises a desktop notification when the job fails this many times in a row.
+	NotifyAfter int `yaml:"notify_after"`
 }
 
 type CronCmd struct {
 	HyprlandCmd
-	Config string `arg:"" help:"Path to the configuration file" default:"~/.config/hypr/cron.yaml"`
+	Config  string `arg:"" help:"Path to the configuration file" default:"~/.config/hypr/cron.yaml"`
+	Socket  string `help:"Control socket used by the cron list, run, pause and resume commands (default: $XDG_RUNTIME_DIR/ebenezer/cron.sock)" default:""`
+	History string `help:"Run history file (default: ~/.local/state/ebenezer/cron-history.jsonl)" default:""`
+
+	// alerts holds the level last notified by the check handlers, by alert
+	alertsMu sync.Mutex
+	alerts   map[string]string
 }
 
 type DefinedCron map[string]CronHandlerBuilder
@@ -39,64 +85,45 @@ func (w *CronCmd) Run(ctx *cmd.Context) error {
 	return w.SetupCron(ctx)
 }
 
+// SetupCron runs the jobs until SIGINT or SIGTERM, reloading them when the config file changes
+// or on SIGHUP. Running jobs are given time to finish on shutdown.
 func (w *CronCmd) SetupCron(ctx *cmd.Context) error {
-	done := make(chan bool)
+	crons, err := w.parseCrons()
+	if err != nil {
+		w.Logger.Error("No valid cron jobs provided", "error", err)
+		return fmt.Errorf("no valid cron jobs provided: %w", err)
+	}
+
+	w.Logger.Info("Setting up cron jobs", "count", len(crons.Jobs))
 
-	scheduler, err := gocron.NewScheduler()
+	scheduler, err := newCronScheduler(w)
 	if err != nil {
 		w.Logger.Error("Failed to create scheduler", "error", err)
 		return err
 	}
 
-	crons, err := w.parseCrons()
-
-	if err != nil || crons.Jobs == nil || len(crons.Jobs) == 0 {
-		w.Logger.Error("No valid cron jobs provided", "error", err)
-		return fmt.Errorf("no valid cron jobs provided")
+	if err := scheduler.Apply(crons.Jobs); err != nil {
+		w.Logger.Error("Failed to create cron job", "error", err)
+		scheduler.Shutdown()
+		return err
 	}
 
-	w.Logger.Info("Setting up cron jobs", "count", len(crons.Jobs))
+	runCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	definedCron := w.buildDefinedCrons()
-
-	for _, cron := range crons.Jobs {
-		_, err := scheduler.NewJob(
-			gocron.DurationJob(
-				cron.Interval,
-			),
-			gocron.NewTask(
-				w.jobWrapper(
-					w.Logger,
-					func() {
-						w.Logger.Debug("Running cron job", "name", cron.Name, "type", cron.Command, "interval", cron.Interval)
-						handler := w.buildCronHandler(definedCron, cron)
-
-						if err := handler(); err != nil {
-							w.Logger.Error("Failed to execute cron job", "name", cron.Name, "error", err)
-						} else {
-							w.Logger.Info("Successfully executed cron job", "name", cron.Name)
-						}
-					},
-				),
-			),
-		)
-		if err != nil {
-			w.Logger.Error("Failed to create cron job", "error", err, "cron", cron)
-			return fmt.Errorf("failed to create cron job '%s': %w", cron.Name, err)
-		}
-	}
-
-	if err != nil {
-		w.Logger.Error("Failed to create new job", "error", err)
+	if err := control.Listen(runCtx, w.Logger, cronSocketPath(w.Socket), scheduler.Handle); err != nil {
+		w.Logger.Error("Failed to open the cron control socket", "error", err)
+		scheduler.Shutdown()
 		return err
 	}
 
 	scheduler.Start()
 
-	<-done
+	w.serve(runCtx, scheduler)
 
-	err = scheduler.Shutdown()
-	if err != nil {
+	w.Logger.Info("Stopping cron jobs")
+
+	if err := scheduler.Shutdown(); err != nil {
 		w.Logger.Error("Failed to shutdown scheduler", "error", err)
 		return err
 	}
@@ -104,6 +131,46 @@ func (w *CronCmd) SetupCron(ctx *cmd.Context) error {
 	return nil
 }
 
+// serve reloads the jobs when the config file changes or on SIGHUP, until ctx is done.
+func (w *CronCmd) serve(ctx context.Context, scheduler *cronScheduler) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	changes, err := configfile.Watch(ctx, w.Config)
+	if err != nil {
+		w.Logger.Warning("Not watching the cron config, reload with SIGHUP", "file", w.Config, "error", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			w.Logger.Info("Reloading cron jobs", "reason", "SIGHUP")
+			w.reload(scheduler)
+		case <-changes:
+			w.Logger.Info("Reloading cron jobs", "reason", "config changed")
+			w.reload(scheduler)
+		}
+	}
+}
+
+// reload applies the config file to the scheduler. An invalid file keeps the current jobs.
+func (w *CronCmd) reload(scheduler *cronScheduler) {
+	crons, err := w.parseCrons()
+	if err == nil {
+		err = scheduler.Apply(crons.Jobs)
+	}
+
+	if err != nil {
+		w.Logger.Error("Failed to reload cron jobs, keeping the current ones", "error", err)
+		return
+	}
+
+	w.Logger.Info("Reloaded cron jobs", "count", len(crons.Jobs))
+}
+
 func (w *CronCmd) parseCrons() (*CronJobs, error) {
 	if w.Config == "" {
 		return nil, fmt.Errorf("configuration file path is empty")
@@ -123,6 +190,10 @@ func (w *CronCmd) parseCrons() (*CronJobs, error) {
 		return nil, fmt.Errorf("no jobs found in configuration file '%s'", w.Config)
 	}
 
+	if err := cronJobs.Validate(time.Now()); err != nil {
+		return nil, fmt.Errorf("invalid config file '%s': %w", w.Config, err)
+	}
+
 	return cronJobs, nil
 }
 
@@ -143,18 +214,24 @@ func (w *CronCmd) parseConfigFile() (*CronJobs, error) {
 }
 
 type CronHandlerBuilder func(CronJob) CronHandler
-type CronHandler func() error
+type CronHandler func(ctx context.Context) error
 
 func (w *CronCmd) NoCronHandler() CronHandler {
-	return func() error {
+	return func(context.Context) error {
 		return fmt.Errorf("no cron handler defined")
 	}
 }
 
 func (w *CronCmd) buildCronHandler(definedCron DefinedCron, cronJob CronJob) CronHandler {
+	return w.buildCronHandlerWithOutput(definedCron, cronJob, nil)
+}
+
+// buildCronHandlerWithOutput builds the handler of the job; shell jobs copy their output to
+// output when it is set.
+func (w *CronCmd) buildCronHandlerWithOutput(definedCron DefinedCron, cronJob CronJob, output io.Writer) CronHandler {
 	switch cronJob.Type {
 	case "shell":
-		return w.runShell(cronJob)
+		return w.runShellCapture(cronJob, nil, output)
 	case "defined":
 		if handler, exists := definedCron[cronJob.Command]; exists {
 			return handler(cronJob)
@@ -164,21 +241,22 @@ func (w *CronCmd) buildCronHandler(definedCron DefinedCron, cronJob CronJob) Cro
 	return w.NoCronHandler()
 }
 
-func (w *CronCmd) runShell(cronJob CronJob) CronHandler {
-	return func() error {
-		parts := strings.Fields(cronJob.Command)
-
-		if len(parts) == 0 {
-			return fmt.Errorf("empty command")
+// runShellCapture runs the command of the job, copying its combined output to output when set.
+// The command is killed when ctx is done.
+func (w *CronCmd) runShellCapture(cronJob CronJob, env []string, output io.Writer) CronHandler {
+	return func(ctx context.Context) error {
+		execution, err := w.shellExecution(cronJob, env, w.shellData(cronJob, time.Now()))
+		if err != nil {
+			w.Logger.Error("Invalid shell command", "command", cronJob.Command, "error", err)
+			return fmt.Errorf("invalid shell command '%s': %w", cronJob.Command, err)
 		}
+		execution.Context = ctx
 
-		command := parts[0]
-		args := parts[1:]
+		result, err := w.Shell.RunCombinedOutput(execution)
 
-		_, err := w.Shell.Run(shell.RunnerExecutionArgs{
-			Command: command,
-			Args:    args,
-		})
+		if output != nil {
+			io.WriteString(output, result)
+		}
 
 		if err != nil {
 			w.Logger.Error("Failed to run shell command", "command", cronJob.Command, "error", err)
@@ -190,58 +268,96 @@ func (w *CronCmd) runShell(cronJob CronJob) CronHandler {
 	}
 }
 
+// buildDefinedCrons binds the builders of definedCronHandlers to the command.
 func (w *CronCmd) buildDefinedCrons() DefinedCron {
-	return map[string]CronHandlerBuilder{
-		"$set_random_wallpaper": func(cronJob CronJob) CronHandler {
-			return func() error {
-				hyprpaper := hyprland.NewHyprpaper(w.Logger, w.Shell)
-				hyprpaper.SetWallpaper(core.ResolvePath(cronJob.Args["path"].(string)))
-				return nil
-			}
-		},
-		"$update_lock_screen_phrase": func(cronJob CronJob) CronHandler {
-			return w.buildHyprlockHandler(cronJob)
-		},
+	defined := DefinedCron{}
+	for command, handler := range definedCronHandlers {
+		defined[command] = func(cronJob CronJob) CronHandler {
+			return handler.Build(w, cronJob)
+		}
 	}
+
+	return defined
 }
 
-func (w *CronCmd) buildHyprlockHandler(cronJob CronJob) CronHandler {
-	return func() error {
-		hyprlockCmd := HyprlockCmd{
-			HyprlandCmd: w.HyprlandCmd,
-			ConfigPath:  "~/.config/hypr/hyprlock.conf",
-			Jokes:       false,
-			Message:     "",
-			Format:      "👉 %s 🤪",
-			Provider:    []string{"reddit", "icanhazdadjoke"},
+func (w *CronCmd) buildWallpaperHandler(cronJob CronJob) CronHandler {
+	return func(ctx context.Context) error {
+		var args WallpaperArgs
+		if err := jobArgs(cronJob, &args); err != nil {
+			return err
 		}
 
-		if config, ok := cronJob.Args["config"]; ok {
-			hyprlockCmd.ConfigPath = core.ResolvePath(config.(string))
+		hyprpaperCmd := WallpaperCmd{
+			HyprlandCmd:        w.withContext(ctx),
+			Backend:            args.Backend,
+			AllMonitors:        args.AllMonitors,
+			MonitorPath:        map[string]string{},
+			Path:               args.Path,
+			Schedule:           args.Schedule.File,
+			schedule:           args.Schedule.Inline,
+			TransitionType:     args.Transition.Type,
+			TransitionStep:     args.Transition.Step,
+			TransitionFps:      args.Transition.Fps,
+			TransitionDuration: args.Transition.Duration,
+			Theme:              args.Theme,
 		}
+		maps.Copy(hyprpaperCmd.MonitorPath, args.Monitors)
 
-		if jokes, ok := cronJob.Args["jokes"]; ok {
-			hyprlockCmd.Jokes = jokes.(bool)
+		if err := hyprpaperCmd.setupWallpaper(); err != nil {
+			return err
 		}
 
-		if msg, ok := cronJob.Args["message"]; ok {
-			hyprlockCmd.Message = msg.(string)
+		wallpaperPath, err := hyprpaperCmd.wallpaperPath()
+		if err != nil {
+			return err
 		}
 
-		if fmtStr, ok := cronJob.Args["format"]; ok {
-			hyprlockCmd.Format = fmtStr.(string)
+		return hyprpaperCmd.RunSetWallpaper(wallpaperPath, "")
+	}
+}
+
+func (w *CronCmd) buildHyprlockHandler(cronJob CronJob) CronHandler {
+	return func(ctx context.Context) error {
+		var args LockScreenArgs
+		if err := jobArgs(cronJob, &args); err != nil {
+			return err
 		}
 
-		if provider, ok := cronJob.Args["provider"]; ok {
-			if providers, ok := provider.([]string); ok {
-				hyprlockCmd.Provider = providers
-			}
+		hyprlockCmd := HyprlockCmd{
+			HyprlandCmd: w.withContext(ctx),
+			ConfigPath:  core.ResolvePath(args.Config),
+			Jokes:       args.Jokes,
+			Message:     args.Message,
+			Format:      args.Format,
+			Provider:    args.Provider,
+			Providers:   args.Providers,
+			Label:       args.Label,
+			LabelIndex:  args.LabelIndex,
+			Filters: jokes.Options{
+				Blocklist: args.Blocklist,
+				MaxLength: args.MaxLength,
+				AllowNsfw: args.AllowNsfw,
+				Subreddit: args.Subreddits,
+				Flair:     args.Flairs,
+			},
+			Truncate: args.Truncate,
+			Newlines: args.Newlines,
+			Quotes:   args.Quotes,
 		}
 
-		return hyprlockCmd.Run(&cmd.Context{})
+		return hyprlockCmd.update(ctx)
 	}
 }
 
+// withContext returns the command with a shell runner whose commands are killed when ctx is
+// done, for the defined handlers to stop with their run.
+func (w *CronCmd) withContext(ctx context.Context) HyprlandCmd {
+	hyprlandCmd := w.HyprlandCmd
+	hyprlandCmd.Shell = shell.WithContext(w.Shell, ctx)
+
+	return hyprlandCmd
+}
+
 func (w *CronCmd) jobWrapper(logger core.Logger, fn func()) func() {
 	return func() {
 		defer func() {
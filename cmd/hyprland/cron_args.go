@@ -0,0 +1,228 @@
+package hyprland
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	decoderType  = reflect.TypeFor[cronArgDecoder]()
+)
+
+// cronArgsValidator is implemented by args structs with checks beyond their tags.
+type cronArgsValidator interface {
+	Validate() error
+}
+
+// cronArgDecoder is implemented by args that accept more than one YAML shape.
+type cronArgDecoder interface {
+	DecodeCronArg(value interface{}) error
+}
+
+// decodeCronArgs decodes the args of a job into the struct target points to. Fields are
+// matched by their yaml tag; default holds the value of an arg that is not given, enum the
+// accepted values and required marks args that must be given. Errors name the arg at fault.
+func decodeCronArgs(args map[string]interface{}, target any) error {
+	value := reflect.ValueOf(target).Elem()
+	if err := decodeCronStruct(args, value, ""); err != nil {
+		return err
+	}
+
+	if validator, ok := target.(cronArgsValidator); ok {
+		return validator.Validate()
+	}
+
+	return nil
+}
+
+func decodeCronStruct(args map[string]interface{}, value reflect.Value, prefix string) error {
+	known := map[string]bool{}
+
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		known[name] = true
+
+		arg, given := args[name]
+		if !given || arg == nil {
+			if _, required := field.Tag.Lookup("required"); required {
+				return fmt.Errorf("args.%s%s is required", prefix, name)
+			}
+
+			defaultValue, ok := field.Tag.Lookup("default")
+			switch {
+			case ok:
+				arg = defaultValue
+			case field.Type.Kind() == reflect.Struct && !reflect.PointerTo(field.Type).Implements(decoderType):
+				// a missing group still gets the defaults of its fields
+				arg = map[string]interface{}{}
+			default:
+				continue
+			}
+		}
+
+		if err := decodeCronArg(arg, value.Field(i), prefix+name); err != nil {
+			return err
+		}
+
+		if enum, ok := field.Tag.Lookup("enum"); ok {
+			accepted := strings.Split(enum, ",")
+			if text := fmt.Sprint(value.Field(i).Interface()); !slices.Contains(accepted, text) {
+				return fmt.Errorf("args.%s%s: invalid value '%s', expected one of %s", prefix, name, text, strings.Join(accepted, ", "))
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		if !known[name] {
+			return fmt.Errorf("args.%s%s: unknown arg", prefix, name)
+		}
+	}
+
+	return nil
+}
+
+// decodeCronArg sets field from a value decoded from YAML. Strings are accepted for every
+// kind, so defaults can be read from tags.
+func decodeCronArg(arg interface{}, field reflect.Value, name string) error {
+	invalid := func(expected string) error {
+		return fmt.Errorf("args.%s: expected %s, got %s", name, expected, describeCronArg(arg))
+	}
+
+	if decoder, ok := field.Addr().Interface().(cronArgDecoder); ok {
+		if err := decoder.DecodeCronArg(arg); err != nil {
+			return fmt.Errorf("args.%s: %w", name, err)
+		}
+		return nil
+	}
+
+	text, isText := arg.(string)
+
+	switch {
+	case field.Type() == durationType:
+		switch arg := arg.(type) {
+		case string:
+			duration, err := time.ParseDuration(arg)
+			if err != nil {
+				return invalid("a duration such as 30m")
+			}
+			field.SetInt(int64(duration))
+		case int:
+			field.SetInt(int64(time.Duration(arg) * time.Second))
+		default:
+			return invalid("a duration such as 30m")
+		}
+	case field.Kind() == reflect.String:
+		if !isText {
+			return invalid("a string")
+		}
+		field.SetString(text)
+	case field.Kind() == reflect.Bool:
+		switch arg := arg.(type) {
+		case bool:
+			field.SetBool(arg)
+		case string:
+			parsed, err := strconv.ParseBool(arg)
+			if err != nil {
+				return invalid("true or false")
+			}
+			field.SetBool(parsed)
+		default:
+			return invalid("true or false")
+		}
+	case field.Kind() == reflect.Int:
+		switch arg := arg.(type) {
+		case int:
+			field.SetInt(int64(arg))
+		case string:
+			parsed, err := strconv.Atoi(arg)
+			if err != nil {
+				return invalid("an integer")
+			}
+			field.SetInt(int64(parsed))
+		default:
+			return invalid("an integer")
+		}
+	case field.Kind() == reflect.Float64:
+		switch arg := arg.(type) {
+		case int:
+			field.SetFloat(float64(arg))
+		case float64:
+			field.SetFloat(arg)
+		case string:
+			parsed, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return invalid("a number")
+			}
+			field.SetFloat(parsed)
+		default:
+			return invalid("a number")
+		}
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var items []string
+		switch arg := arg.(type) {
+		case string:
+			items = strings.Split(arg, ",")
+		case []interface{}:
+			for i, item := range arg {
+				item, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("args.%s[%d]: expected a string, got %s", name, i, describeCronArg(arg[i]))
+				}
+				items = append(items, item)
+			}
+		default:
+			return invalid("a string or a list of strings")
+		}
+		field.Set(reflect.ValueOf(items))
+	case field.Kind() == reflect.Map && field.Type().Elem().Kind() == reflect.String:
+		entries, ok := arg.(map[string]interface{})
+		if !ok {
+			return invalid("a map of strings")
+		}
+		result := map[string]string{}
+		for key, item := range entries {
+			item, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("args.%s.%s: expected a string, got %s", name, key, describeCronArg(entries[key]))
+			}
+			result[key] = item
+		}
+		field.Set(reflect.ValueOf(result))
+	case field.Kind() == reflect.Struct:
+		entries, ok := arg.(map[string]interface{})
+		if !ok {
+			return invalid("a map")
+		}
+		return decodeCronStruct(entries, field, name+".")
+	default:
+		return fmt.Errorf("args.%s: unsupported type %s", name, field.Type())
+	}
+
+	return nil
+}
+
+// describeCronArg names the YAML type of a value in errors.
+func describeCronArg(arg interface{}) string {
+	switch arg := arg.(type) {
+	case string:
+		return fmt.Sprintf("string '%s'", arg)
+	case bool, int, float64:
+		return fmt.Sprintf("%T %v", arg, arg)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a map"
+	default:
+		return fmt.Sprintf("%T", arg)
+	}
+}
@@ -0,0 +1,85 @@
+package hyprland
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testCronArgs struct {
+	Mode     string            `yaml:"mode" enum:"fast,slow" default:"fast"`
+	Path     string            `yaml:"path" required:""`
+	Count    int               `yaml:"count" default:"3"`
+	Ratio    float64           `yaml:"ratio"`
+	Enabled  bool              `yaml:"enabled" default:"true"`
+	Every    time.Duration     `yaml:"every" default:"1m"`
+	Tags     []string          `yaml:"tags"`
+	Monitors map[string]string `yaml:"monitors"`
+	Nested   struct {
+		Fps int `yaml:"fps" default:"30"`
+	} `yaml:"nested"`
+}
+
+func TestDecodeCronArgs(t *testing.T) {
+	defaults := testCronArgs{Mode: "fast", Path: "/tmp", Count: 3, Enabled: true, Every: time.Minute}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		want    func(args *testCronArgs)
+		wantErr string
+	}{
+		{"Defaults", map[string]interface{}{"path": "/tmp"}, func(*testCronArgs) {}, ""},
+		{
+			name: "Values",
+			args: map[string]interface{}{
+				"path": "/tmp", "mode": "slow", "count": 5, "ratio": 2, "enabled": false, "every": "2h",
+				"tags": []interface{}{"a", "b"}, "monitors": map[string]interface{}{"eDP-1": "a.png"},
+				"nested": map[string]interface{}{"fps": 60},
+			},
+			want: func(args *testCronArgs) {
+				args.Mode, args.Count, args.Ratio, args.Enabled, args.Every = "slow", 5, 2, false, 2*time.Hour
+				args.Tags = []string{"a", "b"}
+				args.Monitors = map[string]string{"eDP-1": "a.png"}
+				args.Nested.Fps = 60
+			},
+		},
+		{"SingleTag", map[string]interface{}{"path": "/tmp", "tags": "a"}, func(args *testCronArgs) { args.Tags = []string{"a"} }, ""},
+		{"Seconds", map[string]interface{}{"path": "/tmp", "every": 90}, func(args *testCronArgs) { args.Every = 90 * time.Second }, ""},
+		{"Required", map[string]interface{}{}, nil, "args.path is required"},
+		{"Unknown", map[string]interface{}{"path": "/tmp", "colour": "red"}, nil, "args.colour: unknown arg"},
+		{"Enum", map[string]interface{}{"path": "/tmp", "mode": "medium"}, nil, "args.mode: invalid value 'medium', expected one of fast, slow"},
+		{"WrongType", map[string]interface{}{"path": "/tmp", "count": "many"}, nil, "args.count: expected an integer, got string 'many'"},
+		{"WrongString", map[string]interface{}{"path": 3}, nil, "args.path: expected a string, got int 3"},
+		{"WrongList", map[string]interface{}{"path": "/tmp", "tags": []interface{}{"a", 1}}, nil, "args.tags[1]: expected a string, got int 1"},
+		{"WrongDuration", map[string]interface{}{"path": "/tmp", "every": "soon"}, nil, "args.every: expected a duration such as 30m, got string 'soon'"},
+		{"Nested", map[string]interface{}{"path": "/tmp", "nested": map[string]interface{}{"fps": true}}, nil, "args.nested.fps: expected an integer, got bool true"},
+		{"NestedUnknown", map[string]interface{}{"path": "/tmp", "nested": map[string]interface{}{"step": 1}}, nil, "args.nested.step: unknown arg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args testCronArgs
+			err := decodeCronArgs(tt.args, &args)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			want := defaults
+			want.Nested.Fps = 30
+			tt.want(&want)
+
+			if !reflect.DeepEqual(args, want) {
+				t.Errorf("Expected %+v, got %+v", want, args)
+			}
+		})
+	}
+}
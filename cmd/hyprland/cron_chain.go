@@ -0,0 +1,153 @@
+package hyprland
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// followUp reports a job without a schedule of its own, which only runs after other jobs.
+func (c CronJob) followUp() bool {
+	return c.Interval == 0 && c.Schedule == "" && c.At == ""
+}
+
+// triggeredJobs returns the names of the jobs started by other jobs, through after, on_success
+// or on_failure.
+func (c *CronJobs) triggeredJobs() map[string]bool {
+	triggered := map[string]bool{}
+
+	for _, job := range c.Jobs {
+		if len(job.After) > 0 {
+			triggered[job.Name] = true
+		}
+		for _, name := range slices.Concat(job.OnSuccess, job.OnFailure) {
+			triggered[name] = true
+		}
+	}
+
+	return triggered
+}
+
+// validateChains checks that after, on_success and on_failure name declared jobs, and that
+// following them never leads back to a job.
+func (c *CronJobs) validateChains() error {
+	names := map[string]bool{}
+	for _, job := range c.Jobs {
+		if job.Name != "" {
+			names[job.Name] = true
+		}
+	}
+
+	// next holds the edges from a job to the jobs that run after it
+	next := map[string][]string{}
+	for i, job := range c.Jobs {
+		references := map[string][]string{"after": job.After, "on_success": job.OnSuccess, "on_failure": job.OnFailure}
+		for _, field := range []string{"after", "on_success", "on_failure"} {
+			for _, name := range references[field] {
+				if !names[name] {
+					return fmt.Errorf("%s: %s names unknown job '%s'", job.label(i), field, name)
+				}
+			}
+		}
+
+		if (len(job.OnSuccess) > 0 || len(job.OnFailure) > 0 || len(job.After) > 0) && job.Name == "" {
+			return fmt.Errorf("%s: jobs in a chain need a name", job.label(i))
+		}
+
+		for _, name := range job.After {
+			next[name] = append(next[name], job.Name)
+		}
+		next[job.Name] = append(next[job.Name], slices.Concat(job.OnSuccess, job.OnFailure)...)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := map[string]int{}
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			cycle := append(path[slices.Index(path, name):], name)
+			return fmt.Errorf("cron jobs form a cycle: %s", strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+
+		marks[name] = visiting
+		path = append(path, name)
+		for _, following := range next[name] {
+			if err := visit(following); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+
+		return nil
+	}
+
+	for _, job := range c.Jobs {
+		if job.Name == "" {
+			continue
+		}
+		if err := visit(job.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chain starts the jobs that follow a run of job: its on_success or on_failure jobs, and the
+// jobs whose after list it completes.
+func (s *cronScheduler) chain(job CronJob, err error) {
+	following := job.OnSuccess
+	if err != nil {
+		following = job.OnFailure
+	}
+
+	s.mu.Lock()
+	names := slices.Clone(following)
+	if err == nil && job.Name != "" {
+		for key, scheduled := range s.jobs {
+			after := scheduled.config.After
+			if !slices.Contains(after, job.Name) {
+				continue
+			}
+
+			state := s.state(key)
+			if state.completed == nil {
+				state.completed = map[string]bool{}
+			}
+			state.completed[job.Name] = true
+
+			if !slices.ContainsFunc(after, func(name string) bool { return !state.completed[name] }) {
+				state.completed = nil
+				names = append(names, key)
+			}
+		}
+	}
+
+	var jobs []scheduledJob
+	for _, name := range names {
+		if scheduled, ok := s.jobs[name]; ok && !slices.ContainsFunc(jobs, func(j scheduledJob) bool { return j.index == scheduled.index }) {
+			jobs = append(jobs, scheduled)
+		}
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(jobs, func(a, b scheduledJob) int { return a.index - b.index })
+
+	// the runs take the lock to record themselves, so it is not held here
+	for _, scheduled := range jobs {
+		s.cron.Logger.Debug("Starting follow-up cron job", "name", scheduled.config.Name, "after", job.Name)
+		if err := s.runOnce(scheduled.config.Name, scheduled.config, false); err != nil {
+			s.cron.Logger.Warning("Failed to start follow-up cron job", "name", scheduled.config.Name, "error", err)
+		}
+	}
+}
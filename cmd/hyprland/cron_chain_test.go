@@ -0,0 +1,218 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCronJobs_validateChains(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobs    []CronJob
+		wantErr string
+	}{
+		{
+			name: "Chain",
+			jobs: []CronJob{
+				{Name: "wallpaper", Interval: time.Hour, OnFailure: []string{"alert"}},
+				{Name: "theme", After: []string{"wallpaper"}, OnSuccess: []string{"waybar"}},
+				{Name: "waybar"},
+				{Name: "alert"},
+			},
+		},
+		{
+			name: "Diamond",
+			jobs: []CronJob{
+				{Name: "a", Interval: time.Hour, OnSuccess: []string{"b", "c"}},
+				{Name: "b"},
+				{Name: "c"},
+				{Name: "d", After: []string{"b", "c"}},
+			},
+		},
+		{
+			name:    "UnknownAfter",
+			jobs:    []CronJob{{Name: "theme", After: []string{"wallpaper"}}},
+			wantErr: "cron job 'theme': after names unknown job 'wallpaper'",
+		},
+		{
+			name:    "UnknownOnFailure",
+			jobs:    []CronJob{{Name: "backup", Interval: time.Hour, OnFailure: []string{"alert"}}},
+			wantErr: "cron job 'backup': on_failure names unknown job 'alert'",
+		},
+		{
+			name:    "Unnamed",
+			jobs:    []CronJob{{Name: "a", Interval: time.Hour}, {Interval: time.Hour, After: []string{"a"}}},
+			wantErr: "cron job #2: jobs in a chain need a name",
+		},
+		{
+			name:    "Self",
+			jobs:    []CronJob{{Name: "a", Interval: time.Hour, OnFailure: []string{"a"}}},
+			wantErr: "cron jobs form a cycle: a -> a",
+		},
+		{
+			name: "Cycle",
+			jobs: []CronJob{
+				{Name: "a", Interval: time.Hour, OnSuccess: []string{"b"}},
+				{Name: "b", OnSuccess: []string{"c"}},
+				{Name: "c", After: []string{"x"}, OnSuccess: []string{"b"}},
+				{Name: "x", Interval: time.Hour},
+			},
+			wantErr: "cron jobs form a cycle: b -> c -> b",
+		},
+		{
+			name: "CycleThroughAfter",
+			jobs: []CronJob{
+				{Name: "a", After: []string{"b"}},
+				{Name: "b", After: []string{"a"}},
+			},
+			wantErr: "cron jobs form a cycle: a -> b -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&CronJobs{Jobs: tt.jobs}).validateChains()
+
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCronJobs_ValidateFollowUps(t *testing.T) {
+	now := time.Now()
+
+	jobs := &CronJobs{Jobs: []CronJob{
+		{Name: "wallpaper", Type: "defined", Command: "$set_random_wallpaper", Args: map[string]interface{}{"path": "~/Pictures"}, Interval: time.Hour, OnSuccess: []string{"waybar"}},
+		{Name: "theme", Type: "shell", Command: "true", After: []string{"wallpaper"}},
+		{Name: "waybar", Type: "shell", Command: "true"},
+	}}
+	if err := jobs.Validate(now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	jobs.Jobs = append(jobs.Jobs, CronJob{Name: "orphan", Type: "shell", Command: "true"})
+	if err := jobs.Validate(now); err == nil || !strings.Contains(err.Error(), "cron job 'orphan': one of interval, schedule or at is required") {
+		t.Errorf("Expected a missing schedule error, got %v", err)
+	}
+}
+
+func TestCronScheduler_chain(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	ran := make(chan string, 10)
+	scheduler.defined = DefinedCron{
+		"$step": func(job CronJob) CronHandler {
+			return func(context.Context) error {
+				ran <- job.Name
+				if job.Args["fail"] == true {
+					return errors.New("failed")
+				}
+				return nil
+			}
+		},
+	}
+
+	step := func(job CronJob) CronJob {
+		job.Type = "defined"
+		job.Command = "$step"
+		return job
+	}
+
+	err := scheduler.Apply([]CronJob{
+		step(CronJob{Name: "wallpaper", Interval: time.Hour}),
+		step(CronJob{Name: "theme", After: []string{"wallpaper"}, OnSuccess: []string{"waybar"}}),
+		step(CronJob{Name: "waybar"}),
+		step(CronJob{Name: "backup", Interval: time.Hour, Args: map[string]interface{}{"fail": true}, OnSuccess: []string{"waybar"}, OnFailure: []string{"alert"}}),
+		step(CronJob{Name: "alert"}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	scheduler.Start()
+
+	expect := func(want ...string) {
+		t.Helper()
+		for _, name := range want {
+			select {
+			case got := <-ran:
+				if got != name {
+					t.Fatalf("Expected %s to run, got %s", name, got)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Timed out waiting for %s", name)
+			}
+		}
+
+		select {
+		case got := <-ran:
+			t.Fatalf("Unexpected run of %s", got)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if err := scheduler.RunNow("wallpaper"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expect("wallpaper", "theme", "waybar")
+
+	if err := scheduler.RunNow("backup"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expect("backup", "alert")
+
+	for _, status := range scheduler.List() {
+		if status.Name == "theme" && (status.Schedule != "after wallpaper" || !status.NextRun.IsZero()) {
+			t.Errorf("Unexpected status of a follow-up job: %+v", status)
+		}
+	}
+
+	if names := scheduledNames(scheduler); !slices.Equal(names, []string{"backup", "wallpaper"}) {
+		t.Errorf("Expected only the jobs with a schedule in gocron, got %v", names)
+	}
+}
+
+func TestCronScheduler_chainScheduledAfter(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	var reports atomic.Int32
+	scheduler.defined = DefinedCron{
+		"$report": func(CronJob) CronHandler {
+			return func(context.Context) error {
+				reports.Add(1)
+				return nil
+			}
+		},
+	}
+
+	report := CronJob{Name: "report", Type: "defined", Command: "$report", Interval: time.Hour, After: []string{"backup", "sync"}}
+	err := scheduler.Apply([]CronJob{
+		{Name: "backup", Type: "shell", Command: "true", Interval: time.Hour},
+		{Name: "sync", Type: "shell", Command: "true", Interval: time.Hour},
+		report,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	scheduler.Start()
+
+	scheduler.chain(CronJob{Name: "backup"}, nil)
+
+	// a scheduled run of report uses up the success of backup
+	scheduler.task("report", report, false)(t.Context())
+	if got := reports.Load(); got != 1 {
+		t.Fatalf("Expected the scheduled run, got %d runs", got)
+	}
+
+	scheduler.chain(CronJob{Name: "sync"}, nil)
+	time.Sleep(100 * time.Millisecond)
+	if got := reports.Load(); got != 1 {
+		t.Errorf("Expected report to wait for backup again, got %d runs", got)
+	}
+}
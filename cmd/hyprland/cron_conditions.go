@@ -0,0 +1,210 @@
+package hyprland
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+	"github.com/williampsena/ebenezer-cli/internal/system"
+)
+
+const (
+	PowerAC      = "ac"
+	PowerBattery = "battery"
+)
+
+// readPower reads the power supplies for the power condition and the battery check; tests
+// stub it.
+var readPower = func() (system.PowerState, error) {
+	return system.ReadPower(system.PowerSupplyDir)
+}
+
+// CronConditions are checked before each scheduled run; the run is skipped unless all of
+// them hold. Runs started with `cron run` ignore them.
+type CronConditions struct {
+	// Power is ac or battery.
+	Power string `yaml:"power"`
+	// Locked requires the screen to be locked (true) or unlocked (false), from the hyprlock process.
+	Locked *bool `yaml:"locked"`
+	// IdleAbove and IdleBelow bound the idle time of the session, as reported by logind.
+	IdleAbove time.Duration `yaml:"idle_above"`
+	IdleBelow time.Duration `yaml:"idle_below"`
+	// Monitor requires a monitor with this name or description to be connected.
+	Monitor string `yaml:"monitor"`
+	// From and To restrict the runs to a daily window, "HH:MM"; it may wrap around midnight.
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+	// Command is run through sh; the job runs when it exits with 0.
+	Command string `yaml:"command"`
+}
+
+// Validate checks the conditions when the config file is loaded.
+func (c *CronConditions) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	switch c.Power {
+	case "", PowerAC, PowerBattery:
+	default:
+		return fmt.Errorf("invalid when.power '%s': expected %s or %s", c.Power, PowerAC, PowerBattery)
+	}
+
+	if c.IdleAbove < 0 || c.IdleBelow < 0 {
+		return fmt.Errorf("when.idle_above and when.idle_below must not be negative")
+	}
+
+	if c.IdleAbove > 0 && c.IdleBelow > 0 && c.IdleAbove >= c.IdleBelow {
+		return fmt.Errorf("when.idle_above %s must be shorter than when.idle_below %s", c.IdleAbove, c.IdleBelow)
+	}
+
+	if _, err := c.window(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CronConditions) window() (*core.TimeWindow, error) {
+	if c.From == "" && c.To == "" {
+		return nil, nil
+	}
+
+	if c.From == "" || c.To == "" {
+		return nil, fmt.Errorf("when.from and when.to must be set together")
+	}
+
+	window, err := core.ParseTimeWindow(c.From, c.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid when window: %w", err)
+	}
+
+	if window.UsesSun() {
+		return nil, fmt.Errorf("invalid when window: sunrise and sunset are not supported, use HH:MM")
+	}
+
+	return &window, nil
+}
+
+// conditionsMet checks the conditions of a job, returning why the run is skipped when they
+// do not hold. A condition that cannot be checked does not hold.
+func (w *CronCmd) conditionsMet(conditions *CronConditions, now time.Time) (bool, string) {
+	if conditions == nil {
+		return true, ""
+	}
+
+	checks := []func(*CronConditions, time.Time) (bool, string, error){
+		w.checkWindow,
+		w.checkPower,
+		w.checkLocked,
+		w.checkIdle,
+		w.checkMonitor,
+		w.checkCommand,
+	}
+
+	for _, check := range checks {
+		met, reason, err := check(conditions, now)
+		if err != nil {
+			return false, err.Error()
+		}
+		if !met {
+			return false, reason
+		}
+	}
+
+	return true, ""
+}
+
+func (w *CronCmd) checkWindow(c *CronConditions, now time.Time) (bool, string, error) {
+	window, err := c.window()
+	if err != nil || window == nil {
+		return true, "", err
+	}
+
+	inside, err := window.Contains(now, nil)
+	return inside, fmt.Sprintf("outside %s-%s", c.From, c.To), err
+}
+
+func (w *CronCmd) checkPower(c *CronConditions, _ time.Time) (bool, string, error) {
+	if c.Power == "" {
+		return true, "", nil
+	}
+
+	state, err := readPower()
+	if err != nil {
+		return false, "", err
+	}
+
+	if c.Power == PowerBattery {
+		return state.OnBattery(), "on AC power", nil
+	}
+
+	return state.OnAC, "on battery", nil
+}
+
+func (w *CronCmd) checkLocked(c *CronConditions, _ time.Time) (bool, string, error) {
+	if c.Locked == nil {
+		return true, "", nil
+	}
+
+	locked := w.ProcessManager.IsProcessRunning("hyprlock")
+	if *c.Locked {
+		return locked, "screen unlocked", nil
+	}
+
+	return !locked, "screen locked", nil
+}
+
+func (w *CronCmd) checkIdle(c *CronConditions, now time.Time) (bool, string, error) {
+	if c.IdleAbove == 0 && c.IdleBelow == 0 {
+		return true, "", nil
+	}
+
+	idle, err := system.IdleTime(w.Shell, now)
+	if err != nil {
+		return false, "", err
+	}
+
+	switch {
+	case c.IdleAbove > 0 && idle < c.IdleAbove:
+		return false, fmt.Sprintf("idle for %s, less than %s", idle.Round(time.Second), c.IdleAbove), nil
+	case c.IdleBelow > 0 && idle >= c.IdleBelow:
+		return false, fmt.Sprintf("idle for %s, more than %s", idle.Round(time.Second), c.IdleBelow), nil
+	}
+
+	return true, "", nil
+}
+
+func (w *CronCmd) checkMonitor(c *CronConditions, _ time.Time) (bool, string, error) {
+	if c.Monitor == "" {
+		return true, "", nil
+	}
+
+	monitors, err := w.IPC.Monitors()
+	if err != nil {
+		return false, "", err
+	}
+
+	connected := slices.ContainsFunc(monitors, func(monitor hyprland.Monitor) bool {
+		return monitor.Name == c.Monitor || strings.Contains(monitor.Description, c.Monitor)
+	})
+
+	return connected, fmt.Sprintf("monitor %s not connected", c.Monitor), nil
+}
+
+func (w *CronCmd) checkCommand(c *CronConditions, _ time.Time) (bool, string, error) {
+	if c.Command == "" {
+		return true, "", nil
+	}
+
+	_, err := w.Shell.Run(shell.RunnerExecutionArgs{
+		Command: "sh",
+		Args:    []string{"-c", c.Command},
+	})
+
+	return err == nil, fmt.Sprintf("'%s' failed", c.Command), nil
+}
@@ -0,0 +1,132 @@
+package hyprland
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/process"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+	"github.com/williampsena/ebenezer-cli/internal/system"
+)
+
+func TestCronConditions_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions *CronConditions
+		wantErr    string
+	}{
+		{"Nil", nil, ""},
+		{"Valid", &CronConditions{Power: PowerAC, IdleAbove: time.Minute, IdleBelow: time.Hour, From: "22:00", To: "06:00"}, ""},
+		{"BadPower", &CronConditions{Power: "solar"}, "invalid when.power 'solar'"},
+		{"NegativeIdle", &CronConditions{IdleAbove: -time.Minute}, "must not be negative"},
+		{"IdleRange", &CronConditions{IdleAbove: time.Hour, IdleBelow: time.Minute}, "must be shorter than"},
+		{"HalfWindow", &CronConditions{From: "08:00"}, "must be set together"},
+		{"BadWindow", &CronConditions{From: "8am", To: "10:00"}, "invalid when window"},
+		{"SunWindow", &CronConditions{From: "sunset", To: "sunrise"}, "sunrise and sunset are not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.conditions.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCronCmd_conditionsMet(t *testing.T) {
+	stubPower(t, system.PowerState{Batteries: []system.Battery{{Name: "BAT0", Status: "Discharging"}}})
+
+	cronCmd := &CronCmd{}
+	cronCmd.SetupContext(&cmd.Context{Debug: false})
+	cronCmd.IPC = hyprland.NewIPCClientMock(cronCmd.Logger, []hyprland.Monitor{{Name: "eDP-1", Description: "BOE 0x0BCA"}})
+	cronCmd.injectProcessManager(process.NewProcessManagerMock([]string{"hyprlock"}, nil))
+	cronCmd.Shell = shell.NewRunnerMock(cronCmd.Logger, []string{"sh"}, []string{"loginctl"}, nil)
+
+	yes, no := true, false
+	evening := time.Date(2024, time.March, 4, 23, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		conditions *CronConditions
+		met        bool
+		reason     string
+	}{
+		{"None", nil, true, ""},
+		{"OnBattery", &CronConditions{Power: PowerBattery}, true, ""},
+		{"OnAC", &CronConditions{Power: PowerAC}, false, "on battery"},
+		{"Locked", &CronConditions{Locked: &yes}, true, ""},
+		{"Unlocked", &CronConditions{Locked: &no}, false, "screen locked"},
+		{"MonitorByName", &CronConditions{Monitor: "eDP-1"}, true, ""},
+		{"MonitorByDescription", &CronConditions{Monitor: "BOE"}, true, ""},
+		{"MonitorMissing", &CronConditions{Monitor: "HDMI-A-1"}, false, "monitor HDMI-A-1 not connected"},
+		{"InsideWindow", &CronConditions{From: "22:00", To: "06:00"}, true, ""},
+		{"OutsideWindow", &CronConditions{From: "08:00", To: "18:00"}, false, "outside 08:00-18:00"},
+		{"Command", &CronConditions{Command: "pgrep steam"}, true, ""},
+		{"IdleUnknown", &CronConditions{IdleAbove: time.Minute}, false, "failed to read the idle hint"},
+		{"AllMustHold", &CronConditions{Power: PowerBattery, Monitor: "DP-2"}, false, "monitor DP-2 not connected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			met, reason := cronCmd.conditionsMet(tt.conditions, evening)
+			if met != tt.met {
+				t.Errorf("Expected met %v, got %v (%s)", tt.met, met, reason)
+			}
+
+			if !strings.Contains(reason, tt.reason) {
+				t.Errorf("Expected reason containing %q, got %q", tt.reason, reason)
+			}
+		})
+	}
+}
+
+func TestCronScheduler_SkipsUnmetConditions(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	runs := make(chan struct{}, 10)
+	scheduler.defined = DefinedCron{
+		"$count": func(CronJob) CronHandler {
+			return func(context.Context) error {
+				runs <- struct{}{}
+				return nil
+			}
+		},
+	}
+
+	unlocked := false
+	scheduler.cron.injectProcessManager(process.NewProcessManagerMock([]string{"hyprlock"}, nil))
+
+	job := CronJob{Name: "phrase", Type: "defined", Command: "$count", Interval: time.Hour, When: &CronConditions{Locked: &unlocked}}
+	if err := scheduler.Apply([]CronJob{job}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// a scheduled run while the screen is locked is skipped
+	scheduler.task("phrase", job, false)(t.Context())
+	select {
+	case <-runs:
+		t.Fatal("Expected the run to be skipped while locked")
+	default:
+	}
+
+	// `cron run` ignores the conditions
+	scheduler.task("phrase", job, true)(t.Context())
+	select {
+	case <-runs:
+	default:
+		t.Fatal("Expected a forced run to ignore the conditions")
+	}
+}
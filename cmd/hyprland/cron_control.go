@@ -0,0 +1,206 @@
+package hyprland
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	formatters "github.com/williampsena/ebenezer-cli/internal/cmd/formatters"
+	"github.com/williampsena/ebenezer-cli/internal/control"
+)
+
+type CronGroup struct {
+	Daemon   CronCmd         `cmd:"" default:"withargs" help:"Run the cron jobs (default)"`
+	List     CronListCmd     `cmd:"" help:"List the jobs of the running cron daemon"`
+	Run      CronRunCmd      `cmd:"" help:"Run a job of the cron daemon now"`
+	Pause    CronPauseCmd    `cmd:"" help:"Pause the scheduled runs of a job"`
+	Resume   CronResumeCmd   `cmd:"" help:"Resume a paused job"`
+	Validate CronValidateCmd `cmd:"" help:"Check a cron configuration file"`
+	History  CronHistoryCmd  `cmd:"" help:"Show the recent runs of the cron jobs"`
+	Schema   CronSchemaCmd   `cmd:"" help:"Print a JSON Schema of cron.yaml for editors"`
+}
+
+// cronSocketPath returns socket, or the default control socket of the cron daemon.
+func cronSocketPath(socket string) string {
+	if socket == "" {
+		return control.SocketPath("cron")
+	}
+
+	return socket
+}
+
+// CronClientCmd talks to the running cron daemon through its control socket.
+type CronClientCmd struct {
+	HyprlandCmd
+	Socket string `help:"Control socket of the cron daemon (default: $XDG_RUNTIME_DIR/ebenezer/cron.sock)" default:""`
+}
+
+func (c *CronClientCmd) call(request control.Request, result any) error {
+	if err := control.Call(cronSocketPath(c.Socket), request, result); err != nil {
+		c.Logger.Error("Cron daemon request failed", "command", request.Command, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+type CronListCmd struct {
+	CronClientCmd
+}
+
+func (c *CronListCmd) Run(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	var statuses []CronJobStatus
+	if err := c.call(control.Request{Command: "list"}, &statuses); err != nil {
+		return err
+	}
+
+	if len(statuses) == 0 {
+		return formatters.WriteToStdout("No cron jobs scheduled\n")
+	}
+
+	lines := []string{fmt.Sprintf("%-24s %-24s %-19s %-19s %s", "NAME", "SCHEDULE", "NEXT RUN", "LAST RUN", "LAST RESULT")}
+	for _, status := range statuses {
+		next := formatRunTime(status.NextRun)
+		if status.Paused {
+			next = "paused"
+		}
+
+		result := status.LastResult
+		switch {
+		case status.Running:
+			result = "running"
+		case result == "":
+			result = "-"
+		}
+
+		lines = append(lines, fmt.Sprintf("%-24s %-24s %-19s %-19s %s", status.Name, status.Schedule, next, formatRunTime(status.LastRun), result))
+	}
+
+	return formatters.WriteToStdout(strings.Join(lines, "\n") + "\n")
+}
+
+func formatRunTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+type CronRunCmd struct {
+	CronClientCmd
+	Name string `arg:"" help:"Job to run"`
+}
+
+func (c *CronRunCmd) Run(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	if err := c.call(control.Request{Command: "run", Target: c.Name}, nil); err != nil {
+		return err
+	}
+
+	return formatters.WriteToStdout(fmt.Sprintf("Triggered %s\n", c.Name))
+}
+
+type CronPauseCmd struct {
+	CronClientCmd
+	Name string `arg:"" help:"Job to pause"`
+}
+
+func (c *CronPauseCmd) Run(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	if err := c.call(control.Request{Command: "pause", Target: c.Name}, nil); err != nil {
+		return err
+	}
+
+	return formatters.WriteToStdout(fmt.Sprintf("Paused %s\n", c.Name))
+}
+
+type CronResumeCmd struct {
+	CronClientCmd
+	Name string `arg:"" help:"Job to resume"`
+}
+
+func (c *CronResumeCmd) Run(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	if err := c.call(control.Request{Command: "resume", Target: c.Name}, nil); err != nil {
+		return err
+	}
+
+	return formatters.WriteToStdout(fmt.Sprintf("Resumed %s\n", c.Name))
+}
+
+type CronValidateCmd struct {
+	HyprlandCmd
+	Config string `arg:"" help:"Path to the configuration file" default:"~/.config/hypr/cron.yaml"`
+}
+
+func (c *CronValidateCmd) Run(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	cronCmd := &CronCmd{HyprlandCmd: c.HyprlandCmd, Config: c.Config}
+	crons, err := cronCmd.parseCrons()
+	if err != nil {
+		c.Logger.Error("Invalid cron configuration", "error", err)
+		return err
+	}
+
+	lines := []string{fmt.Sprintf("%s is valid, %d jobs:", cronCmd.Config, len(crons.Jobs))}
+	for i, job := range crons.Jobs {
+		lines = append(lines, fmt.Sprintf("  %-24s %s", jobKey(i, job), job.describe()))
+	}
+
+	return formatters.WriteToStdout(strings.Join(lines, "\n") + "\n")
+}
+
+type CronHistoryCmd struct {
+	HyprlandCmd
+	Name    string `arg:"" optional:"" help:"Only show the runs of this job"`
+	Limit   int    `help:"Number of runs to show, 0 for all" default:"20"`
+	Output  bool   `help:"Show the captured output of each run" default:"false"`
+	History string `help:"Run history file (default: ~/.local/state/ebenezer/cron-history.jsonl)" default:""`
+}
+
+func (c *CronHistoryCmd) Run(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	runs, err := NewCronHistory(c.History).Read(c.Name, c.Limit)
+	if err != nil {
+		c.Logger.Error("Failed to read the cron history", "error", err)
+		return err
+	}
+
+	if len(runs) == 0 {
+		return formatters.WriteToStdout("No cron runs recorded\n")
+	}
+
+	lines := []string{fmt.Sprintf("%-19s %-24s %-12s %10s %s", "START", "NAME", "RESULT", "DURATION", "ATTEMPTS")}
+	for _, run := range runs {
+		result := "ok"
+		if run.Error != "" {
+			result = fmt.Sprintf("exit %d", run.ExitCode)
+		}
+		if run.Forced {
+			result += " manual"
+		}
+
+		lines = append(lines, fmt.Sprintf("%-19s %-24s %-12s %10s %d", formatRunTime(run.Start), run.Job, result, run.Duration(), run.Attempts))
+
+		if run.Error != "" {
+			lines = append(lines, "  error: "+run.Error)
+		}
+
+		if c.Output && run.Output != "" {
+			for line := range strings.SplitSeq(strings.TrimRight(run.Output, "\n"), "\n") {
+				lines = append(lines, "  | "+line)
+			}
+		}
+	}
+
+	return formatters.WriteToStdout(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,407 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/cmd/desktop"
+	jokes "github.com/williampsena/ebenezer-cli/internal/jokes"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+	"github.com/williampsena/ebenezer-cli/internal/system"
+	"github.com/williampsena/ebenezer-cli/internal/wallpaper"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// WallpaperArgs are the args of $set_random_wallpaper.
+type WallpaperArgs struct {
+	Path        string               `yaml:"path" help:"Directory of wallpapers"`
+	Schedule    WallpaperScheduleArg `yaml:"schedule" help:"Schedule file, or a schedule written inline, picking the directory from the time of day"`
+	Backend     string               `yaml:"backend" help:"Wallpaper backend" enum:"auto,hyprpaper,swww,swaybg,feh" default:"auto"`
+	AllMonitors bool                 `yaml:"all_monitors" help:"Set a wallpaper on every monitor" default:"true"`
+	Monitors    map[string]string    `yaml:"monitors" help:"Image or directory per monitor name"`
+	Transition  struct {
+		Type     string  `yaml:"type" help:"swww transition type (simple, fade, grow, wipe, ...)"`
+		Step     int     `yaml:"step" help:"swww transition step"`
+		Fps      int     `yaml:"fps" help:"swww transition frame rate"`
+		Duration float64 `yaml:"duration" help:"swww transition duration in seconds"`
+	} `yaml:"transition" help:"swww transition options"`
+	Theme bool `yaml:"theme" help:"Generate a colour theme from the new wallpaper"`
+}
+
+func (a *WallpaperArgs) Validate() error {
+	if a.Path == "" && a.Schedule.File == "" && a.Schedule.Inline == nil {
+		return fmt.Errorf("args.path or args.schedule is required")
+	}
+
+	return nil
+}
+
+// WallpaperScheduleArg is the path of a schedule file, or a schedule written inline in the job.
+type WallpaperScheduleArg struct {
+	File   string
+	Inline *wallpaper.Schedule
+}
+
+func (s *WallpaperScheduleArg) DecodeCronArg(value interface{}) error {
+	switch value := value.(type) {
+	case string:
+		s.File = value
+	case map[string]interface{}:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		if s.Inline, err = wallpaper.ParseSchedule(data); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("expected a file path or a schedule, got %s", describeCronArg(value))
+	}
+
+	return nil
+}
+
+func (s *WallpaperScheduleArg) CronArgSchema() map[string]any {
+	return map[string]any{"oneOf": []any{
+		map[string]any{"type": "string"},
+		map[string]any{"type": "object", "required": []string{"sets"}},
+	}}
+}
+
+// LockScreenArgs are the args of $update_lock_screen_phrase, named like the hyprlock flags.
+type LockScreenArgs struct {
+	Config     string   `yaml:"config" help:"Hyprlock config file" default:"~/.config/hypr/hyprlock.conf"`
+	Jokes      bool     `yaml:"jokes" help:"Show a joke from the providers instead of the message"`
+	Message    string   `yaml:"message" help:"Message shown without jokes"`
+	Format     string   `yaml:"format" help:"Message format: a Go template, or a printf-style format where %s is the message" default:"👉 %s 🤪"`
+	Provider   []string `yaml:"provider" help:"Joke providers (icanhazdadjoke, reddit, quotes, fortune, bible, votd or one from the providers file)" default:"reddit,icanhazdadjoke"`
+	Providers  string   `yaml:"providers" help:"YAML file declaring HTTP providers"`
+	Label      string   `yaml:"label" help:"Label to update, tagged with a '# ebenezer:<label>' comment" default:"message"`
+	LabelIndex int      `yaml:"label_index" help:"Position of the label block to update, counting from 1"`
+	Blocklist  []string `yaml:"blocklist" help:"Skip jokes containing any of these words"`
+	Subreddits []string `yaml:"subreddits" help:"Subreddits read by the reddit provider"`
+	Flairs     []string `yaml:"flairs" help:"Only keep reddit posts with one of these flairs"`
+	MaxLength  int      `yaml:"max_length" help:"Skip jokes longer than this many characters (0 for no limit)"`
+	AllowNsfw  bool     `yaml:"allow_nsfw" help:"Keep reddit posts marked as NSFW"`
+	Truncate   int      `yaml:"truncate" help:"Maximum length of the message in characters before format adds to it (0 for no limit)" default:"100"`
+	Newlines   string   `yaml:"newlines" help:"How line breaks are shown" enum:"br,space" default:"br"`
+	Quotes     string   `yaml:"quotes" help:"Quotation mark style" enum:"keep,straight,curly" default:"keep"`
+}
+
+func (a *LockScreenArgs) Validate() error {
+	for name, value := range map[string]int{"label_index": a.LabelIndex, "max_length": a.MaxLength, "truncate": a.Truncate} {
+		if value < 0 {
+			return fmt.Errorf("args.%s must not be negative, got %d", name, value)
+		}
+	}
+
+	if _, err := jokes.ParseFormat(a.Format, nil); err != nil {
+		return fmt.Errorf("args.format: %w", err)
+	}
+
+	return nil
+}
+
+// ReloadArgs are the args of $reload.
+type ReloadArgs struct {
+	Component string `yaml:"component" help:"Component to reload" enum:"all,hyprland,waybar" default:"all"`
+	Wait      int    `yaml:"wait" help:"Seconds between stopping and starting waybar" default:"2"`
+}
+
+func (a *ReloadArgs) Validate() error {
+	if a.Wait < 0 {
+		return fmt.Errorf("args.wait must not be negative, got %d", a.Wait)
+	}
+
+	return nil
+}
+
+// ClearNotificationsArgs are the args of $clear_notifications.
+type ClearNotificationsArgs struct {
+	Provider string `yaml:"provider" help:"Notification daemon" enum:"swaync,dunst" default:"swaync"`
+}
+
+// CleanupCacheArgs are the args of $cleanup_cache.
+type CleanupCacheArgs struct {
+	Jokes      bool          `yaml:"jokes" help:"Remove the joke pools fetched longer than max_age ago" default:"true"`
+	MaxAge     time.Duration `yaml:"max_age" help:"Age of the joke pools to remove" default:"168h"`
+	Wallpapers bool          `yaml:"wallpapers" help:"Forget the deleted images in the wallpaper rotation" default:"true"`
+}
+
+func (a *CleanupCacheArgs) Validate() error {
+	if a.MaxAge <= 0 {
+		return fmt.Errorf("args.max_age must be positive, got %s", a.MaxAge)
+	}
+
+	return nil
+}
+
+// BatteryCheckArgs are the args of $battery_check.
+type BatteryCheckArgs struct {
+	Threshold int `yaml:"threshold" help:"Charge in percent that raises a notification" default:"20"`
+	Critical  int `yaml:"critical" help:"Charge in percent that raises a critical notification" default:"10"`
+}
+
+func (a *BatteryCheckArgs) Validate() error {
+	if a.Threshold < 1 || a.Threshold > 100 {
+		return fmt.Errorf("args.threshold must be between 1 and 100, got %d", a.Threshold)
+	}
+
+	if a.Critical < 0 || a.Critical > a.Threshold {
+		return fmt.Errorf("args.critical must be between 0 and the threshold %d, got %d", a.Threshold, a.Critical)
+	}
+
+	return nil
+}
+
+// DiskSpaceCheckArgs are the args of $disk_space_check.
+type DiskSpaceCheckArgs struct {
+	Paths          []string `yaml:"paths" help:"Paths whose filesystems are checked" default:"/"`
+	MinFreePercent int      `yaml:"min_free_percent" help:"Free space in percent under which a notification is sent" default:"10"`
+}
+
+func (a *DiskSpaceCheckArgs) Validate() error {
+	if len(a.Paths) == 0 {
+		return fmt.Errorf("args.paths must not be empty")
+	}
+
+	if a.MinFreePercent < 1 || a.MinFreePercent > 99 {
+		return fmt.Errorf("args.min_free_percent must be between 1 and 99, got %d", a.MinFreePercent)
+	}
+
+	return nil
+}
+
+// definedCronHandler is a handler of `type: defined` jobs: its help and args feed validation
+// and the schema, and Build makes the handler of a job.
+type definedCronHandler struct {
+	Help string
+	// Args returns a new args struct, which the args of a job are decoded into.
+	Args  func() any
+	Build func(w *CronCmd, cronJob CronJob) CronHandler
+}
+
+// definedCronHandlers lists the defined handlers.
+var definedCronHandlers = map[string]definedCronHandler{
+	"$set_random_wallpaper":      {"Set a random wallpaper from a directory or a schedule", func() any { return &WallpaperArgs{} }, (*CronCmd).buildWallpaperHandler},
+	"$update_lock_screen_phrase": {"Update the message of the hyprlock lock screen", func() any { return &LockScreenArgs{} }, (*CronCmd).buildHyprlockHandler},
+	"$reload":                    {"Reload Hyprland, waybar or both", func() any { return &ReloadArgs{} }, (*CronCmd).buildReloadHandler},
+	"$clear_notifications":       {"Clear the desktop notifications", func() any { return &ClearNotificationsArgs{} }, (*CronCmd).buildClearNotificationsHandler},
+	"$cleanup_cache":             {"Prune the joke cache and the deleted wallpapers of the rotation", func() any { return &CleanupCacheArgs{} }, (*CronCmd).buildCleanupCacheHandler},
+	"$battery_check":             {"Notify when the battery runs low", func() any { return &BatteryCheckArgs{} }, (*CronCmd).buildBatteryCheckHandler},
+	"$disk_space_check":          {"Notify when a filesystem runs out of space", func() any { return &DiskSpaceCheckArgs{} }, (*CronCmd).buildDiskSpaceCheckHandler},
+}
+
+// validateHandler checks that a defined job names a known handler. Only the config files are
+// checked with it, not the scheduler, so tests can schedule handlers of their own.
+func (c CronJob) validateHandler() error {
+	if _, known := definedCronHandlers[c.Command]; c.Type == "defined" && !known {
+		return fmt.Errorf("unknown defined handler '%s'", c.Command)
+	}
+
+	return nil
+}
+
+// validateArgs checks the args of a defined job whose handler declares them.
+func (c CronJob) validateArgs() error {
+	if c.Type != "defined" {
+		return nil
+	}
+
+	handler, ok := definedCronHandlers[c.Command]
+	if !ok {
+		return nil
+	}
+
+	return decodeCronArgs(c.Args, handler.Args())
+}
+
+// jobArgs decodes the args of cronJob into args.
+func jobArgs(cronJob CronJob, args any) error {
+	if err := decodeCronArgs(cronJob.Args, args); err != nil {
+		return fmt.Errorf("invalid args in cron job '%s': %w", cronJob.Name, err)
+	}
+
+	return nil
+}
+
+func (w *CronCmd) buildReloadHandler(cronJob CronJob) CronHandler {
+	return func(ctx context.Context) error {
+		var args ReloadArgs
+		if err := jobArgs(cronJob, &args); err != nil {
+			return err
+		}
+
+		reloadCmd := ReloadCmd{HyprlandCmd: w.withContext(ctx), Component: args.Component, WaitTime: args.Wait}
+		return reloadCmd.reload()
+	}
+}
+
+func (w *CronCmd) buildClearNotificationsHandler(cronJob CronJob) CronHandler {
+	return func(ctx context.Context) error {
+		var args ClearNotificationsArgs
+		if err := jobArgs(cronJob, &args); err != nil {
+			return err
+		}
+
+		notificationsCmd := desktop.NotificationsCmd{BaseCmd: w.withContext(ctx).BaseCmd, Clear: true, Provider: args.Provider}
+		return notificationsCmd.ClearNotifications()
+	}
+}
+
+func (w *CronCmd) buildCleanupCacheHandler(cronJob CronJob) CronHandler {
+	return func(context.Context) error {
+		var args CleanupCacheArgs
+		if err := jobArgs(cronJob, &args); err != nil {
+			return err
+		}
+
+		if args.Jokes {
+			providers, err := jokes.NewCacheStore(w.Logger, "").Prune(args.MaxAge)
+			if err != nil {
+				return fmt.Errorf("failed to prune the jokes cache: %w", err)
+			}
+			w.Logger.Info("Pruned the jokes cache", "providers", providers)
+		}
+
+		if args.Wallpapers {
+			rotation, err := wallpaper.LoadRotation(wallpaper.DefaultRotationPath())
+			if err != nil {
+				return err
+			}
+
+			// only images that are gone are forgotten, not the ones on a drive that fails to answer
+			forgotten := rotation.Prune(func(image string) bool {
+				_, err := os.Stat(image)
+				return !errors.Is(err, os.ErrNotExist)
+			})
+
+			if forgotten > 0 {
+				if err := rotation.Save(); err != nil {
+					return err
+				}
+			}
+			w.Logger.Info("Pruned the wallpaper rotation", "forgotten", forgotten)
+		}
+
+		return nil
+	}
+}
+
+func (w *CronCmd) buildBatteryCheckHandler(cronJob CronJob) CronHandler {
+	return func(context.Context) error {
+		var args BatteryCheckArgs
+		if err := jobArgs(cronJob, &args); err != nil {
+			return err
+		}
+
+		power, err := readPower()
+		if err != nil {
+			return err
+		}
+
+		capacity := power.Capacity()
+
+		var level string
+		switch {
+		case capacity < 0 || power.OnAC:
+		case capacity <= args.Critical:
+			level = "critical"
+		case capacity <= args.Threshold:
+			level = "normal"
+		}
+
+		if !w.raiseAlert("battery", level) {
+			return nil
+		}
+
+		return w.notify(level, "Battery low", fmt.Sprintf("%d%% remaining, plug in the charger", capacity))
+	}
+}
+
+func (w *CronCmd) buildDiskSpaceCheckHandler(cronJob CronJob) CronHandler {
+	return func(context.Context) error {
+		var args DiskSpaceCheckArgs
+		if err := jobArgs(cronJob, &args); err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, path := range args.Paths {
+			usage, err := system.ReadDisk(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			var level string
+			if usage.FreePercent() < float64(args.MinFreePercent) {
+				level = "critical"
+			}
+
+			if !w.raiseAlert("disk:"+path, level) {
+				continue
+			}
+
+			body := fmt.Sprintf("%s free on %s (%.0f%%)", formatBytes(usage.Free), path, usage.FreePercent())
+			if err := w.notify(level, "Low disk space", body); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// raiseAlert records the level of the alert named key, an empty level once the alert is
+// over. It reports whether a notification is due: the level is set and changed since the
+// last check, so a condition that lasts is notified once.
+func (w *CronCmd) raiseAlert(key, level string) bool {
+	w.alertsMu.Lock()
+	defer w.alertsMu.Unlock()
+
+	if w.alerts == nil {
+		w.alerts = map[string]string{}
+	}
+
+	previous := w.alerts[key]
+	w.alerts[key] = level
+
+	return level != "" && level != previous
+}
+
+// notify sends a desktop notification with urgency low, normal or critical.
+func (w *CronCmd) notify(urgency, summary, body string) error {
+	_, err := w.Shell.Run(shell.RunnerExecutionArgs{
+		Command: "notify-send",
+		Args:    []string{"--urgency=" + urgency, "--app-name=ebenezer", summary, body},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return nil
+}
+
+// formatBytes returns size in the largest binary unit under it, such as 3.2 GiB.
+func formatBytes(size uint64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + " " + units[unit]
+}
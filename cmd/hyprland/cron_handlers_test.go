@@ -0,0 +1,253 @@
+package hyprland
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	jokes "github.com/williampsena/ebenezer-cli/internal/jokes"
+	"github.com/williampsena/ebenezer-cli/internal/system"
+	"github.com/williampsena/ebenezer-cli/internal/wallpaper"
+)
+
+func TestCronJob_validateArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"Reload", "$reload", map[string]interface{}{"component": "waybar"}, ""},
+		{"ReloadComponent", "$reload", map[string]interface{}{"component": "kitty"}, "args.component: invalid value 'kitty'"},
+		{"ReloadWait", "$reload", map[string]interface{}{"wait": -1}, "args.wait must not be negative"},
+		{"Notifications", "$clear_notifications", map[string]interface{}{"provider": "dunst"}, ""},
+		{"NotificationsProvider", "$clear_notifications", map[string]interface{}{"provider": "mako"}, "args.provider: invalid value 'mako'"},
+		{"Cleanup", "$cleanup_cache", map[string]interface{}{"max_age": "24h", "wallpapers": false}, ""},
+		{"CleanupAge", "$cleanup_cache", map[string]interface{}{"max_age": "0s"}, "args.max_age must be positive"},
+		{"Battery", "$battery_check", nil, ""},
+		{"BatteryCritical", "$battery_check", map[string]interface{}{"threshold": 15, "critical": 30}, "args.critical must be between 0 and the threshold 15"},
+		{"BatteryType", "$battery_check", map[string]interface{}{"threshold": "low"}, "args.threshold: expected an integer"},
+		{"Disk", "$disk_space_check", map[string]interface{}{"paths": []interface{}{"/", "/home"}}, ""},
+		{"DiskPercent", "$disk_space_check", map[string]interface{}{"min_free_percent": 100}, "args.min_free_percent must be between 1 and 99"},
+		{"DiskUnknown", "$disk_space_check", map[string]interface{}{"path": "/"}, "args.path: unknown arg"},
+		{"Wallpaper", "$set_random_wallpaper", map[string]interface{}{"path": "~/Pictures", "transition": map[string]interface{}{"type": "fade", "fps": 60}}, ""},
+		{"WallpaperSchedule", "$set_random_wallpaper", map[string]interface{}{"schedule": "~/.config/hypr/wallpapers.yaml"}, ""},
+		{"WallpaperInline", "$set_random_wallpaper", map[string]interface{}{"schedule": map[string]interface{}{"sets": []interface{}{map[string]interface{}{"from": "08:00", "to": "18:00", "path": "~/day"}}}}, ""},
+		{"WallpaperMissingPath", "$set_random_wallpaper", nil, "args.path or args.schedule is required"},
+		{"WallpaperBackend", "$set_random_wallpaper", map[string]interface{}{"path": "~/Pictures", "backend": "nitrogen"}, "args.backend: invalid value 'nitrogen'"},
+		{"WallpaperTransition", "$set_random_wallpaper", map[string]interface{}{"path": "~/Pictures", "transition": map[string]interface{}{"fps": "fast"}}, "args.transition.fps: expected an integer"},
+		{"WallpaperScheduleType", "$set_random_wallpaper", map[string]interface{}{"schedule": 3}, "args.schedule: expected a file path or a schedule"},
+		{"Lock", "$update_lock_screen_phrase", map[string]interface{}{"jokes": true, "provider": []interface{}{"fortune"}, "truncate": 80}, ""},
+		{"LockJokes", "$update_lock_screen_phrase", map[string]interface{}{"jokes": "sometimes"}, "args.jokes: expected true or false"},
+		{"LockTruncate", "$update_lock_screen_phrase", map[string]interface{}{"truncate": -1}, "args.truncate must not be negative"},
+		{"LockQuotes", "$update_lock_screen_phrase", map[string]interface{}{"quotes": "fancy"}, "args.quotes: invalid value 'fancy'"},
+		{"Untyped", "$custom", map[string]interface{}{"anything": 1}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := CronJob{Name: "job", Type: "defined", Command: tt.command, Args: tt.args, Interval: time.Hour}
+			err := job.validateArgs()
+
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCronJobs_ValidateArgsNamesJob(t *testing.T) {
+	jobs := &CronJobs{Jobs: []CronJob{{Name: "battery", Type: "defined", Command: "$battery_check", Interval: time.Minute, Args: map[string]interface{}{"threshold": 150}}}}
+
+	err := jobs.Validate(time.Now())
+	if err == nil || err.Error() != "cron job 'battery': args.threshold must be between 1 and 100, got 150" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCronJobs_ValidateUnknownHandler(t *testing.T) {
+	jobs := &CronJobs{Jobs: []CronJob{{Name: "typo", Type: "defined", Command: "$set_random_wallpapers", Interval: time.Minute}}}
+
+	err := jobs.Validate(time.Now())
+	if err == nil || err.Error() != "cron job 'typo': unknown defined handler '$set_random_wallpapers'" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func buildTestCheckCron(t *testing.T) (*CronCmd, *notifyRunner) {
+	t.Helper()
+
+	cronCmd := &CronCmd{}
+	cronCmd.SetupContext(&cmd.Context{Debug: false})
+
+	runner := &notifyRunner{Runner: cronCmd.Shell}
+	cronCmd.Shell = runner
+
+	return cronCmd, runner
+}
+
+func TestCronCmd_batteryCheck(t *testing.T) {
+	cronCmd, runner := buildTestCheckCron(t)
+	handler := cronCmd.buildBatteryCheckHandler(CronJob{Name: "battery", Args: map[string]interface{}{"threshold": 25}})
+
+	steps := []struct {
+		capacity int
+		onAC     bool
+		notices  int
+		urgency  string
+	}{
+		{80, false, 0, ""},
+		{24, false, 1, "--urgency=normal"},
+		{22, false, 1, ""},
+		{9, false, 2, "--urgency=critical"},
+		{9, true, 2, ""},
+		{20, false, 3, "--urgency=normal"},
+	}
+
+	for _, step := range steps {
+		stubPower(t, system.PowerState{OnAC: step.onAC, Batteries: []system.Battery{{Name: "BAT0", Capacity: step.capacity}}})
+		if err := handler(t.Context()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(runner.notices) != step.notices {
+			t.Fatalf("Expected %d notifications at %d%% (AC %t), got %v", step.notices, step.capacity, step.onAC, runner.notices)
+		}
+
+		if step.urgency != "" && runner.notices[len(runner.notices)-1][0] != step.urgency {
+			t.Errorf("Expected %s, got %v", step.urgency, runner.notices[len(runner.notices)-1])
+		}
+	}
+}
+
+// stubPower makes readPower report state until the test ends.
+func stubPower(t *testing.T, state system.PowerState) {
+	t.Helper()
+
+	previous := readPower
+	readPower = func() (system.PowerState, error) { return state, nil }
+	t.Cleanup(func() { readPower = previous })
+}
+
+func TestCronCmd_lockScreenTimeout(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// reddit never answers
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	previous := jokes.RedditURL
+	jokes.RedditURL = server.URL
+	t.Cleanup(func() { jokes.RedditURL = previous })
+
+	configPath := filepath.Join(t.TempDir(), "hyprlock.conf")
+	if err := os.WriteFile(configPath, []byte("label {\n    text = old message\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, scheduler := buildTestCronScheduler(t, "")
+	job := CronJob{
+		Name:    "lock",
+		Type:    "defined",
+		Command: "$update_lock_screen_phrase",
+		Timeout: 100 * time.Millisecond,
+		Args:    map[string]interface{}{"config": configPath, "jokes": true, "provider": "reddit"},
+	}
+
+	start := time.Now()
+	if _, err := scheduler.run(t.Context(), job, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the timeout to stop the fetch, took %s", elapsed)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), defaultLockMessage) {
+		t.Errorf("Expected the default message once the fetch stopped, got:\n%s", data)
+	}
+}
+
+func TestCronCmd_diskSpaceCheck(t *testing.T) {
+	cronCmd, runner := buildTestCheckCron(t)
+
+	dir := t.TempDir()
+	handler := cronCmd.buildDiskSpaceCheckHandler(CronJob{Name: "disk", Args: map[string]interface{}{"paths": dir, "min_free_percent": 99}})
+
+	for range 2 {
+		if err := handler(t.Context()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(runner.notices) != 1 || !strings.Contains(strings.Join(runner.notices[0], " "), "free on "+dir) {
+		t.Errorf("Expected a single low space notification, got %v", runner.notices)
+	}
+
+	missing := cronCmd.buildDiskSpaceCheckHandler(CronJob{Name: "disk", Args: map[string]interface{}{"paths": filepath.Join(dir, "missing")}})
+	if err := missing(t.Context()); err == nil {
+		t.Error("Expected an error for a missing path")
+	}
+}
+
+func TestCronCmd_cleanupCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	store := jokes.NewCacheStore(core.BuildSilentLogger(), "")
+	store.Save(&jokes.Pool{Provider: "reddit", FetchedAt: time.Now().Add(-30 * 24 * time.Hour)})
+	store.Save(&jokes.Pool{Provider: "fortune", FetchedAt: time.Now()})
+
+	kept := filepath.Join(t.TempDir(), "kept.png")
+	os.WriteFile(kept, []byte("png"), 0644)
+
+	rotation, _ := wallpaper.LoadRotation(wallpaper.DefaultRotationPath())
+	rotation.Record([]wallpaper.Assignment{{Monitor: "eDP-1", Image: "/nowhere/gone.png"}, {Monitor: "HDMI-A-1", Image: kept}})
+	rotation.Save()
+
+	cronCmd, _ := buildTestCheckCron(t)
+	if err := cronCmd.buildCleanupCacheHandler(CronJob{Name: "cleanup"})(t.Context()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pools, _ := store.List()
+	if len(pools) != 1 || pools[0].Provider != "fortune" {
+		t.Errorf("Expected only the fresh pool to be kept, got %v", pools)
+	}
+
+	rotation, _ = wallpaper.LoadRotation(wallpaper.DefaultRotationPath())
+	if shown := rotation.State().Shown; len(shown) != 1 || shown[0] != kept {
+		t.Errorf("Expected only the existing image to be kept, got %v", shown)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size uint64
+		want string
+	}{
+		{512, "512 B"},
+		{2048, "2 KiB"},
+		{3435973837, "3.2 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBytes(tt.size); got != tt.want {
+			t.Errorf("Expected %s for %d, got %s", tt.want, tt.size, got)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package hyprland
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+const (
+	// cronOutputLimit is the number of output bytes kept per run, from the end of the output.
+	cronOutputLimit = 4096
+	// cronHistoryLimit is the size at which the history file is rotated.
+	cronHistoryLimit = 1 << 20
+)
+
+// CronRun is a run of a job, as recorded in the history.
+type CronRun struct {
+	Job        string    `json:"job"`
+	Start      time.Time `json:"start"`
+	DurationMs int64     `json:"duration_ms"`
+	// ExitCode is the exit code of a shell command, 0 on success and -1 for other failures.
+	ExitCode int    `json:"exit_code"`
+	Attempts int    `json:"attempts"`
+	Forced   bool   `json:"forced,omitempty"`
+	Output   string `json:"output,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Duration returns how long the run took.
+func (r CronRun) Duration() time.Duration {
+	return time.Duration(r.DurationMs) * time.Millisecond
+}
+
+// newCronRun records a finished run, keeping the end of its output.
+func newCronRun(job string, start time.Time, attempts int, output string, err error) CronRun {
+	run := CronRun{
+		Job:        job,
+		Start:      start,
+		DurationMs: time.Since(start).Milliseconds(),
+		Attempts:   attempts,
+		Output:     truncateOutput(output, cronOutputLimit),
+	}
+
+	if err != nil {
+		run.Error = err.Error()
+		run.ExitCode = -1
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			run.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	return run
+}
+
+// truncateOutput keeps the last limit bytes of output, where errors usually are.
+func truncateOutput(output string, limit int) string {
+	if len(output) <= limit {
+		return output
+	}
+
+	cut := len(output) - limit
+	for cut < len(output) && !utf8.RuneStart(output[cut]) {
+		cut++
+	}
+
+	return "…" + output[cut:]
+}
+
+// CronHistory is a JSON lines file of runs, rotated to a single ".1" file when it grows
+// past cronHistoryLimit.
+type CronHistory struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultCronHistoryPath returns the history file under the XDG state directory.
+func DefaultCronHistoryPath() string {
+	return filepath.Join(core.StateDir(), "cron-history.jsonl")
+}
+
+// NewCronHistory returns the history at path, or at DefaultCronHistoryPath when path is empty.
+func NewCronHistory(path string) *CronHistory {
+	if path == "" {
+		path = DefaultCronHistoryPath()
+	}
+
+	return &CronHistory{path: core.ResolvePath(path)}
+}
+
+// Path returns the history file.
+func (h *CronHistory) Path() string {
+	return h.path
+}
+
+// Append adds run to the history.
+func (h *CronHistory) Append(run CronRun) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	if info, err := os.Stat(h.path); err == nil && info.Size()+int64(len(data)) >= cronHistoryLimit {
+		if err := os.Rename(h.path, h.path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate cron history: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open cron history: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write cron history: %w", err)
+	}
+
+	return nil
+}
+
+// Read returns the last limit runs of job, oldest first; an empty job means every job and a
+// limit of 0 every run.
+func (h *CronHistory) Read(job string, limit int) ([]CronRun, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var runs []CronRun
+	for _, path := range []string{h.path + ".1", h.path} {
+		file, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cron history: %w", err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), cronHistoryLimit)
+
+		for scanner.Scan() {
+			var run CronRun
+			if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+				continue
+			}
+			if job == "" || run.Job == job {
+				runs = append(runs, run)
+			}
+		}
+
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cron history: %w", err)
+		}
+	}
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+
+	return runs, nil
+}
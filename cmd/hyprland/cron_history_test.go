@@ -0,0 +1,194 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+func TestCronHistory_AppendRead(t *testing.T) {
+	history := NewCronHistory(filepath.Join(t.TempDir(), "state", "cron-history.jsonl"))
+
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	for i, name := range []string{"wallpaper", "backup", "wallpaper", "wallpaper"} {
+		run := CronRun{Job: name, Start: start.Add(time.Duration(i) * time.Minute), Attempts: 1}
+		if err := history.Append(run); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		job       string
+		limit     int
+		wantCount int
+		wantFirst time.Time
+	}{
+		{"All", "", 0, 4, start},
+		{"Job", "wallpaper", 0, 3, start},
+		{"Limit", "wallpaper", 2, 2, start.Add(2 * time.Minute)},
+		{"Unknown", "theme", 0, 0, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := history.Read(tt.job, tt.limit)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(runs) != tt.wantCount {
+				t.Fatalf("Expected %d runs, got %d", tt.wantCount, len(runs))
+			}
+
+			if len(runs) > 0 && !runs[0].Start.Equal(tt.wantFirst) {
+				t.Errorf("Expected the first run at %v, got %v", tt.wantFirst, runs[0].Start)
+			}
+		})
+	}
+}
+
+func TestCronHistory_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron-history.jsonl")
+	history := NewCronHistory(path)
+
+	if err := history.Append(CronRun{Job: "old"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// pad the file up to the rotation size
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(strings.Repeat("\n", cronHistoryLimit))
+	file.Close()
+
+	if err := history.Append(CronRun{Job: "new"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("Expected a rotated history: %v", err)
+	}
+
+	runs, err := history.Read("", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(runs) != 2 || runs[0].Job != "old" || runs[1].Job != "new" {
+		t.Errorf("Expected the old and new runs, got %+v", runs)
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		limit  int
+		want   string
+	}{
+		{"Short", "done", 10, "done"},
+		{"Long", "first line\nlast line", 9, "…last line"},
+		{"Rune", "añb", 2, "…b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateOutput(tt.output, tt.limit); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewCronRun_ExitCode(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"Success", nil, 0},
+		{"ExitCode", exitErr, 3},
+		{"Wrapped", errors.Join(errors.New("failed after 2 attempts"), exitErr), 3},
+		{"Other", errors.New("timed out"), -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := newCronRun("job", time.Now(), 1, "", tt.err)
+			if run.ExitCode != tt.want {
+				t.Errorf("Expected exit code %d, got %d", tt.want, run.ExitCode)
+			}
+		})
+	}
+}
+
+// notifyRunner records the notifications sent through notify-send.
+type notifyRunner struct {
+	shell.Runner
+	mu      sync.Mutex
+	notices [][]string
+}
+
+func (r *notifyRunner) Run(args shell.RunnerExecutionArgs) (string, error) {
+	if args.Command == "notify-send" {
+		r.mu.Lock()
+		r.notices = append(r.notices, args.Args)
+		r.mu.Unlock()
+		return "", nil
+	}
+
+	return r.Runner.Run(args)
+}
+
+func TestCronScheduler_taskRecordsHistory(t *testing.T) {
+	cronCmd, scheduler := buildTestCronScheduler(t, "")
+
+	runner := &notifyRunner{Runner: cronCmd.Shell}
+	cronCmd.Shell = runner
+
+	scheduler.defined = DefinedCron{
+		"$broken": func(CronJob) CronHandler {
+			return func(context.Context) error { return errors.New("disk full") }
+		},
+	}
+
+	job := CronJob{Name: "backup", Type: "defined", Command: "$broken", NotifyAfter: 2}
+	task := scheduler.task("backup", job, false)
+	for range 3 {
+		task(context.Background())
+	}
+
+	runs, err := scheduler.history.Read("backup", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(runs) != 3 {
+		t.Fatalf("Expected 3 recorded runs, got %d", len(runs))
+	}
+
+	if runs[0].Error != "disk full" || runs[0].ExitCode != -1 || runs[0].Attempts != 1 {
+		t.Errorf("Unexpected run: %+v", runs[0])
+	}
+
+	if len(runner.notices) != 1 {
+		t.Fatalf("Expected a single notification, got %v", runner.notices)
+	}
+
+	if notice := strings.Join(runner.notices[0], " "); !strings.Contains(notice, "backup failed 2 times in a row") {
+		t.Errorf("Unexpected notification: %s", notice)
+	}
+}
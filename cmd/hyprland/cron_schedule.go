@@ -0,0 +1,309 @@
+package hyprland
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser accepts the 5-field crontab, an optional leading seconds field, descriptors such
+// as @daily or @every 5m and a CRON_TZ= prefix, like the gocron scheduler does.
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// atLayouts are the accepted formats of one-shot `at` times; a time alone means its next occurrence.
+var atLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+var clockLayouts = []string{"15:04:05", "15:04"}
+
+const (
+	OverlapAllow         = "allow"
+	OverlapSingleton     = "singleton" // alias of OverlapSkipIfRunning
+	OverlapSkipIfRunning = "skip-if-running"
+	OverlapQueue         = "queue"
+)
+
+const (
+	defaultCronBackoff = 5 * time.Second
+	maxCronBackoff     = 10 * time.Minute
+)
+
+// errJobExpired is returned for a one-shot job whose time has passed.
+var errJobExpired = errors.New("the at time has passed")
+
+// Validate checks the schedule of every job and the chains between them, naming the job at fault.
+func (c *CronJobs) Validate(now time.Time) error {
+	seen := map[string]bool{}
+
+	for _, job := range c.Jobs {
+		if job.Name != "" && seen[job.Name] {
+			return fmt.Errorf("cron job '%s' is declared twice", job.Name)
+		}
+		seen[job.Name] = true
+	}
+
+	if err := c.validateChains(); err != nil {
+		return err
+	}
+
+	triggered := c.triggeredJobs()
+	for i, job := range c.Jobs {
+		if err := job.validateHandler(); err != nil {
+			return fmt.Errorf("%s: %w", job.label(i), err)
+		}
+
+		if _, err := job.definition(now, triggered[job.Name]); err != nil && !errors.Is(err, errJobExpired) {
+			return fmt.Errorf("%s: %w", job.label(i), err)
+		}
+	}
+
+	return nil
+}
+
+// label names the job in errors, by position when it has no name.
+func (c CronJob) label(index int) string {
+	if c.Name == "" {
+		return fmt.Sprintf("cron job #%d", index+1)
+	}
+
+	return fmt.Sprintf("cron job '%s'", c.Name)
+}
+
+// definition returns the gocron schedule of the job: an interval, a cron expression or a
+// one-shot time, in the job timezone when one is set. triggered jobs, started by other jobs,
+// may have no schedule of their own; their definition is nil and they are not scheduled.
+func (c CronJob) definition(now time.Time, triggered bool) (gocron.JobDefinition, error) {
+	set := 0
+	for _, given := range []bool{c.Interval != 0, c.Schedule != "", c.At != ""} {
+		if given {
+			set++
+		}
+	}
+
+	switch {
+	case set == 0 && !triggered:
+		return nil, fmt.Errorf("one of interval, schedule or at is required, unless the job follows another")
+	case set > 1:
+		return nil, fmt.Errorf("interval, schedule and at cannot be combined")
+	}
+
+	location, err := c.location()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validateRuns(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case set == 0:
+		if c.Timezone != "" {
+			return nil, fmt.Errorf("timezone applies to schedule and at, not to follow-up jobs")
+		}
+		return nil, nil
+	case c.Interval < 0:
+		return nil, fmt.Errorf("interval must be positive, got %s", c.Interval)
+	case c.Interval > 0:
+		if c.Timezone != "" {
+			return nil, fmt.Errorf("timezone applies to schedule and at, not interval")
+		}
+		if c.Jitter > 0 {
+			if c.Jitter >= c.Interval {
+				return nil, fmt.Errorf("jitter %s must be shorter than the interval %s", c.Jitter, c.Interval)
+			}
+			return gocron.DurationRandomJob(c.Interval-c.Jitter, c.Interval+c.Jitter), nil
+		}
+		return gocron.DurationJob(c.Interval), nil
+	case c.Schedule != "":
+		crontab, err := parseCronSchedule(c.Schedule, location)
+		if err != nil {
+			return nil, err
+		}
+		return gocron.CronJob(crontab, true), nil
+	default:
+		at, err := parseAt(c.At, location, now)
+		if err != nil {
+			return nil, err
+		}
+		if !at.After(now) {
+			return nil, fmt.Errorf("%w (%s)", errJobExpired, at.Format(time.RFC3339))
+		}
+		return gocron.OneTimeJob(gocron.OneTimeJobStartDateTime(at)), nil
+	}
+}
+
+// validateRuns checks the options that control how the job runs.
+func (c CronJob) validateRuns() error {
+	for name, value := range map[string]time.Duration{"timeout": c.Timeout, "backoff": c.Backoff, "jitter": c.Jitter} {
+		if value < 0 {
+			return fmt.Errorf("%s must not be negative, got %s", name, value)
+		}
+	}
+
+	if c.Retries < 0 {
+		return fmt.Errorf("retries must not be negative, got %d", c.Retries)
+	}
+
+	if c.NotifyAfter < 0 {
+		return fmt.Errorf("notify_after must not be negative, got %d", c.NotifyAfter)
+	}
+
+	switch c.Overlap {
+	case "", OverlapAllow, OverlapSingleton, OverlapSkipIfRunning, OverlapQueue:
+	default:
+		return fmt.Errorf("invalid overlap '%s': expected %s, %s, %s or %s", c.Overlap, OverlapAllow, OverlapSingleton, OverlapSkipIfRunning, OverlapQueue)
+	}
+
+	if c.RunOnStart && c.At != "" {
+		return fmt.Errorf("run_on_start cannot be used with at")
+	}
+
+	if err := c.validateShell(); err != nil {
+		return err
+	}
+
+	if err := c.validateArgs(); err != nil {
+		return err
+	}
+
+	return c.When.Validate()
+}
+
+// exclusive reports a job whose runs must not overlap.
+func (c CronJob) exclusive() bool {
+	return c.Overlap == OverlapSingleton || c.Overlap == OverlapSkipIfRunning
+}
+
+// options returns the gocron options of the job.
+func (c CronJob) options() []gocron.JobOption {
+	options := []gocron.JobOption{gocron.WithName(c.Name)}
+
+	switch c.Overlap {
+	case OverlapSingleton, OverlapSkipIfRunning:
+		options = append(options, gocron.WithSingletonMode(gocron.LimitModeReschedule))
+	case OverlapQueue:
+		options = append(options, gocron.WithSingletonMode(gocron.LimitModeWait))
+	}
+
+	if c.RunOnStart {
+		options = append(options, gocron.WithStartAt(gocron.WithStartImmediately()))
+	}
+
+	return options
+}
+
+// backoff returns the wait before the retry that follows attempt, counting from 1.
+func (c CronJob) backoff(attempt int) time.Duration {
+	delay := c.Backoff
+	if delay == 0 {
+		delay = defaultCronBackoff
+	}
+
+	for i := 1; i < attempt && delay < maxCronBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxCronBackoff)
+}
+
+// describe returns the schedule of the job as written in the config file.
+func (c CronJob) describe() string {
+	var description string
+
+	switch {
+	case c.followUp():
+		if len(c.After) == 0 {
+			return "follow-up"
+		}
+		return "after " + strings.Join(c.After, ", ")
+	case c.Interval > 0:
+		description = "every " + c.Interval.String()
+	case c.Schedule != "":
+		description = c.Schedule
+	default:
+		description = "at " + c.At
+	}
+
+	if c.Timezone != "" {
+		description += " (" + c.Timezone + ")"
+	}
+
+	if len(c.After) > 0 {
+		description += ", after " + strings.Join(c.After, ", ")
+	}
+
+	return description
+}
+
+func (c CronJob) location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %w", c.Timezone, err)
+	}
+
+	return location, nil
+}
+
+// parseCronSchedule validates a cron expression or descriptor and returns it with the timezone
+// prefix gocron expects.
+func parseCronSchedule(schedule string, location *time.Location) (string, error) {
+	crontab := strings.Join(strings.Fields(schedule), " ")
+
+	if strings.HasPrefix(crontab, "TZ=") || strings.HasPrefix(crontab, "CRON_TZ=") {
+		return "", fmt.Errorf("invalid schedule '%s': use the timezone field instead of a TZ prefix", schedule)
+	}
+
+	if !strings.HasPrefix(crontab, "@") {
+		if fields := len(strings.Fields(crontab)); fields != 5 && fields != 6 {
+			return "", fmt.Errorf("invalid schedule '%s': expected 5 or 6 fields, got %d", schedule, fields)
+		}
+	}
+
+	crontab = fmt.Sprintf("CRON_TZ=%s %s", location, crontab)
+
+	parsed, err := cronParser.Parse(crontab)
+	if err != nil {
+		return "", fmt.Errorf("invalid schedule '%s': %w", schedule, err)
+	}
+
+	if parsed.Next(time.Now()).IsZero() {
+		return "", fmt.Errorf("invalid schedule '%s': it never runs", schedule)
+	}
+
+	return crontab, nil
+}
+
+// parseAt reads a one-shot time in location. A time of day without a date is its next occurrence.
+func parseAt(value string, location *time.Location, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range atLayouts {
+		if at, err := time.ParseInLocation(layout, value, location); err == nil {
+			return at, nil
+		}
+	}
+
+	for _, layout := range clockLayouts {
+		clock, err := time.ParseInLocation(layout, value, location)
+		if err != nil {
+			continue
+		}
+
+		local := now.In(location)
+		at := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, location)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid at time '%s': expected 'YYYY-MM-DD HH:MM', RFC 3339 or 'HH:MM'", value)
+}
@@ -0,0 +1,170 @@
+package hyprland
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+)
+
+func TestCronJob_definition(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		job     CronJob
+		wantErr string
+	}{
+		{"Interval", CronJob{Interval: time.Minute}, ""},
+		{"FiveFields", CronJob{Schedule: "0 9 * * 1-5"}, ""},
+		{"SixFields", CronJob{Schedule: "30 0 * * * *"}, ""},
+		{"Descriptor", CronJob{Schedule: "@daily"}, ""},
+		{"Every", CronJob{Schedule: "@every 15m"}, ""},
+		{"Timezone", CronJob{Schedule: "0 9 * * *", Timezone: "America/Sao_Paulo"}, ""},
+		{"At", CronJob{At: "2024-03-05 09:00"}, ""},
+		{"AtClock", CronJob{At: "09:00"}, ""},
+		{"Missing", CronJob{}, "one of interval, schedule or at is required"},
+		{"Combined", CronJob{Interval: time.Minute, Schedule: "@hourly"}, "cannot be combined"},
+		{"NegativeInterval", CronJob{Interval: -time.Minute}, "interval must be positive"},
+		{"IntervalTimezone", CronJob{Interval: time.Minute, Timezone: "UTC"}, "not interval"},
+		{"FourFields", CronJob{Schedule: "0 9 * *"}, "expected 5 or 6 fields, got 4"},
+		{"BadField", CronJob{Schedule: "0 25 * * *"}, "invalid schedule '0 25 * * *'"},
+		{"BadDescriptor", CronJob{Schedule: "@sometimes"}, "invalid schedule"},
+		{"TZPrefix", CronJob{Schedule: "CRON_TZ=UTC 0 9 * * *"}, "use the timezone field"},
+		{"BadTimezone", CronJob{Schedule: "@daily", Timezone: "Mars/Olympus"}, "invalid timezone 'Mars/Olympus'"},
+		{"BadAt", CronJob{At: "tomorrow"}, "invalid at time 'tomorrow'"},
+		{"AtPassed", CronJob{At: "2024-03-01 09:00"}, "the at time has passed"},
+		{"RunOptions", CronJob{Schedule: "@hourly", Timeout: time.Minute, Retries: 3, Backoff: time.Second, Overlap: OverlapQueue, RunOnStart: true, Jitter: time.Minute}, ""},
+		{"IntervalJitter", CronJob{Interval: time.Hour, Jitter: time.Minute}, ""},
+		{"JitterTooLong", CronJob{Interval: time.Minute, Jitter: time.Hour}, "jitter 1h0m0s must be shorter than the interval 1m0s"},
+		{"NegativeTimeout", CronJob{Interval: time.Minute, Timeout: -time.Second}, "timeout must not be negative"},
+		{"NegativeRetries", CronJob{Interval: time.Minute, Retries: -1}, "retries must not be negative"},
+		{"BadOverlap", CronJob{Interval: time.Minute, Overlap: "parallel"}, "invalid overlap 'parallel'"},
+		{"RunOnStartAt", CronJob{At: "2024-03-05 09:00", RunOnStart: true}, "run_on_start cannot be used with at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition, err := tt.job.definition(now, false)
+			if tt.wantErr == "" {
+				if err != nil || definition == nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCronJob_backoff(t *testing.T) {
+	tests := []struct {
+		job      CronJob
+		attempt  int
+		expected time.Duration
+	}{
+		{CronJob{}, 1, 5 * time.Second},
+		{CronJob{}, 3, 20 * time.Second},
+		{CronJob{Backoff: time.Second}, 4, 8 * time.Second},
+		{CronJob{Backoff: time.Minute}, 10, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if delay := tt.job.backoff(tt.attempt); delay != tt.expected {
+			t.Errorf("backoff(%d) with %s: expected %s, got %s", tt.attempt, tt.job.Backoff, tt.expected, delay)
+		}
+	}
+}
+
+func TestParseAt(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	now := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		location *time.Location
+		expected time.Time
+	}{
+		{"DateTime", "2024-03-05 09:30", time.UTC, time.Date(2024, time.March, 5, 9, 30, 0, 0, time.UTC)},
+		{"RFC3339", "2024-03-05T09:30:00Z", saoPaulo, time.Date(2024, time.March, 5, 9, 30, 0, 0, time.UTC)},
+		{"InLocation", "2024-03-05T09:30", saoPaulo, time.Date(2024, time.March, 5, 12, 30, 0, 0, time.UTC)},
+		{"ClockToday", "18:00", time.UTC, time.Date(2024, time.March, 4, 18, 0, 0, 0, time.UTC)},
+		{"ClockTomorrow", "09:00", time.UTC, time.Date(2024, time.March, 5, 9, 0, 0, 0, time.UTC)},
+		{"ClockInLocation", "08:00", saoPaulo, time.Date(2024, time.March, 4, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := parseAt(tt.value, tt.location, now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !at.Equal(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, at)
+			}
+		})
+	}
+}
+
+func TestCronJobs_Validate(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		jobs    []CronJob
+		wantErr string
+	}{
+		{"Valid", []CronJob{{Name: "a", Schedule: "@hourly"}, {Name: "b", Interval: time.Hour}}, ""},
+		{"ExpiredIsSkipped", []CronJob{{Name: "once", At: "2024-01-01 00:00"}}, ""},
+		{"NamesJob", []CronJob{{Name: "ok", Schedule: "@hourly"}, {Name: "wallpaper", Schedule: "0 9 * * mon-fry"}}, "cron job 'wallpaper': invalid schedule"},
+		{"Unnamed", []CronJob{{Name: "ok", Schedule: "@hourly"}, {Schedule: "nope"}}, "cron job #2:"},
+		{"Duplicate", []CronJob{{Name: "a", Schedule: "@hourly"}, {Name: "a", Schedule: "@daily"}}, "cron job 'a' is declared twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&CronJobs{Jobs: tt.jobs}).Validate(now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCronCmd_parseCrons(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "cron.yaml")
+	os.WriteFile(config, []byte(`jobs:
+  - name: weekday-wallpaper
+    type: shell
+    command: echo hi
+    schedule: "0 9 * * 1-5"
+    timezone: Europe/Lisbon
+  - name: lock-phrase
+    type: shell
+    command: echo hi
+    schedule: "61 * * * *"
+`), 0644)
+
+	cronCmd := &CronCmd{Config: config}
+	cronCmd.SetupContext(&cmd.Context{Debug: false})
+
+	_, err := cronCmd.parseCrons()
+	if err == nil || !strings.Contains(err.Error(), "cron job 'lock-phrase'") {
+		t.Fatalf("Expected an error naming lock-phrase, got %v", err)
+	}
+}
@@ -0,0 +1,533 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/williampsena/ebenezer-cli/internal/control"
+)
+
+// cronStopTimeout is how long a shutdown waits for the jobs that are running.
+const cronStopTimeout = 30 * time.Second
+
+// cronScheduler keeps the jobs of cron.yaml in a running gocron scheduler, so a reload only
+// touches the jobs that changed.
+type cronScheduler struct {
+	cron      *CronCmd
+	scheduler gocron.Scheduler
+	defined   DefinedCron
+	history   *CronHistory
+
+	mu     sync.Mutex
+	jobs   map[string]scheduledJob
+	states map[string]*jobState
+}
+
+type scheduledJob struct {
+	config CronJob
+	// job is nil for follow-up jobs, which only run when chain starts them
+	job gocron.Job
+	// index is the position of the job in the config file
+	index int
+}
+
+// jobState is what the daemon remembers of a job across runs and reloads.
+type jobState struct {
+	lastRun   time.Time
+	lastError string
+	ran       bool
+	// running counts the runs in progress, manual runs may overlap scheduled ones
+	running int
+	// failures counts the failed runs since the last success
+	failures int
+	paused   bool
+	// queue makes the runs of queue jobs wait for each other, manual ones included
+	queue sync.Mutex
+	// completed holds the jobs of the after list that succeeded since the last run
+	completed map[string]bool
+}
+
+// CronJobStatus is a job as reported by `cron list`.
+type CronJobStatus struct {
+	Name       string    `json:"name"`
+	Schedule   string    `json:"schedule"`
+	NextRun    time.Time `json:"next_run"`
+	LastRun    time.Time `json:"last_run"`
+	LastResult string    `json:"last_result"`
+	Running    bool      `json:"running"`
+	Paused     bool      `json:"paused"`
+}
+
+func newCronScheduler(w *CronCmd) (*cronScheduler, error) {
+	scheduler, err := gocron.NewScheduler(gocron.WithStopTimeout(cronStopTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduler: %w", err)
+	}
+
+	return &cronScheduler{
+		cron:      w,
+		scheduler: scheduler,
+		defined:   w.buildDefinedCrons(),
+		history:   NewCronHistory(w.History),
+		jobs:      map[string]scheduledJob{},
+		states:    map[string]*jobState{},
+	}, nil
+}
+
+// Apply brings the scheduler in line with jobs: new jobs are added, changed ones rescheduled
+// and the ones no longer declared removed. Unchanged jobs keep their schedule.
+func (s *cronScheduler) Apply(jobs []CronJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	logger := s.cron.Logger
+
+	triggered := (&CronJobs{Jobs: jobs}).triggeredJobs()
+
+	wanted := map[string]bool{}
+	for i, job := range jobs {
+		wanted[jobKey(i, job)] = true
+	}
+
+	for key, scheduled := range s.jobs {
+		if !wanted[key] {
+			s.remove(key, scheduled)
+			logger.Info("Removed cron job", "name", scheduled.config.Name)
+		}
+	}
+
+	for i, job := range jobs {
+		key := jobKey(i, job)
+		scheduled, exists := s.jobs[key]
+
+		if exists && reflect.DeepEqual(scheduled.config, job) {
+			scheduled.index = i
+			s.jobs[key] = scheduled
+			continue
+		}
+
+		definition, err := job.definition(now, triggered[job.Name])
+		if errors.Is(err, errJobExpired) {
+			logger.Warning("Skipping one-shot cron job", "name", job.Name, "at", job.At)
+			if exists {
+				s.remove(key, scheduled)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", job.label(i), err)
+		}
+
+		task := gocron.NewTask(s.task(key, job, false))
+
+		var created gocron.Job
+		switch {
+		case definition == nil:
+			if exists && scheduled.job != nil {
+				s.unschedule(scheduled)
+			}
+		case exists && scheduled.job != nil:
+			created, err = s.scheduler.Update(scheduled.job.ID(), definition, task, job.options()...)
+		default:
+			created, err = s.scheduler.NewJob(definition, task, job.options()...)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to schedule %s: %w", job.label(i), err)
+		}
+
+		s.jobs[key] = scheduledJob{config: job, job: created, index: i}
+
+		if exists {
+			logger.Info("Rescheduled cron job", "name", job.Name)
+		} else {
+			logger.Debug("Scheduled cron job", "name", job.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *cronScheduler) remove(key string, scheduled scheduledJob) {
+	if scheduled.job != nil {
+		s.unschedule(scheduled)
+	}
+
+	delete(s.jobs, key)
+	delete(s.states, key)
+}
+
+// unschedule removes the gocron job of the job, leaving its state alone.
+func (s *cronScheduler) unschedule(scheduled scheduledJob) {
+	if err := s.scheduler.RemoveJob(scheduled.job.ID()); err != nil {
+		s.cron.Logger.Warning("Failed to remove cron job", "name", scheduled.config.Name, "error", err)
+	}
+}
+
+// task returns the gocron task of the job; gocron passes a context that is cancelled on shutdown.
+// forced runs, started by `cron run`, go through a pause and skip the conditions of the job.
+func (s *cronScheduler) task(key string, job CronJob, forced bool) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		s.cron.jobWrapper(s.cron.Logger, func() {
+			logger := s.cron.Logger
+
+			// interval jobs get their jitter from the schedule
+			if job.Jitter > 0 && job.Interval == 0 {
+				delay := time.Duration(rand.Int63n(int64(job.Jitter)))
+				logger.Debug("Delaying cron job", "name", job.Name, "jitter", delay)
+				if !sleepContext(ctx, delay) {
+					return
+				}
+			}
+
+			if !forced && s.paused(key) {
+				logger.Debug("Skipping paused cron job", "name", job.Name)
+				return
+			}
+
+			if !forced {
+				if met, reason := s.cron.conditionsMet(job.When, time.Now()); !met {
+					logger.Debug("Skipping cron job, condition not met", "name", job.Name, "reason", reason)
+					return
+				}
+			}
+
+			if job.Overlap == OverlapQueue {
+				queue := s.queue(key)
+				queue.Lock()
+				defer queue.Unlock()
+			}
+
+			if !s.begin(key, job) {
+				logger.Debug("Skipping cron job, a run is in progress", "name", job.Name)
+				return
+			}
+
+			s.execute(ctx, key, job, forced)
+		})()
+	}
+}
+
+// execute runs a job that has begun and records the run. A panic of the handler fails the run,
+// so the job is not left running.
+func (s *cronScheduler) execute(ctx context.Context, key string, job CronJob, forced bool) {
+	logger := s.cron.Logger
+	start := time.Now()
+
+	var output strings.Builder
+	attempts := 1
+	var err error
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		failures := s.finish(key, err)
+
+		run := newCronRun(key, start, attempts, output.String(), err)
+		run.Forced = forced
+		if err := s.history.Append(run); err != nil {
+			logger.Warning("Failed to record cron run", "name", job.Name, "error", err)
+		}
+
+		if err != nil {
+			logger.Error("Failed to execute cron job", "name", job.Name, "error", err)
+		} else {
+			logger.Info("Successfully executed cron job", "name", job.Name)
+		}
+
+		if job.NotifyAfter > 0 && failures == job.NotifyAfter {
+			s.notifyFailures(key, failures, err)
+		}
+
+		s.chain(job, err)
+	}()
+
+	logger.Debug("Running cron job", "name", job.Name, "type", job.Command, "interval", job.Interval, "schedule", job.Schedule, "at", job.At)
+
+	attempts, err = s.run(ctx, job, &output)
+}
+
+// notifyFailures raises a desktop notification about a job that keeps failing.
+func (s *cronScheduler) notifyFailures(name string, failures int, err error) {
+	summary := fmt.Sprintf("Cron job %s failed %d times in a row", name, failures)
+	if notifyErr := s.cron.notify("critical", summary, err.Error()); notifyErr != nil {
+		s.cron.Logger.Warning("Failed to send the failure notification", "name", name, "error", notifyErr)
+	}
+}
+
+// run runs the handler of job within its timeout, retrying failed runs with exponential
+// backoff. Each attempt writes to its own buffer, which is copied to output once the attempt
+// has returned. It returns the number of attempts.
+func (s *cronScheduler) run(ctx context.Context, job CronJob, output io.Writer) (int, error) {
+	attempt := func() error {
+		var buffer strings.Builder
+		err := runWithTimeout(ctx, job.Timeout, s.cron.buildCronHandlerWithOutput(s.defined, job, &buffer))
+		if output != nil {
+			io.WriteString(output, buffer.String())
+		}
+		return err
+	}
+
+	attempts := 1
+	err := attempt()
+	for ; err != nil && attempts <= job.Retries; attempts++ {
+		delay := job.backoff(attempts)
+		s.cron.Logger.Warning("Retrying cron job", "name", job.Name, "attempt", attempts, "retries", job.Retries, "delay", delay, "error", err)
+
+		if !sleepContext(ctx, delay) {
+			return attempts, fmt.Errorf("stopped before retrying: %w", err)
+		}
+
+		err = attempt()
+	}
+
+	if err != nil && job.Retries > 0 {
+		return attempts, fmt.Errorf("failed after %d attempts: %w", attempts, err)
+	}
+
+	return attempts, err
+}
+
+// runWithTimeout runs handler with a context that is done after timeout, when it is set.
+// It returns only once the handler has, so handlers must stop when their context is done;
+// shell commands are killed.
+func runWithTimeout(ctx context.Context, timeout time.Duration, handler CronHandler) error {
+	if timeout <= 0 {
+		return handler(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := handler(attemptCtx)
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case attemptCtx.Err() != nil:
+		return fmt.Errorf("timed out after %s", timeout)
+	default:
+		return err
+	}
+}
+
+// sleepContext waits for delay, returning false when ctx is done first.
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *cronScheduler) state(key string) *jobState {
+	state, ok := s.states[key]
+	if !ok {
+		state = &jobState{}
+		s.states[key] = state
+	}
+
+	return state
+}
+
+// paused reports whether the scheduled runs of the job are paused.
+func (s *cronScheduler) paused(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state(key).paused
+}
+
+// queue returns the lock the runs of a queue job take in turn.
+func (s *cronScheduler) queue(key string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &s.state(key).queue
+}
+
+// begin records the start of a run. gocron keeps the scheduled runs of singleton and
+// skip-if-running jobs apart, but not the manual ones, so those are refused here.
+func (s *cronScheduler) begin(key string, job CronJob) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state(key)
+	if state.running > 0 && job.exclusive() {
+		return false
+	}
+
+	state.running++
+	state.lastRun = time.Now()
+	// the jobs of the after list have to succeed again before the next run
+	state.completed = nil
+	return true
+}
+
+// finish records the end of a run and returns the number of failures in a row.
+func (s *cronScheduler) finish(key string, err error) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state(key)
+	state.running--
+	state.ran = true
+	state.lastError = ""
+	if err != nil {
+		state.lastError = err.Error()
+		state.failures++
+	} else {
+		state.failures = 0
+	}
+
+	return state.failures
+}
+
+// List returns the status of every job, in the order of the config file.
+func (s *cronScheduler) List() []CronJobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]CronJobStatus, 0, len(s.jobs))
+	for key, scheduled := range s.jobs {
+		state := s.state(key)
+		status := CronJobStatus{
+			Name:     key,
+			Schedule: scheduled.config.describe(),
+			LastRun:  state.lastRun,
+			Running:  state.running > 0,
+			Paused:   state.paused,
+		}
+
+		if scheduled.job != nil && !state.paused {
+			if next, err := scheduled.job.NextRun(); err == nil {
+				status.NextRun = next
+			}
+		}
+
+		switch {
+		case !state.ran:
+		case state.lastError != "":
+			status.LastResult = state.lastError
+		default:
+			status.LastResult = "ok"
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	slices.SortFunc(statuses, func(a, b CronJobStatus) int {
+		return s.jobs[a.Name].index - s.jobs[b.Name].index
+	})
+
+	return statuses
+}
+
+// RunNow runs the job now, even when it is paused. The run is a one-shot job of its own, so it
+// leaves the schedule of the job alone.
+func (s *cronScheduler) RunNow(name string) error {
+	s.mu.Lock()
+	scheduled, ok := s.jobs[name]
+	busy := ok && s.state(name).running > 0 && scheduled.config.exclusive()
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no cron job named '%s'", name)
+	}
+
+	if busy {
+		return fmt.Errorf("cron job '%s' is already running", name)
+	}
+
+	if err := s.runOnce(name, scheduled.config, true); err != nil {
+		return fmt.Errorf("failed to run cron job '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// runOnce runs the job once through a one-shot gocron job, removed when the run is over.
+func (s *cronScheduler) runOnce(key string, job CronJob, forced bool) error {
+	created := make(chan gocron.Job, 1)
+	run := s.task(key, job, forced)
+
+	task := gocron.NewTask(func(ctx context.Context) {
+		run(ctx)
+
+		once := <-created
+		if err := s.scheduler.RemoveJob(once.ID()); err != nil {
+			s.cron.Logger.Debug("Failed to remove one-shot cron run", "name", job.Name, "error", err)
+		}
+	})
+
+	once, err := s.scheduler.NewJob(gocron.OneTimeJob(gocron.OneTimeJobStartImmediately()), task, gocron.WithName(job.Name))
+	if err != nil {
+		return err
+	}
+	created <- once
+
+	return nil
+}
+
+// Pause stops the scheduled runs of the job until it is resumed; paused is false to resume.
+func (s *cronScheduler) Pause(name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; !ok {
+		return fmt.Errorf("no cron job named '%s'", name)
+	}
+
+	s.state(name).paused = paused
+	return nil
+}
+
+// Handle answers the requests of the control socket.
+func (s *cronScheduler) Handle(request control.Request) (any, error) {
+	switch request.Command {
+	case "list":
+		return s.List(), nil
+	case "run":
+		return nil, s.RunNow(request.Target)
+	case "pause":
+		return nil, s.Pause(request.Target, true)
+	case "resume":
+		return nil, s.Pause(request.Target, false)
+	}
+
+	return nil, fmt.Errorf("unknown command '%s'", request.Command)
+}
+
+// Start starts running the scheduled jobs.
+func (s *cronScheduler) Start() {
+	s.scheduler.Start()
+}
+
+// Shutdown stops the scheduler, waiting up to cronStopTimeout for running jobs.
+func (s *cronScheduler) Shutdown() error {
+	return s.scheduler.Shutdown()
+}
+
+// jobKey identifies a job across reloads: by name, or by position when it has none.
+func jobKey(index int, job CronJob) string {
+	if job.Name == "" {
+		return fmt.Sprintf("#%d", index+1)
+	}
+
+	return job.Name
+}
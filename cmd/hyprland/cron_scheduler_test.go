@@ -0,0 +1,451 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/control"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+func buildTestCronScheduler(t *testing.T, config string) (*CronCmd, *cronScheduler) {
+	t.Helper()
+
+	cronCmd := &CronCmd{Config: config, History: filepath.Join(t.TempDir(), "cron-history.jsonl")}
+	cronCmd.SetupContext(&cmd.Context{Debug: false})
+
+	scheduler, err := newCronScheduler(cronCmd)
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	t.Cleanup(func() { scheduler.Shutdown() })
+
+	return cronCmd, scheduler
+}
+
+func scheduledNames(scheduler *cronScheduler) []string {
+	var names []string
+	for _, job := range scheduler.scheduler.Jobs() {
+		names = append(names, job.Name())
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestCronScheduler_Apply(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	err := scheduler.Apply([]CronJob{
+		{Name: "wallpaper", Type: "shell", Command: "true", Interval: time.Hour},
+		{Name: "lock", Type: "shell", Command: "true", Schedule: "@hourly"},
+		{Name: "theme", Type: "shell", Command: "true", Schedule: "@daily"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if names := scheduledNames(scheduler); !slices.Equal(names, []string{"lock", "theme", "wallpaper"}) {
+		t.Fatalf("Unexpected jobs: %v", names)
+	}
+
+	wallpaperID := scheduler.jobs["wallpaper"].job.ID()
+	lockID := scheduler.jobs["lock"].job.ID()
+
+	err = scheduler.Apply([]CronJob{
+		{Name: "wallpaper", Type: "shell", Command: "true", Interval: time.Hour},
+		{Name: "lock", Type: "shell", Command: "true", Schedule: "0 9 * * *"},
+		{Name: "reload", Type: "shell", Command: "true", Interval: time.Minute},
+		{Name: "once", Type: "shell", Command: "true", At: "2000-01-01 00:00"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if names := scheduledNames(scheduler); !slices.Equal(names, []string{"lock", "reload", "wallpaper"}) {
+		t.Fatalf("Unexpected jobs: %v", names)
+	}
+
+	if scheduler.jobs["wallpaper"].job.ID() != wallpaperID {
+		t.Error("Expected the unchanged job to be kept")
+	}
+
+	if scheduler.jobs["lock"].job.ID() != lockID || scheduler.jobs["lock"].config.Schedule != "0 9 * * *" {
+		t.Error("Expected the changed job to be rescheduled in place")
+	}
+}
+
+func TestCronCmd_reload(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "cron.yaml")
+	os.WriteFile(config, []byte("jobs:\n  - name: a\n    type: shell\n    command: 'true'\n    interval: 1h\n"), 0644)
+
+	cronCmd, scheduler := buildTestCronScheduler(t, config)
+	cronCmd.reload(scheduler)
+
+	if names := scheduledNames(scheduler); !slices.Equal(names, []string{"a"}) {
+		t.Fatalf("Unexpected jobs: %v", names)
+	}
+
+	// an invalid file keeps the running jobs
+	os.WriteFile(config, []byte("jobs:\n  - name: b\n    type: shell\n    command: 'true'\n    schedule: never\n"), 0644)
+	cronCmd.reload(scheduler)
+
+	if names := scheduledNames(scheduler); !slices.Equal(names, []string{"a"}) {
+		t.Fatalf("Unexpected jobs after an invalid reload: %v", names)
+	}
+}
+
+func TestCronCmd_serveReloadsOnChange(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "cron.yaml")
+	os.WriteFile(config, []byte("jobs:\n  - name: a\n    type: shell\n    command: 'true'\n    interval: 1h\n"), 0644)
+
+	cronCmd, scheduler := buildTestCronScheduler(t, config)
+	cronCmd.reload(scheduler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		cronCmd.serve(ctx, scheduler)
+		close(done)
+	}()
+
+	// give the watcher time to start
+	time.Sleep(100 * time.Millisecond)
+	os.WriteFile(config, []byte("jobs:\n  - name: b\n    type: shell\n    command: 'true'\n    interval: 1h\n"), 0644)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Equal(scheduledNames(scheduler), []string{"b"}) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the jobs to be reloaded, got %v", scheduledNames(scheduler))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
+
+func waitForResult(t *testing.T, scheduler *cronScheduler, name string) CronJobStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, status := range scheduler.List() {
+			if status.Name == name && status.LastResult != "" && !status.Running {
+				return status
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("Job %s did not run", name)
+	return CronJobStatus{}
+}
+
+func TestCronScheduler_Control(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	err := scheduler.Apply([]CronJob{
+		{Name: "lock", Type: "shell", Command: "true", Schedule: "@hourly"},
+		{Name: "broken", Type: "unknown", Command: "nothing", Interval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	scheduler.Start()
+
+	statuses := scheduler.List()
+	if len(statuses) != 2 || statuses[0].Name != "lock" || statuses[1].Name != "broken" {
+		t.Fatalf("Expected the jobs in config order, got %+v", statuses)
+	}
+	if statuses[0].Schedule != "@hourly" || statuses[0].NextRun.IsZero() || statuses[0].LastResult != "" {
+		t.Errorf("Unexpected status: %+v", statuses[0])
+	}
+
+	if _, err := scheduler.Handle(control.Request{Command: "pause", Target: "broken"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !scheduler.List()[1].Paused || !scheduler.List()[1].NextRun.IsZero() {
+		t.Errorf("Expected broken to be paused: %+v", scheduler.List()[1])
+	}
+
+	// a manual run goes through a pause
+	if _, err := scheduler.Handle(control.Request{Command: "run", Target: "broken"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if status := waitForResult(t, scheduler, "broken"); status.LastResult != "no cron handler defined" || status.LastRun.IsZero() {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	if !scheduler.paused("broken") {
+		t.Error("Expected scheduled runs of a paused job to be skipped")
+	}
+
+	if _, err := scheduler.Handle(control.Request{Command: "resume", Target: "broken"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if scheduler.paused("broken") {
+		t.Error("Expected a resumed job to run")
+	}
+
+	for _, request := range []control.Request{{Command: "run", Target: "missing"}, {Command: "pause", Target: "missing"}, {Command: "reboot"}} {
+		if _, err := scheduler.Handle(request); err == nil {
+			t.Errorf("Expected an error for %+v", request)
+		}
+	}
+}
+
+func TestCronScheduler_RunNowWhileBusy(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlap string
+		runs    int
+		wantErr string
+	}{
+		{"Allow", OverlapAllow, 2, ""},
+		{"Singleton", OverlapSingleton, 1, "cron job 'wallpaper' is already running"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, scheduler := buildTestCronScheduler(t, "")
+
+			started := make(chan struct{}, 10)
+			release := make(chan struct{})
+			scheduler.defined = DefinedCron{
+				"$slow": func(CronJob) CronHandler {
+					return func(context.Context) error {
+						started <- struct{}{}
+						<-release
+						return nil
+					}
+				},
+			}
+
+			job := CronJob{Name: "wallpaper", Type: "defined", Command: "$slow", Interval: 50 * time.Millisecond, Overlap: tt.overlap}
+			if err := scheduler.Apply([]CronJob{job}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := scheduler.Pause("wallpaper", true); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			scheduler.Start()
+
+			if err := scheduler.RunNow("wallpaper"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for the manual run")
+			}
+
+			err := scheduler.RunNow("wallpaper")
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+
+			close(release)
+
+			// the scheduled ticks of the paused job stay skipped once the manual runs are over
+			time.Sleep(300 * time.Millisecond)
+			if runs := 1 + len(started); runs != tt.runs {
+				t.Errorf("Expected %d runs, got %d", tt.runs, runs)
+			}
+
+			if status := scheduler.List()[0]; status.Running {
+				t.Errorf("Expected no run in progress, got %+v", status)
+			}
+
+			if jobs := scheduler.scheduler.Jobs(); len(jobs) != 1 {
+				t.Errorf("Expected the one-shot runs to be removed, got %d jobs", len(jobs))
+			}
+		})
+	}
+}
+
+func TestCronScheduler_taskPanic(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	scheduler.defined = DefinedCron{
+		"$panic": func(CronJob) CronHandler {
+			return func(context.Context) error {
+				panic("boom")
+			}
+		},
+	}
+
+	job := CronJob{Name: "broken", Type: "defined", Command: "$panic", Interval: time.Hour, Overlap: OverlapSkipIfRunning}
+	if err := scheduler.Apply([]CronJob{job}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	scheduler.task("broken", job, false)(t.Context())
+
+	if status := scheduler.List()[0]; status.Running || status.LastResult != "panic: boom" {
+		t.Errorf("Expected a failed run that is over, got %+v", status)
+	}
+
+	if err := scheduler.RunNow("broken"); err != nil {
+		t.Errorf("Expected the job to run again, got %v", err)
+	}
+}
+
+func TestCronScheduler_runRetries(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	calls := 0
+	scheduler.defined = DefinedCron{
+		"$flaky": func(CronJob) CronHandler {
+			return func(context.Context) error {
+				calls++
+				if calls < 3 {
+					return errors.New("not yet")
+				}
+				return nil
+			}
+		},
+	}
+
+	tests := []struct {
+		name      string
+		retries   int
+		wantCalls int
+		wantErr   string
+	}{
+		{"NoRetries", 0, 1, "not yet"},
+		{"NotEnough", 1, 2, "failed after 2 attempts: not yet"},
+		{"Recovers", 5, 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			job := CronJob{Name: "flaky", Type: "defined", Command: "$flaky", Retries: tt.retries, Backoff: time.Millisecond}
+
+			attempts, err := scheduler.run(context.Background(), job, nil)
+			if calls != tt.wantCalls || attempts != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d calls and %d attempts", tt.wantCalls, calls, attempts)
+			}
+
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	if err := runWithTimeout(context.Background(), 20*time.Millisecond, slow); err == nil || err.Error() != "timed out after 20ms" {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+
+	if err := runWithTimeout(context.Background(), time.Second, func(context.Context) error { return errors.New("failed") }); err == nil || err.Error() != "failed" {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runWithTimeout(ctx, time.Minute, slow); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled run, got %v", err)
+	}
+}
+
+// stuckRunner runs commands that only stop once they are killed, writing output on the way out.
+type stuckRunner struct {
+	shell.Runner
+	running    atomic.Int32
+	overlapped atomic.Bool
+}
+
+func (r *stuckRunner) RunCombinedOutput(args shell.RunnerExecutionArgs) (string, error) {
+	if r.running.Add(1) > 1 {
+		r.overlapped.Store(true)
+	}
+	defer r.running.Add(-1)
+
+	<-args.Context.Done()
+	time.Sleep(10 * time.Millisecond)
+
+	return "killed\n", args.Context.Err()
+}
+
+func TestCronScheduler_runTimeout(t *testing.T) {
+	cronCmd, scheduler := buildTestCronScheduler(t, "")
+
+	runner := &stuckRunner{}
+	cronCmd.Shell = runner
+
+	job := CronJob{Name: "stuck", Type: "shell", Command: "sleep 60", Timeout: 20 * time.Millisecond, Retries: 1, Backoff: time.Millisecond}
+
+	var output strings.Builder
+	attempts, err := scheduler.run(t.Context(), job, &output)
+	if attempts != 2 || err == nil || err.Error() != "failed after 2 attempts: timed out after 20ms" {
+		t.Errorf("Expected two timed out attempts, got %d attempts and %v", attempts, err)
+	}
+
+	if runner.overlapped.Load() {
+		t.Error("Expected the timed out attempt to stop before the retry")
+	}
+
+	// the attempts are over, so their output is complete
+	if output.String() != "killed\nkilled\n" {
+		t.Errorf("Expected the output of both attempts, got %q", output.String())
+	}
+}
+
+func TestCronScheduler_Overlap(t *testing.T) {
+	_, scheduler := buildTestCronScheduler(t, "")
+
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	scheduler.defined = DefinedCron{
+		"$slow": func(CronJob) CronHandler {
+			return func(context.Context) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			}
+		},
+	}
+
+	err := scheduler.Apply([]CronJob{
+		{Name: "slow", Type: "defined", Command: "$slow", Interval: time.Hour, Overlap: OverlapSkipIfRunning, RunOnStart: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	scheduler.Start()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected run_on_start to run the job")
+	}
+
+	// the job is still running, so the manual run is skipped
+	scheduler.RunNow("slow")
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	select {
+	case <-started:
+		t.Error("Expected the overlapping run to be skipped")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,173 @@
+package hyprland
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	formatters "github.com/williampsena/ebenezer-cli/internal/cmd/formatters"
+)
+
+// cronArgSchemer is implemented by args with a JSON Schema that cannot be derived from their type.
+type cronArgSchemer interface {
+	CronArgSchema() map[string]any
+}
+
+var schemerType = reflect.TypeFor[cronArgSchemer]()
+
+type CronSchemaCmd struct {
+	HyprlandCmd
+}
+
+func (c *CronSchemaCmd) Run(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	data, err := json.MarshalIndent(cronSchema(), "", "  ")
+	if err != nil {
+		c.Logger.Error("Failed to encode the cron schema", "error", err)
+		return err
+	}
+
+	return formatters.WriteToStdout(string(data) + "\n")
+}
+
+// cronSchema returns a JSON Schema of cron.yaml, with the args of each defined handler.
+func cronSchema() map[string]any {
+	job := cronTypeSchema(reflect.TypeFor[CronJob](), false)
+	job["required"] = []string{"type", "command"}
+
+	properties := job["properties"].(map[string]any)
+	properties["type"].(map[string]any)["enum"] = []string{"shell", "defined"}
+	properties["overlap"].(map[string]any)["enum"] = []string{OverlapAllow, OverlapSingleton, OverlapSkipIfRunning, OverlapQueue}
+	properties["when"].(map[string]any)["properties"].(map[string]any)["power"].(map[string]any)["enum"] = []string{PowerAC, PowerBattery}
+
+	commands := slices.Sorted(maps.Keys(definedCronHandlers))
+	rules := []any{map[string]any{
+		"if":   cronSchemaIf(map[string]any{"type": map[string]any{"const": "defined"}}),
+		"then": map[string]any{"properties": map[string]any{"command": map[string]any{"enum": commands}}},
+	}}
+
+	for _, command := range commands {
+		handler := definedCronHandlers[command]
+
+		args := cronTypeSchema(reflect.TypeOf(handler.Args()), true)
+		args["description"] = handler.Help
+
+		rules = append(rules, map[string]any{
+			"if": cronSchemaIf(map[string]any{
+				"type":    map[string]any{"const": "defined"},
+				"command": map[string]any{"const": command},
+			}),
+			"then": map[string]any{"properties": map[string]any{"args": args}},
+		})
+	}
+	job["allOf"] = rules
+
+	return map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "ebenezer-cli cron jobs",
+		"type":                 "object",
+		"properties":           map[string]any{"jobs": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/job"}}},
+		"additionalProperties": false,
+		"$defs":                map[string]any{"job": job},
+	}
+}
+
+func cronSchemaIf(properties map[string]any) map[string]any {
+	return map[string]any{"properties": properties, "required": slices.Sorted(maps.Keys(properties))}
+}
+
+// cronTypeSchema derives the schema of a type from its kind and its yaml, help, default, enum
+// and required tags. args accepts the shapes decodeCronArgs does, such as a string for a list
+// or seconds for a duration; yaml.v3 only takes strings for the durations of the job itself.
+func cronTypeSchema(t reflect.Type, args bool) map[string]any {
+	if reflect.PointerTo(t).Implements(schemerType) {
+		return reflect.New(t).Interface().(cronArgSchemer).CronArgSchema()
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return cronTypeSchema(t.Elem(), args)
+	}
+
+	switch {
+	case t == durationType && args:
+		return map[string]any{"type": []string{"string", "integer"}, "description": "A duration such as 30m, or seconds"}
+	case t == durationType:
+		return map[string]any{"type": "string", "description": "A duration such as 30m"}
+	case t.Kind() == reflect.String:
+		return map[string]any{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case t.Kind() == reflect.Int:
+		return map[string]any{"type": "integer"}
+	case t.Kind() == reflect.Float64:
+		return map[string]any{"type": "number"}
+	case t.Kind() == reflect.Slice:
+		list := map[string]any{"type": "array", "items": cronTypeSchema(t.Elem(), args)}
+		if args && t.Elem().Kind() == reflect.String {
+			return map[string]any{"anyOf": []any{list, map[string]any{"type": "string"}}}
+		}
+		return list
+	case t.Kind() == reflect.Map && t.Elem().Kind() == reflect.String:
+		return map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}
+	case t.Kind() == reflect.Map:
+		return map[string]any{"type": "object"}
+	case t.Kind() == reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			property := cronTypeSchema(field.Type, args)
+			if help, ok := field.Tag.Lookup("help"); ok {
+				property["description"] = help
+			}
+			if enum, ok := field.Tag.Lookup("enum"); ok {
+				property["enum"] = strings.Split(enum, ",")
+			}
+			if value, ok := field.Tag.Lookup("default"); ok {
+				property["default"] = cronSchemaDefault(field.Type, value)
+			}
+			if _, ok := field.Tag.Lookup("required"); ok {
+				required = append(required, name)
+			}
+
+			properties[name] = property
+		}
+
+		schema := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]any{}
+	}
+}
+
+// cronSchemaDefault converts a default tag to the JSON type of the field.
+func cronSchemaDefault(t reflect.Type, value string) any {
+	switch {
+	case t == durationType:
+		return value
+	case t.Kind() == reflect.Bool:
+		parsed, _ := strconv.ParseBool(value)
+		return parsed
+	case t.Kind() == reflect.Int:
+		parsed, _ := strconv.Atoi(value)
+		return parsed
+	case t.Kind() == reflect.Slice:
+		return strings.Split(value, ",")
+	default:
+		return value
+	}
+}
@@ -0,0 +1,144 @@
+package hyprland
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestCronSchema(t *testing.T) {
+	data, err := json.Marshal(cronSchema())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var schema struct {
+		Defs struct {
+			Job struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+				AllOf      []struct {
+					If struct {
+						Properties struct {
+							Command struct {
+								Const string `json:"const"`
+							} `json:"command"`
+						} `json:"properties"`
+					} `json:"if"`
+					Then struct {
+						Properties struct {
+							Args struct {
+								Properties map[string]struct {
+									Type    interface{}   `json:"type"`
+									Enum    []string      `json:"enum"`
+									Default interface{}   `json:"default"`
+									AnyOf   []interface{} `json:"anyOf"`
+									OneOf   []interface{} `json:"oneOf"`
+								} `json:"properties"`
+							} `json:"args"`
+						} `json:"properties"`
+					} `json:"then"`
+				} `json:"allOf"`
+			} `json:"job"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"name", "type", "command", "args", "interval", "when", "after"} {
+		if _, ok := schema.Defs.Job.Properties[name]; !ok {
+			t.Errorf("Expected the job property %s", name)
+		}
+	}
+
+	handlers := map[string]bool{}
+	for _, rule := range schema.Defs.Job.AllOf {
+		command := rule.If.Properties.Command.Const
+		if command == "" {
+			continue
+		}
+		handlers[command] = true
+
+		args := rule.Then.Properties.Args.Properties
+		switch command {
+		case "$reload":
+			if component := args["component"]; len(component.Enum) != 3 || component.Default != "all" {
+				t.Errorf("Unexpected component arg: %+v", component)
+			}
+		case "$battery_check":
+			if threshold := args["threshold"]; threshold.Type != "integer" || threshold.Default != float64(20) {
+				t.Errorf("Unexpected threshold arg: %+v", threshold)
+			}
+		case "$set_random_wallpaper":
+			if len(args["schedule"].OneOf) != 2 {
+				t.Errorf("Expected the schedule arg to be a file or a schedule, got %+v", args["schedule"])
+			}
+		case "$disk_space_check":
+			if len(args["paths"].AnyOf) != 2 {
+				t.Errorf("Expected the paths arg to be a list or a string, got %+v", args["paths"])
+			}
+		}
+	}
+
+	for command := range definedCronHandlers {
+		if !handlers[command] {
+			t.Errorf("Expected the args of %s in the schema", command)
+		}
+	}
+}
+
+func TestCronSchema_durations(t *testing.T) {
+	job := cronSchema()["$defs"].(map[string]any)["job"].(map[string]any)
+	properties := job["properties"].(map[string]any)
+	when := properties["when"].(map[string]any)["properties"].(map[string]any)
+
+	fields := map[string]map[string]any{
+		"interval":        properties["interval"].(map[string]any),
+		"timeout":         properties["timeout"].(map[string]any),
+		"backoff":         properties["backoff"].(map[string]any),
+		"jitter":          properties["jitter"].(map[string]any),
+		"when.idle_above": when["idle_above"].(map[string]any),
+		"when.idle_below": when["idle_below"].(map[string]any),
+	}
+	samples := map[string]any{"string": "30m", "integer": 30}
+
+	for field, property := range fields {
+		types, ok := property["type"].([]string)
+		if !ok {
+			types = []string{property["type"].(string)}
+		}
+
+		// every value the schema accepts has to load
+		for _, kind := range types {
+			t.Run(field+"/"+kind, func(t *testing.T) {
+				cronJob := map[string]any{"name": "sync", "type": "shell", "command": "true", "interval": "2h", "retries": 1}
+				if name, ok := strings.CutPrefix(field, "when."); ok {
+					cronJob["when"] = map[string]any{name: samples[kind]}
+				} else {
+					cronJob[field] = samples[kind]
+				}
+
+				data, err := yaml.Marshal(map[string]any{"jobs": []any{cronJob}})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				config := filepath.Join(t.TempDir(), "cron.yaml")
+				if err := os.WriteFile(config, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				cronCmd := &CronCmd{Config: config}
+				cronCmd.SetupContext(&cmd.Context{Debug: false})
+				if _, err := cronCmd.parseCrons(); err != nil {
+					t.Errorf("Expected the schema-valid config to load:\n%s\ngot %v", data, err)
+				}
+			})
+		}
+	}
+}
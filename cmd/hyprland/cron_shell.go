@@ -0,0 +1,183 @@
+package hyprland
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+// CronShellData holds the variables of the templates in the command, env and dir of shell
+// jobs, such as "backup-{{.Date}}.tar" or "{{.Time.Format \"15h04\"}}".
+type CronShellData struct {
+	Job      string
+	Time     time.Time
+	Hostname string
+	User     string
+
+	monitor func() string
+}
+
+// Date returns the day of the run as 2006-01-02.
+func (d CronShellData) Date() string {
+	return d.Time.Format(time.DateOnly)
+}
+
+// Clock returns the time of the run as 15:04.
+func (d CronShellData) Clock() string {
+	return d.Time.Format("15:04")
+}
+
+// Monitor returns the name of the focused monitor, looked up only when a template uses it.
+func (d CronShellData) Monitor() string {
+	if d.monitor == nil {
+		return ""
+	}
+
+	return d.monitor()
+}
+
+// shellData returns the template variables of a run of job.
+func (w *CronCmd) shellData(cronJob CronJob, now time.Time) CronShellData {
+	data := CronShellData{Job: cronJob.Name, Time: now, User: os.Getenv("USER"), monitor: w.focusedMonitor}
+
+	if hostname, err := os.Hostname(); err == nil {
+		data.Hostname = hostname
+	}
+
+	if current, err := user.Current(); err == nil {
+		data.User = current.Username
+	}
+
+	return data
+}
+
+func (w *CronCmd) focusedMonitor() string {
+	monitors, err := w.IPC.Monitors()
+	if err != nil {
+		w.Logger.Warning("Failed to get the focused monitor", "error", err)
+		return ""
+	}
+
+	for _, monitor := range monitors {
+		if monitor.Focused {
+			return monitor.Name
+		}
+	}
+
+	return ""
+}
+
+// renderShellTemplate expands the template variables of text; text without "{{" is returned as is.
+func renderShellTemplate(name, text string, data CronShellData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to expand %s: %w", name, err)
+	}
+
+	return out.String(), nil
+}
+
+// shellExecution expands the templates of job and returns how to run it: split into words, or
+// through sh -c when Shell is set. env is added after the env of the job.
+func (w *CronCmd) shellExecution(cronJob CronJob, env []string, data CronShellData) (shell.RunnerExecutionArgs, error) {
+	execution := shell.RunnerExecutionArgs{Timeout: int(math.Ceil(cronJob.Timeout.Seconds()))}
+
+	command, err := renderShellTemplate("command", cronJob.Command, data)
+	if err != nil {
+		return execution, err
+	}
+
+	variables := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(cronJob.Env)) {
+		value, err := renderShellTemplate("env "+name, cronJob.Env[name], data)
+		if err != nil {
+			return execution, err
+		}
+
+		variables[name] = os.ExpandEnv(value)
+		execution.Env = append(execution.Env, name+"="+variables[name])
+	}
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		variables[name] = value
+	}
+	execution.Env = append(execution.Env, env...)
+
+	if cronJob.Dir != "" {
+		dir, err := renderShellTemplate("dir", cronJob.Dir, data)
+		if err != nil {
+			return execution, err
+		}
+		execution.Dir = core.ResolvePath(os.ExpandEnv(dir))
+	}
+
+	if cronJob.Shell {
+		execution.Command = "sh"
+		execution.Args = []string{"-c", command}
+		return execution, nil
+	}
+
+	words, err := shell.Split(command, func(name string) (string, bool) {
+		if value, ok := variables[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	})
+	if errors.Is(err, shell.ErrShellSyntax) {
+		return execution, fmt.Errorf("%w, set shell: true", err)
+	}
+	if err != nil {
+		return execution, err
+	}
+
+	if len(words) == 0 {
+		return execution, fmt.Errorf("empty command")
+	}
+
+	execution.Command = words[0]
+	execution.Args = words[1:]
+
+	return execution, nil
+}
+
+// validateShell checks the shell options and templates of the job at load time.
+func (c CronJob) validateShell() error {
+	if c.Type != "shell" {
+		if c.Shell || len(c.Env) > 0 || c.Dir != "" {
+			return fmt.Errorf("shell, env and dir only apply to shell jobs")
+		}
+		return nil
+	}
+
+	for name := range c.Env {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid env variable name '%s'", name)
+		}
+	}
+
+	// a dry run with sample values catches template and quoting mistakes
+	cronCmd := &CronCmd{}
+	data := CronShellData{Job: c.Name, Time: time.Now(), Hostname: "host", User: "user"}
+	_, err := cronCmd.shellExecution(c, nil, data)
+
+	return err
+}
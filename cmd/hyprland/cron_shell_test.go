@@ -0,0 +1,176 @@
+package hyprland
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+func TestCronCmd_shellExecution(t *testing.T) {
+	cronCmd := &CronCmd{}
+	cronCmd.SetupContext(&cmd.Context{Debug: false})
+	cronCmd.IPC = hyprland.NewIPCClientMock(cronCmd.Logger, []hyprland.Monitor{{Name: "eDP-1"}, {Name: "DP-2", Focused: true}})
+
+	home, _ := os.UserHomeDir()
+	data := cronCmd.shellData(CronJob{Name: "backup"}, time.Date(2025, time.March, 4, 9, 30, 0, 0, time.Local))
+	data.Hostname = "desk"
+
+	tests := []struct {
+		name     string
+		job      CronJob
+		env      []string
+		wantCmd  string
+		wantArgs []string
+		wantEnv  []string
+		wantDir  string
+		wantErr  string
+	}{
+		{
+			name:     "Quotes",
+			job:      CronJob{Command: `notify-send "Backup done" 'on $HOSTNAME'`},
+			wantCmd:  "notify-send",
+			wantArgs: []string{"Backup done", "on $HOSTNAME"},
+		},
+		{
+			name:     "Templates",
+			job:      CronJob{Command: `tar -czf ~/backups/{{.Hostname}}-{{.Date}}.tgz --label "{{.Job}} {{.Clock}} {{.Monitor}}"`},
+			wantCmd:  "tar",
+			wantArgs: []string{"-czf", home + "/backups/desk-2025-03-04.tgz", "--label", "backup 09:30 DP-2"},
+		},
+		{
+			name:     "Env",
+			job:      CronJob{Command: "echo $TARGET", Env: map[string]string{"TARGET": "/mnt/{{.Hostname}}", "MODE": "full"}},
+			env:      []string{"HYPRLAND_EVENT=monitoradded"},
+			wantCmd:  "echo",
+			wantArgs: []string{"/mnt/desk"},
+			wantEnv:  []string{"MODE=full", "TARGET=/mnt/desk", "HYPRLAND_EVENT=monitoradded"},
+		},
+		{
+			name:     "Shell",
+			job:      CronJob{Command: "ls {{.Date}} | wc -l > /tmp/count", Shell: true, Dir: "~/backups"},
+			wantCmd:  "sh",
+			wantArgs: []string{"-c", "ls 2025-03-04 | wc -l > /tmp/count"},
+			wantDir:  home + "/backups",
+		},
+		{
+			name:    "ShellSyntax",
+			job:     CronJob{Command: "ls | wc -l"},
+			wantErr: "set shell: true",
+		},
+		{
+			name:    "UnknownVariable",
+			job:     CronJob{Command: "echo {{.Weather}}"},
+			wantErr: "can't evaluate field Weather",
+		},
+		{
+			name:    "Empty",
+			job:     CronJob{Command: " "},
+			wantErr: "empty command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution, err := cronCmd.shellExecution(tt.job, tt.env, data)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if execution.Command != tt.wantCmd || !slices.Equal(execution.Args, tt.wantArgs) {
+				t.Errorf("Expected %s %q, got %s %q", tt.wantCmd, tt.wantArgs, execution.Command, execution.Args)
+			}
+
+			if !slices.Equal(execution.Env, tt.wantEnv) {
+				t.Errorf("Expected env %q, got %q", tt.wantEnv, execution.Env)
+			}
+
+			if execution.Dir != tt.wantDir {
+				t.Errorf("Expected dir %q, got %q", tt.wantDir, execution.Dir)
+			}
+		})
+	}
+}
+
+func TestCronJob_validateShell(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     CronJob
+		wantErr string
+	}{
+		{"Valid", CronJob{Type: "shell", Command: `notify-send "{{.Date}}"`, Env: map[string]string{"A": "1"}}, ""},
+		{"ShellMode", CronJob{Type: "shell", Command: "ls | wc -l", Shell: true}, ""},
+		{"Pipe", CronJob{Type: "shell", Command: "ls | wc -l"}, "set shell: true"},
+		{"Quote", CronJob{Type: "shell", Command: "echo 'oops"}, "unterminated single quote"},
+		{"Template", CronJob{Type: "shell", Command: "echo {{.Date"}, "invalid command template"},
+		{"EnvName", CronJob{Type: "shell", Command: "true", Env: map[string]string{"A=B": "1"}}, "invalid env variable name"},
+		{"Defined", CronJob{Type: "defined", Command: "$set_random_wallpaper", Dir: "/tmp"}, "only apply to shell jobs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.job.validateShell()
+
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// recordingRunner keeps the commands it is asked to run.
+type recordingRunner struct {
+	shell.Runner
+	executions []shell.RunnerExecutionArgs
+}
+
+func (r *recordingRunner) RunCombinedOutput(args shell.RunnerExecutionArgs) (string, error) {
+	r.executions = append(r.executions, args)
+	return "done\n", nil
+}
+
+func TestCronCmd_runShellCapture(t *testing.T) {
+	cronCmd := &CronCmd{}
+	cronCmd.SetupContext(&cmd.Context{Debug: false})
+
+	runner := &recordingRunner{}
+	cronCmd.Shell = runner
+
+	var output strings.Builder
+	job := CronJob{Name: "sync", Type: "shell", Command: `rsync -a "My Documents" /mnt`, Dir: "/tmp", Timeout: 90 * time.Second}
+	if err := cronCmd.runShellCapture(job, nil, &output)(t.Context()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(runner.executions) != 1 {
+		t.Fatalf("Expected one execution, got %d", len(runner.executions))
+	}
+
+	execution := runner.executions[0]
+	if execution.Command != "rsync" || !slices.Equal(execution.Args, []string{"-a", "My Documents", "/mnt"}) || execution.Dir != "/tmp" || execution.Timeout != 90 {
+		t.Errorf("Unexpected execution: %+v", execution)
+	}
+
+	if output.String() != "done\n" {
+		t.Errorf("Expected the output to be captured, got %q", output.String())
+	}
+
+	job.Command = "echo 'unterminated"
+	if err := cronCmd.runShellCapture(job, nil, nil)(t.Context()); err == nil || errors.Is(err, shell.ErrShellSyntax) {
+		t.Errorf("Expected a quoting error, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type EventRules struct {
+	Rules []EventRule `yaml:"rules"`
+}
+
+type EventRule struct {
+	Name    string                 `yaml:"name"`
+	Event   string                 `yaml:"event"`
+	Match   string                 `yaml:"match,omitempty"`
+	Type    string                 `yaml:"type"`
+	Command string                 `yaml:"command"`
+	Args    map[string]interface{} `yaml:"args,omitempty"`
+	pattern *regexp.Regexp
+}
+
+type EventsCmd struct {
+	HyprlandCmd
+	Config    string `arg:"" help:"Path to the event rules file" default:"~/.config/hypr/events.yaml"`
+	Reconnect int    `help:"Seconds to wait before reconnecting to the event socket" default:"2"`
+	rules     []EventRule
+	cron      *CronCmd
+	defined   DefinedCron
+}
+
+func (e *EventsCmd) Run(ctx *cmd.Context) error {
+	e.SetupContext(ctx)
+
+	rules, err := e.parseRules()
+	if err != nil {
+		e.Logger.Error("Failed to load event rules", "error", err)
+		return err
+	}
+
+	e.setupRules(rules)
+
+	e.Logger.Info("Listening for Hyprland events", "rules", len(e.rules))
+
+	listenCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return e.Listen(listenCtx)
+}
+
+// Listen streams events until ctx is cancelled, reconnecting when Hyprland closes the socket.
+func (e *EventsCmd) Listen(ctx context.Context) error {
+	for {
+		err := e.IPC.Events(ctx, e.Dispatch)
+		if ctx.Err() != nil {
+			e.Logger.Info("Stopping event listener")
+			return nil
+		}
+
+		if errors.Is(err, hyprland.ErrNoInstance) {
+			return err
+		}
+
+		e.Logger.Warning("Event stream interrupted, reconnecting", "error", err, "seconds", e.Reconnect)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Duration(e.Reconnect) * time.Second):
+		}
+	}
+}
+
+func (e *EventsCmd) setupRules(rules []EventRule) {
+	e.rules = rules
+	e.cron = &CronCmd{HyprlandCmd: e.HyprlandCmd}
+	e.defined = e.cron.buildDefinedCrons()
+}
+
+// Dispatch runs every rule matching event. Handlers run in their own goroutine so a slow
+// command never blocks the event socket.
+func (e *EventsCmd) Dispatch(event hyprland.Event) {
+	e.Logger.Debug("Received event", "event", event.String())
+
+	for _, rule := range e.rules {
+		if !rule.Matches(event) {
+			continue
+		}
+
+		handler := e.buildHandler(rule, event)
+
+		go e.cron.jobWrapper(e.Logger, func() {
+			if err := handler(context.Background()); err != nil {
+				e.Logger.Error("Failed to execute event rule", "name", rule.Name, "event", event.Name, "error", err)
+			} else {
+				e.Logger.Info("Successfully executed event rule", "name", rule.Name, "event", event.Name)
+			}
+		})()
+	}
+}
+
+func (e *EventsCmd) buildHandler(rule EventRule, event hyprland.Event) CronHandler {
+	cronJob := CronJob{
+		Name:    rule.Name,
+		Type:    rule.Type,
+		Command: rule.Command,
+		Args:    rule.Args,
+	}
+
+	if rule.Type == "shell" {
+		return e.cron.runShellCapture(cronJob, []string{
+			"HYPRLAND_EVENT=" + event.Name,
+			"HYPRLAND_EVENT_DATA=" + event.Data,
+		}, nil)
+	}
+
+	return e.cron.buildCronHandler(e.defined, cronJob)
+}
+
+// Matches reports whether the rule applies to event. An empty or "*" event matches any event.
+func (r *EventRule) Matches(event hyprland.Event) bool {
+	if r.Event != "" && r.Event != "*" && r.Event != event.Name {
+		return false
+	}
+
+	return r.pattern == nil || r.pattern.MatchString(event.Data)
+}
+
+func (e *EventsCmd) parseRules() ([]EventRule, error) {
+	if e.Config == "" {
+		return nil, fmt.Errorf("configuration file path is empty")
+	}
+
+	e.Config = core.ResolvePath(e.Config)
+
+	data, err := os.ReadFile(e.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file '%s': %w", e.Config, err)
+	}
+
+	return ParseEventRules(data)
+}
+
+// ParseEventRules decodes and validates the YAML event rules.
+func ParseEventRules(data []byte) ([]EventRule, error) {
+	var config EventRules
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml file: %w", err)
+	}
+
+	if len(config.Rules) == 0 {
+		return nil, fmt.Errorf("no event rules found")
+	}
+
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+
+		if rule.Type != "shell" && rule.Type != "defined" {
+			return nil, fmt.Errorf("rule '%s': unsupported type '%s' (use 'shell' or 'defined')", rule.Name, rule.Type)
+		}
+
+		if rule.Command == "" {
+			return nil, fmt.Errorf("rule '%s': command is required", rule.Name)
+		}
+
+		cronJob := CronJob{Type: rule.Type, Command: rule.Command, Args: rule.Args}
+		if err := cronJob.validateHandler(); err != nil {
+			return nil, fmt.Errorf("rule '%s': %w", rule.Name, err)
+		}
+		if err := cronJob.validateArgs(); err != nil {
+			return nil, fmt.Errorf("rule '%s': %w", rule.Name, err)
+		}
+
+		if rule.Match != "" {
+			pattern, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return nil, fmt.Errorf("rule '%s': invalid match expression: %w", rule.Name, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+
+	return config.Rules, nil
+}
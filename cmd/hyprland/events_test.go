@@ -0,0 +1,130 @@
+package hyprland
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+)
+
+func TestParseEventRules(t *testing.T) {
+	t.Run("ValidRules", func(t *testing.T) {
+		rules, err := ParseEventRules([]byte(`
+rules:
+  - name: new-monitor
+    event: monitoradded
+    match: "^HDMI"
+    type: defined
+    command: $set_random_wallpaper
+    args:
+      path: ~/Pictures/Wallpapers
+  - event: workspace
+    type: shell
+    command: notify-send workspace
+`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(rules) != 2 {
+			t.Fatalf("Expected 2 rules, got %d", len(rules))
+		}
+
+		if rules[1].Name != "rule-2" {
+			t.Errorf("Expected generated name 'rule-2', got '%s'", rules[1].Name)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		yaml     string
+		errorMsg string
+	}{
+		{"NoRules", "rules: []", "no event rules found"},
+		{"InvalidType", "rules:\n  - name: x\n    type: lua\n    command: foo", "unsupported type 'lua'"},
+		{"MissingCommand", "rules:\n  - name: x\n    type: shell", "command is required"},
+		{"InvalidArgs", "rules:\n  - name: x\n    type: defined\n    command: $reload\n    args:\n      component: kitty", "rule 'x': args.component: invalid value 'kitty'"},
+		{"UnknownHandler", "rules:\n  - name: x\n    type: defined\n    command: $set_random_wallpapers", "rule 'x': unknown defined handler '$set_random_wallpapers'"},
+		{"InvalidRegex", "rules:\n  - name: x\n    type: shell\n    command: ls\n    match: \"[\"", "invalid match expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseEventRules([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing '%s', got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestEventRule_Matches(t *testing.T) {
+	rules, err := ParseEventRules([]byte(`
+rules:
+  - name: hdmi
+    event: monitoradded
+    match: "^HDMI"
+    type: shell
+    command: "true"
+  - name: any
+    event: "*"
+    type: shell
+    command: "true"
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		event    hyprland.Event
+		hdmi     bool
+		wildcard bool
+	}{
+		{hyprland.Event{Name: "monitoradded", Data: "HDMI-A-1"}, true, true},
+		{hyprland.Event{Name: "monitoradded", Data: "DP-1"}, false, true},
+		{hyprland.Event{Name: "workspace", Data: "HDMI"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event.String(), func(t *testing.T) {
+			if got := rules[0].Matches(tt.event); got != tt.hdmi {
+				t.Errorf("Expected hdmi rule match %v, got %v", tt.hdmi, got)
+			}
+			if got := rules[1].Matches(tt.event); got != tt.wildcard {
+				t.Errorf("Expected wildcard rule match %v, got %v", tt.wildcard, got)
+			}
+		})
+	}
+}
+
+func TestEventsCmd_Listen(t *testing.T) {
+	rules, err := ParseEventRules([]byte(`
+rules:
+  - name: reload-on-monitor
+    event: monitoradded
+    type: defined
+    command: $set_random_wallpaper
+    args:
+      path: /nonexistent
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	eventsCmd := &EventsCmd{Reconnect: 1}
+	eventsCmd.SetupContext(&cmd.Context{Debug: false})
+	eventsCmd.IPC.(*hyprland.IPCClientMockImpl).SetEvents([]hyprland.Event{
+		{Name: "monitoradded", Data: "HDMI-A-1"},
+	})
+	eventsCmd.setupRules(rules)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if err := eventsCmd.Listen(ctx); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -1,8 +1,10 @@
 package hyprland
 
 type HyprlandGroup struct {
-	Hyprlock  HyprlockCmd  `cmd:"" help:"Hyprland lock screen command"`
-	Hyprpaper HyprpaperCmd `cmd:"" help:"Hyprland wallpaper management command"`
-	Cron      CronCmd      `cmd:"" help:"Hyprland cron jobs command"`
-	Reload    ReloadCmd    `cmd:"" help:"Reload Hyprland components (waybar, config, etc.)"`
+	Hyprlock  HyprlockCmd    `cmd:"" help:"Hyprland lock screen command"`
+	Hyprpaper HyprpaperGroup `cmd:"" help:"Wallpaper management command (hyprpaper, swww, swaybg, feh)"`
+	Cron      CronGroup      `cmd:"" help:"Hyprland cron jobs command"`
+	Reload    ReloadCmd      `cmd:"" help:"Reload Hyprland components (waybar, config, etc.)"`
+	Events    EventsCmd      `cmd:"" help:"Listen to Hyprland events and run matching rules"`
+	Theme     ThemeCmd       `cmd:"" help:"Generate Waybar, Hyprland and widget colours from a wallpaper"`
 }
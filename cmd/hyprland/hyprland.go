@@ -2,11 +2,27 @@ package hyprland
 
 import (
 	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
 	"github.com/williampsena/ebenezer-cli/internal/process"
+	settings "github.com/williampsena/ebenezer-cli/internal/settings"
 )
 
 type HyprlandCmd struct {
 	cmd.BaseCmd
+	IPC          hyprland.IPCClient `kong:"-"`
+	ConfigWriter configfile.Writer  `kong:"-"`
+}
+
+func (h *HyprlandCmd) SetupContext(ctx *cmd.Context) {
+	h.BaseCmd.SetupContext(ctx)
+	h.ConfigWriter = configfile.NewWriter(h.Logger)
+
+	if settings.IsTestMode {
+		h.IPC = hyprland.NewIPCClientMock(h.Logger, nil)
+	} else {
+		h.IPC = hyprland.NewIPCClient(h.Logger)
+	}
 }
 
 // Useful for testing purposes
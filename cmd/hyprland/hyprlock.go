@@ -1,86 +1,282 @@
 package hyprland
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"os"
-	"regexp"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/shirou/gopsutil/v3/host"
 	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/hyprlang"
 	jokes "github.com/williampsena/ebenezer-cli/internal/jokes"
+	"github.com/williampsena/ebenezer-cli/internal/sanitize"
+	"github.com/williampsena/ebenezer-cli/internal/wallpaper"
 )
 
 var defaultLockMessage = "Powered by hyprlock 🔥"
 
+const labelTagPrefix = "ebenezer:"
+
 type HyprlockCmd struct {
 	HyprlandCmd
-	Dry        bool     `help:"Dry run mode, does not write changes to hyprlock.conf" default:"false"`
-	Startup    bool     `help:"Run on startup" default:"false"`
-	Message    string   `help:"Message for hyprlock" default:""`
-	Jokes      bool     `help:"Use a random joke from icanhazdadjoke or reddit"`
-	Provider   []string `help:"Joke providers (icanhazdadjoke, reddit, etc). Can specify multiple." default:"reddit,icanhazdadjoke"`
-	ConfigPath string   `help:"Hyprlock default config" default:"$HOME/.config/hypr/hyprlock.conf"`
-	Format     string   `help:"Format the message" default:"👉 %s 🤪"`
+	Dry        bool          `help:"Dry run mode, does not write changes to hyprlock.conf" default:"false"`
+	Startup    bool          `help:"Run on startup" default:"false"`
+	Message    string        `help:"Message for hyprlock" default:""`
+	Jokes      bool          `help:"Use a random joke from icanhazdadjoke or reddit"`
+	Provider   []string      `help:"Joke providers (icanhazdadjoke, reddit, quotes, fortune, bible, votd). Offline providers accept a source as provider:path. Can specify multiple." default:"reddit,icanhazdadjoke"`
+	ConfigPath string        `help:"Hyprlock default config" default:"$HOME/.config/hypr/hyprlock.conf"`
+	Format     string        `help:"Message format: a Go template such as '{{.Greeting}}, {{.User}} — {{.Message}}' (fields Message, Provider, Date, Clock, Hostname, User, UptimeText, Wallpaper; helpers upper, lower, trim, truncate, wrap), or a printf-style format where %s is the message" default:"👉 %s 🤪"`
+	Label      string        `help:"Label to update, tagged with a '# ebenezer:<label>' comment" default:"message"`
+	LabelIndex int           `help:"Position of the label block to update, counting from 1 across sourced files" default:"0"`
+	Providers  string        `help:"YAML file declaring HTTP providers (default: ~/.config/ebenezer/providers.yaml)" default:""`
+	Filters    jokes.Options `embed:""`
+	Truncate   int           `help:"Maximum length of the message in characters before --format adds to it, emojis count as one (0 for no limit)" default:"100"`
+	Newlines   string        `help:"How line breaks are shown: br keeps them, space joins the lines" enum:"br,space" default:"br"`
+	Quotes     string        `help:"Quotation mark style" enum:"keep,straight,curly" default:"keep"`
+
+	// provider is the joke provider the message came from, if any.
+	provider string
 }
 
 func (w *HyprlockCmd) Run(ctx *cmd.Context) error {
 	w.SetupContext(ctx)
 
+	// let background cache refills finish before the command exits
+	defer jokes.WaitRefills()
+
+	return w.update(context.Background())
+}
+
+// update writes a new message to the hyprlock labels; ctx cancels the joke requests.
+func (w *HyprlockCmd) update(ctx context.Context) error {
 	w.ConfigPath = os.ExpandEnv(w.ConfigPath)
 
-	data, err := os.ReadFile(w.ConfigPath)
+	files, err := hyprlang.LoadWithSources(w.ConfigPath)
 	if err != nil {
 		w.Logger.Error("Error reading hyprlock.conf", "err", err)
 		return fmt.Errorf("error while trying to read file hyprlock.conf: %v", err)
 	}
 
-	content := string(data)
-
-	re := regexp.MustCompile(`(?ms)(label\s*{[^}]*?\btext\s*=\s*)[^\n]+`)
+	labels, err := w.findLabels(files)
+	if err != nil {
+		w.Logger.Error("Error finding hyprlock label", "err", err)
+		return err
+	}
 
-	message, err := w.getMessage()
+	message, err := w.getMessage(ctx)
 	if err != nil {
 		w.Logger.Error("Error getting message for hyprlock", "err", err)
 		return err
 	}
 
-	w.Logger.Debug("Raw message for hyprlock: %s", message)
-	message = jokes.ParseJokeHtml(message)
+	w.Logger.Debug("Raw message for hyprlock", "message", message)
+
+	message, err = w.sanitize(message)
+	if err != nil {
+		return err
+	}
 
 	if w.Format != "" {
-		message = jokes.ApplyFormat(message, w.Format)
+		message, err = jokes.RenderFormat(w.Format, w.messageData(message), sanitize.EscapePango)
+		if err != nil {
+			w.Logger.Error("Error formatting message for hyprlock", "err", err)
+			return err
+		}
+	} else {
+		message = sanitize.EscapePango(message)
 	}
 
+	message, err = w.breakLines(message)
+	if err != nil {
+		return err
+	}
+
+	// hyprlock values end at the line break
+	message = strings.Join(strings.Fields(message), " ")
+
 	if w.Dry {
 		w.Logger.Debug("Dry run mode enabled, not writing changes to hyprlock.conf")
 		return nil
 	}
 
-	updated := re.ReplaceAllString(content, fmt.Sprintf(`${1}%s${3}`, message))
+	changed := map[*hyprlang.File]bool{}
+	for _, label := range labels {
+		label.node.Get("text").SetValue(message)
+		changed[label.file] = true
+	}
 
-	err = os.WriteFile(w.ConfigPath, []byte(updated), 0644)
-	if err != nil {
-		w.Logger.Error("Error writing hyprlock.conf", "err", err)
-		return err
+	for file := range changed {
+		if err := w.ConfigWriter.Write(file.Path, file.Bytes()); err != nil {
+			w.Logger.Error("Error writing hyprlock.conf", "err", err)
+			return err
+		}
 	}
 
 	return nil
 }
 
+// sanitize cleans up the message before it is formatted. Pango escaping and line breaks are
+// applied to the formatted message, so template helpers work on the raw text.
+func (w *HyprlockCmd) sanitize(message string) (string, error) {
+	options, err := sanitize.ForTarget(sanitize.TargetHyprlock)
+	if err != nil {
+		return "", err
+	}
+
+	options.MaxLength = w.Truncate
+	options.Newlines = sanitize.NewlinesKeep
+	options.Pango = false
+	if w.Quotes != "" {
+		options.Quotes = sanitize.QuoteStyle(w.Quotes)
+	}
+
+	pipeline, err := sanitize.New(options)
+	if err != nil {
+		return "", err
+	}
+
+	return pipeline.Apply(message), nil
+}
+
+// breakLines shows the line breaks of the message, including the ones of wrap, the way
+// --newlines asks.
+func (w *HyprlockCmd) breakLines(message string) (string, error) {
+	newlines := sanitize.Targets[sanitize.TargetHyprlock].Newlines
+	if w.Newlines != "" {
+		newlines = sanitize.NewlineStrategy(w.Newlines)
+	}
+
+	pipeline, err := sanitize.New(sanitize.Options{Newlines: newlines})
+	if err != nil {
+		return "", err
+	}
+
+	return pipeline.Apply(message), nil
+}
+
+// messageData collects the fields of the message format.
+func (w *HyprlockCmd) messageData(message string) jokes.MessageData {
+	data := jokes.MessageData{
+		Message:  message,
+		Provider: w.provider,
+		Time:     time.Now(),
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		data.Hostname = hostname
+	}
+
+	if current, err := user.Current(); err == nil {
+		data.User = current.Username
+	} else {
+		data.User = os.Getenv("USER")
+	}
+
+	if uptime, err := host.Uptime(); err == nil {
+		data.Uptime = time.Duration(uptime) * time.Second
+	}
+
+	data.Wallpaper = currentWallpaper()
+
+	return data
+}
+
+// currentWallpaper returns the name of the wallpaper the rotation last set, on the first
+// monitor by name.
+func currentWallpaper() string {
+	rotation, err := wallpaper.LoadRotation(wallpaper.DefaultRotationPath())
+	if err != nil {
+		return ""
+	}
+
+	current := rotation.State().Current
+	monitors := make([]string, 0, len(current))
+	for monitor := range current {
+		monitors = append(monitors, monitor)
+	}
+	if len(monitors) == 0 {
+		return ""
+	}
+	slices.Sort(monitors)
+
+	image := filepath.Base(current[monitors[0]])
+	return strings.TrimSuffix(image, filepath.Ext(image))
+}
+
+type hyprlockLabel struct {
+	file *hyprlang.File
+	node *hyprlang.Node
+}
+
+// findLabels returns the label blocks to update: the one at --label-index, the ones tagged
+// with a `# ebenezer:<label>` comment, or the only label of the configuration.
+func (w *HyprlockCmd) findLabels(files []*hyprlang.File) ([]hyprlockLabel, error) {
+	var labels, tagged []hyprlockLabel
+
+	for _, file := range files {
+		for _, block := range file.Blocks("label") {
+			label := hyprlockLabel{file: file, node: block}
+			labels = append(labels, label)
+
+			if slices.Contains(file.BlockComments(block), labelTagPrefix+w.Label) {
+				tagged = append(tagged, label)
+			}
+		}
+	}
+
+	var selected []hyprlockLabel
+
+	switch {
+	case w.LabelIndex > 0:
+		if w.LabelIndex > len(labels) {
+			return nil, fmt.Errorf("label index %d out of range, found %d labels", w.LabelIndex, len(labels))
+		}
+		selected = labels[w.LabelIndex-1 : w.LabelIndex]
+	case len(tagged) > 0:
+		selected = tagged
+	case len(labels) == 1:
+		w.Logger.Debug("Using the only label of hyprlock.conf")
+		selected = labels
+	case len(labels) == 0:
+		return nil, fmt.Errorf("no label found in %s", w.ConfigPath)
+	default:
+		return nil, fmt.Errorf("found %d labels, tag one with '# %s%s' or pass --label-index", len(labels), labelTagPrefix, w.Label)
+	}
+
+	for _, label := range selected {
+		if label.node.Get("text") == nil {
+			return nil, fmt.Errorf("label at %s:%d has no text", label.file.Path, label.node.Line)
+		}
+	}
+
+	return selected, nil
+}
+
 func (w *HyprlockCmd) getProvider() string {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	return w.Provider[r.Intn(len(w.Provider))]
 }
 
-func (w *HyprlockCmd) getMessage() (string, error) {
+func (w *HyprlockCmd) getMessage(ctx context.Context) (string, error) {
 	if w.Jokes {
+		if err := jokes.RegisterConfiguredProviders(w.Providers); err != nil {
+			return "", err
+		}
+
 		provider := w.getProvider()
-		joke, err := w.fetchJokes(provider)
+		joke, err := w.fetchJokes(ctx, provider)
 		if err != nil {
-			w.Logger.Error("Error fetching joke from %s:", provider, err)
+			w.Logger.Error("Error fetching joke", "provider", provider, "err", err)
 			return defaultLockMessage, nil
 		}
+
+		w.provider = provider
 		return joke, nil
 	}
 
@@ -91,20 +287,24 @@ func (w *HyprlockCmd) getMessage() (string, error) {
 	return w.Message, nil
 }
 
-func (w *HyprlockCmd) fetchJokes(provider string) (string, error) {
+func (w *HyprlockCmd) fetchJokes(ctx context.Context, provider string) (string, error) {
 	var joke string
 	var err error
 	for i := 0; i < 3; i++ {
-		fetcher := jokes.BuildJokeFetcher(w.Logger, provider, !w.Startup)
-		joke, err = fetcher.FetchJokes()
+		fetcher := jokes.BuildJokeFetcherWithOptions(w.Logger, provider, !w.Startup, w.Filters)
+		joke, err = fetcher.FetchJokes(ctx)
 		if err == nil {
-			w.Logger.Debug("Fetched joke from %s: %s", provider, joke)
+			w.Logger.Debug("Fetched joke", "provider", provider, "joke", joke)
 			return joke, nil
 		}
 
-		w.Logger.Debug("error while trying to fetched joke from %s: %s", provider, joke)
+		w.Logger.Debug("Error fetching joke, retrying", "provider", provider, "err", err)
 
-		time.Sleep(500 * time.Millisecond)
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-ctx.Done():
+			return "", fmt.Errorf("stopped fetching joke: %w", ctx.Err())
+		}
 	}
 	return "", fmt.Errorf("failed to fetch joke after 3 attempts: %v", err)
 }
@@ -50,7 +50,7 @@ func TestHyprlockCmd_getMessage(t *testing.T) {
 			}
 			hyprlockCmd.SetupContext(&cmd.Context{Debug: false})
 
-			result, err := hyprlockCmd.getMessage()
+			result, err := hyprlockCmd.getMessage(t.Context())
 			if err != nil {
 				t.Errorf("getMessage() returned unexpected error: %v", err)
 				return
@@ -119,7 +119,7 @@ func TestHyprlockCmd_fetchJokes(t *testing.T) {
 	hyprlockCmd.SetupContext(&cmd.Context{Debug: false})
 
 	t.Run("Valid provider", func(t *testing.T) {
-		result, err := hyprlockCmd.fetchJokes("icanhazdadjoke")
+		result, err := hyprlockCmd.fetchJokes(t.Context(), "icanhazdadjoke")
 		if err != nil {
 			t.Logf("Failed to fetch jokes (expected in test environment): %v", err)
 			if !strings.Contains(err.Error(), "failed to fetch joke after 3 attempts") {
@@ -133,7 +133,7 @@ func TestHyprlockCmd_fetchJokes(t *testing.T) {
 	})
 
 	t.Run("Invalid provider", func(t *testing.T) {
-		_, err := hyprlockCmd.fetchJokes("invalidprovider")
+		_, err := hyprlockCmd.fetchJokes(t.Context(), "invalidprovider")
 		if err == nil {
 			t.Error("Expected error for invalid provider")
 		}
@@ -259,3 +259,122 @@ func TestDefaultLockMessage(t *testing.T) {
 		t.Errorf("Expected defaultLockMessage to be '%s', got '%s'", expected, defaultLockMessage)
 	}
 }
+
+func TestHyprlockCmd_RunTargetsLabel(t *testing.T) {
+	config := `label { # ebenezer:clock
+    text = cmd[update:1000] echo "$TIME"
+}
+
+# ebenezer:message
+label {
+    text = old message
+    shadow {
+        passes = 2
+    }
+}
+
+label {
+    text = $USER
+}
+`
+
+	tests := []struct {
+		name       string
+		label      string
+		labelIndex int
+		expected   string
+		expectErr  bool
+	}{
+		{"TaggedLabel", "message", 0, strings.Replace(config, "text = old message", "text = Hi ##1", 1), false},
+		{"ByIndex", "message", 3, strings.Replace(config, "text = $USER", "text = Hi ##1", 1), false},
+		{"IndexOutOfRange", "message", 4, config, true},
+		{"UntaggedAmbiguous", "missing", 0, config, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "hyprlock.conf")
+			if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			hyprlockCmd := &HyprlockCmd{
+				Message:    "Hi #1",
+				ConfigPath: configPath,
+				Label:      tt.label,
+				LabelIndex: tt.labelIndex,
+			}
+
+			err := hyprlockCmd.Run(&cmd.Context{Debug: false})
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+
+			data, _ := os.ReadFile(configPath)
+			if string(data) != tt.expected {
+				t.Errorf("Unexpected hyprlock.conf:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestHyprlockCmd_RunSourcedLabel(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "hyprlock.conf")
+	labelsPath := filepath.Join(dir, "labels.conf")
+
+	main := "source = labels.conf\nbackground {\n    path = screenshot\n}\n"
+	os.WriteFile(configPath, []byte(main), 0644)
+	os.WriteFile(labelsPath, []byte("label {\n    text = old\n}\n"), 0644)
+
+	hyprlockCmd := &HyprlockCmd{Message: "new", ConfigPath: configPath}
+	if err := hyprlockCmd.Run(&cmd.Context{Debug: false}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if data, _ := os.ReadFile(labelsPath); string(data) != "label {\n    text = new\n}\n" {
+		t.Errorf("Expected the sourced label to be updated, got:\n%s", data)
+	}
+
+	if data, _ := os.ReadFile(configPath); string(data) != main {
+		t.Errorf("Expected hyprlock.conf to be untouched, got:\n%s", data)
+	}
+}
+
+func TestHyprlockCmd_RunTemplateFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		newlines  string
+		expected  string
+		expectErr bool
+	}{
+		{"Fields", "{{.Message | upper}} ({{.Provider}})", "br", "label {\n    text = FISH &amp; CHIPS ()\n}\n", false},
+		{"WrapBreaks", "{{wrap 4 .Message}}", "br", "label {\n    text = Fish<br/>&amp;<br/>Chips\n}\n", false},
+		{"WrapSpaces", "{{wrap 4 .Message}}", "space", "label {\n    text = Fish &amp; Chips\n}\n", false},
+		{"Invalid", "{{.Message", "br", "label {\n    text = old\n}\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "hyprlock.conf")
+			os.WriteFile(configPath, []byte("label {\n    text = old\n}\n"), 0644)
+
+			hyprlockCmd := &HyprlockCmd{
+				Message:    "Fish & Chips",
+				ConfigPath: configPath,
+				Format:     tt.format,
+				Newlines:   tt.newlines,
+			}
+
+			err := hyprlockCmd.Run(&cmd.Context{Debug: false})
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+
+			if data, _ := os.ReadFile(configPath); string(data) != tt.expected {
+				t.Errorf("Unexpected hyprlock.conf:\n%s", data)
+			}
+		})
+	}
+}
@@ -2,28 +2,38 @@ package hyprland
 
 import (
 	"fmt"
-	"html/template"
-	"os"
 	"strings"
+	"time"
 
 	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
 	core "github.com/williampsena/ebenezer-cli/internal/core"
-	hyprland "github.com/williampsena/ebenezer-cli/internal/hyprland"
-	"github.com/williampsena/ebenezer-cli/internal/shell"
+	"github.com/williampsena/ebenezer-cli/internal/theme"
+	"github.com/williampsena/ebenezer-cli/internal/wallpaper"
 )
 
-var SCRIPT_CHANGE_WALLPAPER = `
-hyprctl hyprpaper unload all
-hyprctl hyprpaper preload "{{.Image}}"
-hyprctl hyprpaper wallpaper "{{.Monitor}},{{.Image}}"
-`
+// WallpaperCmd holds the flags shared by the wallpaper subcommands.
+type WallpaperCmd struct {
+	HyprlandCmd
+	wallpaper          *wallpaper.Wallpaper
+	MonitorName        string `arg:"" help:"Monitor name to set the wallpaper on" default:""`
+	Path               string `help:"Path to a specific wallpaper file or directory" default:"~/Pictures/Wallpapers/Active"`
+	NoHistory          bool   `help:"Pick purely random wallpapers, ignoring the rotation history" default:"false"`
+	Schedule           string `help:"Schedule file mapping time windows (or sunrise/sunset) to wallpaper directories" default:""`
+	schedule           *wallpaper.Schedule
+	Theme              bool `help:"Generate Waybar, Hyprland and widget colours from the new wallpaper" default:"false" env:"EBENEZER_WALLPAPER_THEME"`
+	themeOutput        *theme.Output
+	AllMonitors        bool              `help:"Set a distinct wallpaper on every connected monitor" default:"false"`
+	MonitorPath        map[string]string `help:"Per-monitor wallpaper file or directory (MONITOR=PATH). Can specify multiple."`
+	Backend            string            `help:"Wallpaper backend (auto, hyprpaper, swww, swaybg, feh)" default:"auto" env:"EBENEZER_WALLPAPER_BACKEND" enum:"auto,hyprpaper,swww,swaybg,feh"`
+	TransitionType     string            `help:"swww transition type (simple, fade, grow, wipe, ...)" default:""`
+	TransitionStep     int               `help:"swww transition step" default:"0"`
+	TransitionFps      int               `help:"swww transition frame rate" default:"0"`
+	TransitionDuration float64           `help:"swww transition duration in seconds" default:"0"`
+}
 
 type HyprpaperCmd struct {
-	HyprlandCmd
-	hyprpaper   *hyprland.Hyprpaper
-	MonitorName string `arg:"" help:"Monitor name to set the wallpaper on" default:""`
-	Path        string `help:"Path to a specific wallpaper file or directory" default:"~/Pictures/Wallpapers/Active"`
-	Startup     bool   `help:"Run hyprpaper on startup" default:"false"`
+	WallpaperCmd
+	Startup bool `help:"Run hyprpaper on startup" default:"false"`
 }
 
 const (
@@ -31,15 +41,14 @@ const (
 )
 
 func (h *HyprpaperCmd) Run(ctx *cmd.Context) error {
-	h.SetupContext(ctx)
-	h.hyprpaper = hyprland.NewHyprpaper(h.Logger, h.Shell)
-
-	if err := h.setMonitorName(); err != nil {
-		h.Logger.Error("Error setting monitor name", "error", err)
+	if err := h.prepare(ctx); err != nil {
 		return err
 	}
 
-	wallpaperPath := core.ResolvePath(h.Path)
+	wallpaperPath, err := h.wallpaperPath()
+	if err != nil {
+		return err
+	}
 	configPath := core.ResolvePath(configFile)
 
 	if h.Startup {
@@ -49,128 +58,175 @@ func (h *HyprpaperCmd) Run(ctx *cmd.Context) error {
 	return h.RunSetWallpaper(wallpaperPath, configPath)
 }
 
-func (h *HyprpaperCmd) RunStartup(wallpaperPath string, configPath string) error {
-	configFile, err := h.buildConfig(configPath, wallpaperPath)
+func (h *WallpaperCmd) prepare(ctx *cmd.Context) error {
+	h.SetupContext(ctx)
 
-	if err != nil {
-		h.Logger.Error("Error building configuration file", "error", err)
+	if err := h.setupWallpaper(); err != nil {
+		h.Logger.Error("Error setting up wallpaper backend", "error", err)
 		return err
 	}
 
-	defer configFile.Close()
-
-	if isDirectory := h.isDirectory(wallpaperPath); isDirectory {
-		h.setupRandomWallpaper(wallpaperPath, configFile)
-	} else {
-		h.setupSingleWallpaper(wallpaperPath, configFile)
-	}
-
-	h.Logger.Info("Hyprpaper configuration file created successfully", "path", configPath)
-
 	return nil
 }
 
-func (h *HyprpaperCmd) RunSetWallpaper(wallpaperPath string, configPath string) error {
-	imageFiles, err := h.hyprpaper.FetchRandomImages(wallpaperPath)
-	if err != nil {
-		return err
+// wallpaperPath returns the directory of the schedule set active now, or --path without a schedule.
+func (h *WallpaperCmd) wallpaperPath() (string, error) {
+	path := core.ResolvePath(h.Path)
+
+	if h.schedule == nil && h.Schedule != "" {
+		schedule, err := wallpaper.LoadSchedule(h.Schedule)
+		if err != nil {
+			h.Logger.Error("Error loading wallpaper schedule", "file", h.Schedule, "error", err)
+			return "", err
+		}
+		h.schedule = schedule
 	}
-	selectedImage := imageFiles[0]
 
-	tmpl, err := template.New("wallpaperScript").Parse(SCRIPT_CHANGE_WALLPAPER)
-	if err != nil {
-		h.Logger.Error("Error parsing template", "error", err)
-		return err
+	if h.schedule == nil {
+		return path, nil
 	}
 
-	var scriptBuilder strings.Builder
-	err = tmpl.Execute(&scriptBuilder, map[string]string{
-		"Image":   selectedImage,
-		"Monitor": h.MonitorName,
-	})
+	path, err := h.schedule.PathAt(time.Now(), path)
 	if err != nil {
-		h.Logger.Error("Error executing template", "error", err)
-		return err
+		h.Logger.Error("Error resolving wallpaper schedule", "error", err)
+		return "", err
 	}
-	script := scriptBuilder.String()
 
-	_, err = h.Shell.Run(shell.RunnerExecutionArgs{
-		Command: "bash",
-		Args:    []string{"-c", script},
-	})
+	h.Logger.Debug("Using scheduled wallpaper path", "path", path)
+	return path, nil
+}
 
+func (h *WallpaperCmd) setupWallpaper() error {
+	manager, err := wallpaper.New(h.Backend, wallpaper.BackendOptions{
+		Logger:         h.Logger,
+		Shell:          h.Shell,
+		IPC:            h.IPC,
+		ProcessManager: h.ProcessManager,
+		Transition: wallpaper.Transition{
+			Type:     h.TransitionType,
+			Step:     h.TransitionStep,
+			Fps:      h.TransitionFps,
+			Duration: h.TransitionDuration,
+		},
+	})
 	if err != nil {
-		h.Logger.Error("Error setting wallpaper", "error", err)
 		return err
 	}
 
+	if !h.NoHistory {
+		rotation, err := wallpaper.LoadRotation(wallpaper.DefaultRotationPath())
+		if err != nil {
+			return err
+		}
+		manager.SetRotation(rotation)
+	}
+
+	h.wallpaper = manager
 	return nil
 }
 
-func (h *HyprpaperCmd) buildConfig(configPath, wallpaperPath string) (*os.File, error) {
-	file, err := os.Create(configPath)
+// RunStartup writes a hyprpaper.conf with one preload/wallpaper pair per monitor. Other
+// backends have no configuration file, so the wallpapers are applied directly.
+func (h *HyprpaperCmd) RunStartup(wallpaperPath string, configPath string) error {
+	assignments, err := h.resolveAssignments(wallpaperPath, true)
 	if err != nil {
-		h.Logger.Error("Error creating config file", "error", err)
-		return nil, err
+		h.Logger.Error("Error selecting wallpapers", "error", err)
+		return err
 	}
 
-	_, err = fmt.Fprintf(file, "# Hyprpaper configuration file\n")
-	if err != nil {
-		h.Logger.Error("Error writing to config file", "error", err)
-		return nil, err
-	}
+	if h.wallpaper.Backend().Name() != "hyprpaper" {
+		if err := h.wallpaper.Apply(assignments); err != nil {
+			return err
+		}
 
-	return file, nil
-}
+		return h.generateTheme(assignments)
+	}
 
-func (h *HyprpaperCmd) setupPreloadImage(file *os.File, imageFile string) error {
-	_, err := fmt.Fprintf(file, "preload = %s\n", imageFile)
-	if err != nil {
-		h.Logger.Error("Error writing preload image", "error", err)
+	if err := h.ConfigWriter.Write(configPath, []byte(h.buildConfig(assignments))); err != nil {
+		h.Logger.Error("Error writing configuration file", "error", err)
 		return err
 	}
 
-	return nil
+	h.Logger.Info("Hyprpaper configuration file created successfully", "path", configPath)
+
+	return h.generateTheme(assignments)
 }
 
-func (h *HyprpaperCmd) setupRandomWallpaper(wallpaperPath string, configFile *os.File) error {
-	imageFiles, err := h.hyprpaper.FetchRandomImages(wallpaperPath)
+func (h *WallpaperCmd) RunSetWallpaper(wallpaperPath string, configPath string) error {
+	assignments, err := h.resolveAssignments(wallpaperPath, h.AllMonitors || len(h.MonitorPath) > 0)
 	if err != nil {
 		return err
 	}
 
-	selectedImage := imageFiles[0]
+	if err := h.wallpaper.Apply(assignments); err != nil {
+		return err
+	}
 
-	h.setupPreloadImage(configFile, selectedImage)
+	return h.generateTheme(assignments)
+}
 
-	_, err = fmt.Fprintf(configFile, "wallpaper = %s,%s\n", h.MonitorName, selectedImage)
+func (h *WallpaperCmd) resolveAssignments(wallpaperPath string, allMonitors bool) ([]wallpaper.Assignment, error) {
+	monitors, err := h.targetMonitors(allMonitors)
 	if err != nil {
-		fmt.Printf("Erro ao escrever wallpaper: %v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
 
-	return nil
+	return h.wallpaper.AssignWallpapers(monitors, wallpaperPath, h.MonitorPath)
 }
 
-func (h *HyprpaperCmd) setupSingleWallpaper(wallpaperPath string, configFile *os.File) error {
-	h.setupPreloadImage(configFile, wallpaperPath)
+// targetMonitors returns the monitor given as argument, every monitor when allMonitors is
+// set, or the focused one.
+func (h *WallpaperCmd) targetMonitors(allMonitors bool) ([]string, error) {
+	var monitors []string
 
-	_, err := fmt.Fprintf(configFile, "wallpaper = %s,%s\n", h.MonitorName, wallpaperPath)
-	if err != nil {
-		h.Logger.Error("Error writing wallpaper option", "error", err)
-		return err
+	switch {
+	case h.MonitorName != "":
+		h.Logger.Debug("Using provided monitor name", "name", h.MonitorName)
+		monitors = []string{h.MonitorName}
+	case allMonitors:
+		names, err := h.wallpaper.GetMonitorNames()
+		if err != nil {
+			return nil, err
+		}
+		monitors = names
+	default:
+		if err := h.setMonitorName(); err != nil {
+			h.Logger.Error("Error setting monitor name", "error", err)
+			return nil, err
+		}
+		monitors = []string{h.MonitorName}
+	}
+
+	return monitors, nil
+}
+
+func (h *HyprpaperCmd) buildConfig(assignments []wallpaper.Assignment) string {
+	var config strings.Builder
+	preloaded := map[string]bool{}
+
+	config.WriteString("# Hyprpaper configuration file\n")
+
+	for _, assignment := range assignments {
+		if !preloaded[assignment.Image] {
+			preloaded[assignment.Image] = true
+			fmt.Fprintf(&config, "preload = %s\n", assignment.Image)
+		}
 	}
 
-	return nil
+	for _, assignment := range assignments {
+		fmt.Fprintf(&config, "wallpaper = %s,%s\n", assignment.Monitor, assignment.Image)
+	}
+
+	return config.String()
 }
 
-func (h *HyprpaperCmd) setMonitorName() error {
+func (h *WallpaperCmd) setMonitorName() error {
 	if h.MonitorName != "" {
 		h.Logger.Debug("Using provided monitor name", "name", h.MonitorName)
 		return nil
 	}
 
-	monitorName, err := h.hyprpaper.GetMonitorName()
+	monitorName, err := h.wallpaper.GetMonitorName()
 
 	if err != nil {
 		h.Logger.Error("Error getting current monitor", "error", err)
@@ -182,12 +238,3 @@ func (h *HyprpaperCmd) setMonitorName() error {
 	h.MonitorName = monitorName
 	return nil
 }
-
-func (h *HyprpaperCmd) isDirectory(path string) bool {
-	info, err := os.Stat(path)
-	if err != nil {
-		h.Logger.Error("Error checking if path is a directory", "path", path, "error", err)
-		return false
-	}
-	return info.IsDir()
-}
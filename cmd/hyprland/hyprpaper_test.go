@@ -0,0 +1,196 @@
+package hyprland
+
+import (
+	imagepkg "image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/theme"
+)
+
+func TestHyprpaperCmd_RunStartup(t *testing.T) {
+	dir := t.TempDir()
+	wallpapers := filepath.Join(dir, "wallpapers")
+	configPath := filepath.Join(dir, "hyprpaper.conf")
+
+	if err := os.MkdirAll(wallpapers, 0755); err != nil {
+		t.Fatalf("Failed to create wallpaper directory: %v", err)
+	}
+	for _, name := range []string{"one.png", "two.png"} {
+		if err := os.WriteFile(filepath.Join(wallpapers, name), []byte("img"), 0644); err != nil {
+			t.Fatalf("Failed to create image: %v", err)
+		}
+	}
+
+	hyprpaperCmd := &HyprpaperCmd{WallpaperCmd: WallpaperCmd{Backend: "hyprpaper"}}
+	hyprpaperCmd.SetupContext(&cmd.Context{Debug: false})
+	hyprpaperCmd.IPC = hyprland.NewIPCClientMock(hyprpaperCmd.Logger, []hyprland.Monitor{{Name: "eDP-1"}, {Name: "HDMI-A-1"}})
+	if err := hyprpaperCmd.setupWallpaper(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := hyprpaperCmd.RunStartup(wallpapers, configPath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read generated config: %v", err)
+	}
+
+	content := string(data)
+	if strings.Count(content, "preload = ") != 2 {
+		t.Errorf("Expected two preload lines, got:\n%s", content)
+	}
+
+	for _, monitor := range []string{"eDP-1", "HDMI-A-1"} {
+		if !strings.Contains(content, "wallpaper = "+monitor+",") {
+			t.Errorf("Expected a wallpaper line for %s, got:\n%s", monitor, content)
+		}
+	}
+}
+
+func TestHyprpaperCmd_RunStartupMissingPath(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "hyprpaper.conf")
+	original := "# keep me\n"
+
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	hyprpaperCmd := &HyprpaperCmd{WallpaperCmd: WallpaperCmd{Backend: "hyprpaper"}}
+	hyprpaperCmd.SetupContext(&cmd.Context{Debug: false})
+	if err := hyprpaperCmd.setupWallpaper(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := hyprpaperCmd.RunStartup(filepath.Join(dir, "missing"), configPath); err == nil {
+		t.Error("Expected error for a missing wallpaper directory")
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if string(data) != original {
+		t.Errorf("Expected config to be left untouched, got:\n%s", data)
+	}
+}
+
+func TestHyprpaperCmd_RunSetWallpaper(t *testing.T) {
+	dir := t.TempDir()
+	image := filepath.Join(dir, "only.png")
+	if err := os.WriteFile(image, []byte("img"), 0644); err != nil {
+		t.Fatalf("Failed to create image: %v", err)
+	}
+
+	hyprpaperCmd := &HyprpaperCmd{WallpaperCmd: WallpaperCmd{AllMonitors: true, Backend: "hyprpaper"}}
+	hyprpaperCmd.SetupContext(&cmd.Context{Debug: false})
+	client := hyprland.NewIPCClientMock(hyprpaperCmd.Logger, []hyprland.Monitor{{Name: "eDP-1"}, {Name: "DP-1"}})
+	hyprpaperCmd.IPC = client
+	if err := hyprpaperCmd.setupWallpaper(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := hyprpaperCmd.RunSetWallpaper(image, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	requests := strings.Join(client.(*hyprland.IPCClientMockImpl).Requests, "\n")
+	for _, monitor := range []string{"eDP-1", "DP-1"} {
+		if !strings.Contains(requests, "wallpaper "+monitor+","+image) {
+			t.Errorf("Expected wallpaper request for %s, got:\n%s", monitor, requests)
+		}
+	}
+}
+
+func TestHyprpaperCmd_setupWallpaper(t *testing.T) {
+	hyprpaperCmd := &HyprpaperCmd{WallpaperCmd: WallpaperCmd{Backend: "swww"}}
+	hyprpaperCmd.SetupContext(&cmd.Context{Debug: false})
+
+	if err := hyprpaperCmd.setupWallpaper(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if name := hyprpaperCmd.wallpaper.Backend().Name(); name != "swww" {
+		t.Errorf("Expected swww backend, got %s", name)
+	}
+
+	hyprpaperCmd.Backend = "xwallpaper"
+	if err := hyprpaperCmd.setupWallpaper(); err == nil {
+		t.Error("Expected error for an unknown backend")
+	}
+}
+
+func TestHyprpaperCmd_wallpaperPath(t *testing.T) {
+	dir := t.TempDir()
+	schedulePath := filepath.Join(dir, "schedule.yaml")
+	schedule := "default: /wallpapers/default\nsets:\n  - name: never\n    from: \"00:00\"\n    to: \"00:00\"\n    path: /wallpapers/never\n"
+
+	if err := os.WriteFile(schedulePath, []byte(schedule), 0644); err != nil {
+		t.Fatalf("Failed to create schedule: %v", err)
+	}
+
+	hyprpaperCmd := &HyprpaperCmd{WallpaperCmd: WallpaperCmd{Path: "/wallpapers/active"}}
+	hyprpaperCmd.SetupContext(&cmd.Context{Debug: false})
+
+	path, err := hyprpaperCmd.wallpaperPath()
+	if err != nil || path != "/wallpapers/active" {
+		t.Errorf("Expected --path without a schedule, got %s (%v)", path, err)
+	}
+
+	hyprpaperCmd.Schedule = schedulePath
+	path, err = hyprpaperCmd.wallpaperPath()
+	if err != nil || path != "/wallpapers/default" {
+		t.Errorf("Expected the schedule default, got %s (%v)", path, err)
+	}
+
+	hyprpaperCmd.schedule = nil
+	hyprpaperCmd.Schedule = filepath.Join(dir, "missing.yaml")
+	if _, err := hyprpaperCmd.wallpaperPath(); err == nil {
+		t.Error("Expected error for a missing schedule file")
+	}
+}
+
+func TestHyprpaperCmd_RunSetWallpaperWithTheme(t *testing.T) {
+	dir := t.TempDir()
+	image := filepath.Join(dir, "wallpaper.png")
+
+	img := imagepkg.NewRGBA(imagepkg.Rect(0, 0, 10, 10))
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			img.Set(x, y, color.RGBA{40, 90, 200, 255})
+		}
+	}
+
+	file, err := os.Create(image)
+	if err != nil {
+		t.Fatalf("Failed to create image: %v", err)
+	}
+	png.Encode(file, img)
+	file.Close()
+
+	output := theme.Output{Hyprland: filepath.Join(dir, "colors.conf")}
+	hyprpaperCmd := &HyprpaperCmd{WallpaperCmd: WallpaperCmd{Backend: "hyprpaper", Theme: true, themeOutput: &output}}
+	hyprpaperCmd.SetupContext(&cmd.Context{Debug: false})
+	if err := hyprpaperCmd.setupWallpaper(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := hyprpaperCmd.RunSetWallpaper(image, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	conf, err := os.ReadFile(output.Hyprland)
+	if err != nil {
+		t.Fatalf("Expected colors.conf to be generated: %v", err)
+	}
+
+	if !strings.Contains(string(conf), "$color0 = rgb(285ac8)") {
+		t.Errorf("Expected the wallpaper colour in colors.conf, got:\n%s", conf)
+	}
+}
@@ -1,9 +1,25 @@
 package hyprland
 
 import (
+	"os"
+	"testing"
+
 	settings "github.com/williampsena/ebenezer-cli/internal/settings"
 )
 
 func init() {
 	settings.SetTestMode()
 }
+
+// TestMain keeps the wallpaper rotation state of the tests away from the user's state directory.
+func TestMain(m *testing.M) {
+	stateDir, err := os.MkdirTemp("", "ebenezer-state")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_STATE_HOME", stateDir)
+
+	code := m.Run()
+	os.RemoveAll(stateDir)
+	os.Exit(code)
+}
@@ -17,7 +17,11 @@ type ReloadCmd struct {
 
 func (r *ReloadCmd) Run(ctx *cmd.Context) error {
 	r.SetupContext(ctx)
+	return r.reload()
+}
 
+// reload reloads the component, also used by the $reload cron job.
+func (r *ReloadCmd) reload() error {
 	if err := r.validateEnvironment(); err != nil {
 		return fmt.Errorf("environment validation failed: %w", err)
 	}
@@ -67,12 +71,8 @@ func (r *ReloadCmd) reloadAll() error {
 func (r *ReloadCmd) reloadHyprland() error {
 	r.Logger.Info("🔄 Reloading 🔳 Hyprland configuration")
 
-	output, err := r.Shell.Run(shell.RunnerExecutionArgs{
-		Command: "hyprctl",
-		Args:    []string{"reload"},
-	})
-	if err != nil {
-		r.Logger.Error("❌ Failed to reload 🔳 Hyprland", "error", err, "output", string(output))
+	if err := r.IPC.Reload(); err != nil {
+		r.Logger.Error("❌ Failed to reload 🔳 Hyprland", "error", err)
 		return fmt.Errorf("failed to reload 🔳 Hyprland: %w", err)
 	}
 
@@ -152,7 +152,7 @@ func (r *ReloadCmd) startWaybar() error {
 }
 
 func (r *ReloadCmd) checkDependencies() error {
-	dependencies := []string{"hyprctl", "pgrep", "kill"}
+	dependencies := []string{"pgrep", "kill"}
 
 	if r.Component == "waybar" || r.Component == "all" {
 		dependencies = append(dependencies, "waybar")
@@ -183,13 +183,12 @@ func (r *ReloadCmd) performHealthCheck() error {
 	r.Logger.Debug("Performing post-reload health check")
 
 	if r.Component == "hyprland" || r.Component == "all" {
-		_, err := r.Shell.Run(shell.RunnerExecutionArgs{Command: "hyprctl", Args: []string{"version"}})
-
+		version, err := r.IPC.Version()
 		if err != nil {
 			r.Logger.Warning("❌ Hyprland health check failed", "error", err)
 			return fmt.Errorf("hyprland health check failed: %w", err)
 		}
-		r.Logger.Debug("Hyprland health check passed")
+		r.Logger.Debug("Hyprland health check passed", "version", version.Tag)
 	}
 
 	if r.Component == "waybar" || r.Component == "all" {
@@ -0,0 +1,96 @@
+package hyprland
+
+import (
+	"fmt"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/theme"
+	"github.com/williampsena/ebenezer-cli/internal/wallpaper"
+)
+
+const themePaletteSize = 8
+
+type ThemeCmd struct {
+	HyprlandCmd
+	Image    string `arg:"" help:"Image to extract the colours from, defaults to the current wallpaper" default:""`
+	Monitor  string `help:"Monitor whose current wallpaper is used" default:""`
+	Waybar   string `help:"Waybar CSS fragment to write" default:"~/.config/waybar/ebenezer-colors.css"`
+	Hyprland string `help:"Hyprland colors.conf to write" default:"~/.config/hypr/colors.conf"`
+}
+
+func (t *ThemeCmd) Run(ctx *cmd.Context) error {
+	t.SetupContext(ctx)
+
+	image := core.ResolvePath(t.Image)
+	if image == "" {
+		current, err := t.currentWallpaper()
+		if err != nil {
+			t.Logger.Error("Error finding the current wallpaper", "error", err)
+			return err
+		}
+		image = current
+	}
+
+	return writeTheme(t.Logger, t.ConfigWriter, image, theme.Output{
+		Waybar:   core.ResolvePath(t.Waybar),
+		Hyprland: core.ResolvePath(t.Hyprland),
+		Widgets:  theme.WidgetColorsPath(),
+	})
+}
+
+func (t *ThemeCmd) currentWallpaper() (string, error) {
+	rotation, err := wallpaper.LoadRotation(wallpaper.DefaultRotationPath())
+	if err != nil {
+		return "", err
+	}
+
+	if image, ok := rotation.Current(t.Monitor); ok {
+		return image, nil
+	}
+
+	for _, image := range rotation.State().Current {
+		if image != "" {
+			return image, nil
+		}
+	}
+
+	return "", fmt.Errorf("no current wallpaper recorded, pass an image path")
+}
+
+// generateTheme writes the theme files from the first assigned image when --theme is set.
+func (h *WallpaperCmd) generateTheme(assignments []wallpaper.Assignment) error {
+	if !h.Theme || len(assignments) == 0 {
+		return nil
+	}
+
+	output := theme.DefaultOutput()
+	if h.themeOutput != nil {
+		output = *h.themeOutput
+	}
+
+	return writeTheme(h.Logger, h.ConfigWriter, assignments[0].Image, output)
+}
+
+func writeTheme(logger core.Logger, writer configfile.Writer, image string, output theme.Output) error {
+	swatches, err := theme.ExtractFile(image, themePaletteSize)
+	if err != nil {
+		logger.Error("Error extracting palette", "image", image, "error", err)
+		return err
+	}
+
+	generated, err := theme.NewTheme(swatches)
+	if err != nil {
+		logger.Error("Error building theme", "image", image, "error", err)
+		return err
+	}
+
+	if err := theme.Write(writer, generated, output); err != nil {
+		logger.Error("Error writing theme", "error", err)
+		return err
+	}
+
+	logger.Info("Theme generated", "image", image, "accent", generated.Accent.Hex())
+	return nil
+}
@@ -0,0 +1,132 @@
+package hyprland
+
+import (
+	"fmt"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/wallpaper"
+)
+
+type HyprpaperGroup struct {
+	Set      HyprpaperCmd         `cmd:"" default:"withargs" help:"Set random wallpapers (default)"`
+	Next     HyprpaperNextCmd     `cmd:"" help:"Switch to the next wallpaper in the rotation"`
+	Previous HyprpaperPreviousCmd `cmd:"" help:"Restore the previously shown wallpaper"`
+	Favorite HyprpaperFavoriteCmd `cmd:"" help:"Mark the current wallpaper as favorite"`
+	Ban      HyprpaperBanCmd      `cmd:"" help:"Ban the current wallpaper and switch to the next one"`
+}
+
+type HyprpaperNextCmd struct {
+	WallpaperCmd
+}
+
+func (h *HyprpaperNextCmd) Run(ctx *cmd.Context) error {
+	if err := h.prepare(ctx); err != nil {
+		return err
+	}
+
+	wallpaperPath, err := h.wallpaperPath()
+	if err != nil {
+		return err
+	}
+
+	return h.RunSetWallpaper(wallpaperPath, "")
+}
+
+type HyprpaperPreviousCmd struct {
+	WallpaperCmd
+}
+
+func (h *HyprpaperPreviousCmd) Run(ctx *cmd.Context) error {
+	if err := h.prepare(ctx); err != nil {
+		return err
+	}
+
+	return h.RunPrevious()
+}
+
+func (h *HyprpaperPreviousCmd) RunPrevious() error {
+	monitors, err := h.targetMonitors(h.AllMonitors)
+	if err != nil {
+		return err
+	}
+
+	return h.wallpaper.Previous(monitors)
+}
+
+type HyprpaperFavoriteCmd struct {
+	WallpaperCmd
+	Weight float64 `help:"Selection weight of the wallpaper, favorites default to 3" default:"0"`
+	Remove bool    `help:"Remove the wallpaper from the favorites" default:"false"`
+}
+
+func (h *HyprpaperFavoriteCmd) Run(ctx *cmd.Context) error {
+	if err := h.prepare(ctx); err != nil {
+		return err
+	}
+
+	return h.RunFavorite()
+}
+
+func (h *HyprpaperFavoriteCmd) RunFavorite() error {
+	return h.updateCurrent(func(rotation *wallpaper.Rotation, image string) {
+		rotation.Favorite(image, h.Weight, h.Remove)
+		h.Logger.Info("Updated favorite wallpaper", "image", image, "removed", h.Remove)
+	})
+}
+
+type HyprpaperBanCmd struct {
+	WallpaperCmd
+	Remove bool `help:"Allow the wallpaper in the rotation again" default:"false"`
+}
+
+func (h *HyprpaperBanCmd) Run(ctx *cmd.Context) error {
+	if err := h.prepare(ctx); err != nil {
+		return err
+	}
+
+	return h.RunBan()
+}
+
+func (h *HyprpaperBanCmd) RunBan() error {
+	err := h.updateCurrent(func(rotation *wallpaper.Rotation, image string) {
+		rotation.Ban(image, h.Remove)
+		h.Logger.Info("Updated banned wallpaper", "image", image, "removed", h.Remove)
+	})
+	if err != nil || h.Remove {
+		return err
+	}
+
+	wallpaperPath, err := h.wallpaperPath()
+	if err != nil {
+		return err
+	}
+
+	return h.RunSetWallpaper(wallpaperPath, "")
+}
+
+// updateCurrent calls update with the current wallpaper of each target monitor and saves the rotation.
+func (h *WallpaperCmd) updateCurrent(update func(rotation *wallpaper.Rotation, image string)) error {
+	rotation := h.wallpaper.Rotation()
+	if rotation == nil {
+		return fmt.Errorf("wallpaper rotation is disabled")
+	}
+
+	monitors, err := h.targetMonitors(h.AllMonitors)
+	if err != nil {
+		return err
+	}
+
+	updated := false
+	for _, monitor := range monitors {
+		if image, ok := rotation.Current(monitor); ok {
+			update(rotation, image)
+			updated = true
+		}
+	}
+
+	if !updated {
+		return fmt.Errorf("no current wallpaper recorded for %v", monitors)
+	}
+
+	return rotation.Save()
+}
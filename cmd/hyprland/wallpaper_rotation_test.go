@@ -0,0 +1,89 @@
+package hyprland
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+)
+
+func buildRotationCmd(t *testing.T) (WallpaperCmd, string) {
+	t.Helper()
+
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	for _, name := range []string{"one.png", "two.png", "three.png"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("img"), 0644); err != nil {
+			t.Fatalf("Failed to create image: %v", err)
+		}
+	}
+
+	wallpaperCmd := WallpaperCmd{Backend: "hyprpaper", Path: dir}
+	wallpaperCmd.SetupContext(&cmd.Context{Debug: false})
+	if err := wallpaperCmd.setupWallpaper(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := wallpaperCmd.RunSetWallpaper(dir, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	current, _ := wallpaperCmd.wallpaper.Rotation().Current("eDP-1")
+	return wallpaperCmd, current
+}
+
+func TestHyprpaperFavoriteCmd_RunFavorite(t *testing.T) {
+	wallpaperCmd, current := buildRotationCmd(t)
+
+	favoriteCmd := &HyprpaperFavoriteCmd{WallpaperCmd: wallpaperCmd, Weight: 5}
+	if err := favoriteCmd.RunFavorite(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	state := favoriteCmd.wallpaper.Rotation().State()
+	if !slices.Contains(state.Favorites, current) || state.Weights[current] != 5 {
+		t.Errorf("Expected %s to be a favorite with weight 5, got %+v", current, state)
+	}
+}
+
+func TestHyprpaperBanCmd_RunBan(t *testing.T) {
+	wallpaperCmd, current := buildRotationCmd(t)
+
+	banCmd := &HyprpaperBanCmd{WallpaperCmd: wallpaperCmd}
+	if err := banCmd.RunBan(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rotation := banCmd.wallpaper.Rotation()
+	if !slices.Contains(rotation.State().Banned, current) {
+		t.Errorf("Expected %s to be banned", current)
+	}
+
+	if next, _ := rotation.Current("eDP-1"); next == current {
+		t.Errorf("Expected the banned wallpaper %s to be replaced", current)
+	}
+}
+
+func TestHyprpaperPreviousCmd_RunPrevious(t *testing.T) {
+	wallpaperCmd, first := buildRotationCmd(t)
+
+	if err := wallpaperCmd.RunSetWallpaper(wallpaperCmd.Path, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	previousCmd := &HyprpaperPreviousCmd{WallpaperCmd: wallpaperCmd}
+	if err := previousCmd.RunPrevious(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if current, _ := previousCmd.wallpaper.Rotation().Current("eDP-1"); current != first {
+		t.Errorf("Expected %s to be restored, got %s", first, current)
+	}
+
+	if err := previousCmd.RunPrevious(); err == nil {
+		t.Error("Expected error when there is no earlier wallpaper")
+	}
+}
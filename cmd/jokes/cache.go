@@ -0,0 +1,152 @@
+package jokes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	formatters "github.com/williampsena/ebenezer-cli/internal/cmd/formatters"
+	jokelib "github.com/williampsena/ebenezer-cli/internal/jokes"
+)
+
+type CacheListCmd struct {
+	JokesCmd
+}
+
+func (c *CacheListCmd) Run(ctx *cmd.Context) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	pools, err := c.store.List()
+	if err != nil {
+		c.Logger.Error("Error listing cache", "error", err)
+		return err
+	}
+
+	if len(pools) == 0 {
+		return formatters.WriteToStdout(fmt.Sprintf("No cached jokes in %s\n", c.store.Dir()))
+	}
+
+	var lines []string
+	for _, pool := range pools {
+		lines = append(lines, fmt.Sprintf("%-20s %4d entries %4d unseen  fetched %s",
+			pool.Provider, len(pool.Entries), len(pool.Remaining()), pool.FetchedAt.Format("2006-01-02 15:04:05")))
+	}
+
+	return formatters.WriteToStdout(strings.Join(lines, "\n") + "\n")
+}
+
+type CacheClearCmd struct {
+	JokesCmd
+	Provider []string `arg:"" optional:"" help:"Providers to clear"`
+}
+
+func (c *CacheClearCmd) Run(ctx *cmd.Context) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	if err := c.store.Clear(c.Provider...); err != nil {
+		c.Logger.Error("Error clearing cache", "error", err)
+		return err
+	}
+
+	if len(c.Provider) == 0 {
+		return formatters.WriteToStdout("Cleared every cached pool\n")
+	}
+
+	return formatters.WriteToStdout(fmt.Sprintf("Cleared %s\n", strings.Join(c.Provider, ", ")))
+}
+
+type CacheRefreshCmd struct {
+	JokesCmd
+	Provider []string `arg:"" optional:"" help:"Providers to refresh"`
+}
+
+func (c *CacheRefreshCmd) Run(ctx *cmd.Context) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	targets, err := c.targets()
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	var failed []string
+
+	for _, target := range targets {
+		pool, err := target.fetcher.Refresh(context.Background())
+		if err != nil {
+			c.Logger.Error("Error refreshing cache", "provider", target.name, "error", err)
+			failed = append(failed, target.name)
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%-20s %4d entries %4d unseen", target.name, len(pool.Entries), len(pool.Remaining())))
+	}
+
+	if len(lines) > 0 {
+		if err := formatters.WriteToStdout(strings.Join(lines, "\n") + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to refresh %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// refreshTarget is a pool to refresh and the fetcher filling it.
+type refreshTarget struct {
+	name    string
+	fetcher jokelib.JokeFetcher
+}
+
+// targets returns the pools to refresh: the given ones, the cached ones, or every pooled
+// provider when nothing was cached yet. Cached pools are fetched again with the provider and
+// options they were filled with, so filtered reddit pools stay filtered.
+func (c *CacheRefreshCmd) targets() ([]refreshTarget, error) {
+	pools, err := c.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	cached := map[string]*jokelib.Pool{}
+	for _, pool := range pools {
+		cached[pool.Provider] = pool
+	}
+
+	names := c.Provider
+	if len(names) == 0 {
+		for _, pool := range pools {
+			names = append(names, pool.Provider)
+		}
+	}
+
+	if len(names) == 0 {
+		names = jokelib.PooledProviders()
+	}
+
+	var targets []refreshTarget
+	for _, name := range names {
+		pool, ok := cached[name]
+		if !ok || pool.Source == "" {
+			targets = append(targets, refreshTarget{name, jokelib.BuildJokeFetcher(c.Logger, name, true)})
+			continue
+		}
+
+		var options jokelib.Options
+		if pool.Options != nil {
+			options = *pool.Options
+		}
+		targets = append(targets, refreshTarget{name, jokelib.BuildJokeFetcherWithOptions(c.Logger, pool.Source, true, options)})
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,111 @@
+package jokes
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	jokelib "github.com/williampsena/ebenezer-cli/internal/jokes"
+)
+
+func TestCacheCmds(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"quotes": ["one", "two", "three"]}`))
+	}))
+	defer server.Close()
+
+	providers := filepath.Join(t.TempDir(), "providers.yaml")
+	os.WriteFile(providers, []byte("providers:\n  - name: cmd-test\n    url: "+server.URL+"\n    path: $.quotes\n"), 0644)
+	defer delete(jokelib.PROVIDERS, "cmd-test")
+
+	ctx := &cmd.Context{Silent: true}
+	store := jokelib.NewCacheStore(core.BuildSilentLogger(), "")
+
+	refreshCmd := &CacheRefreshCmd{JokesCmd: JokesCmd{Providers: providers}, Provider: []string{"cmd-test"}}
+	if err := refreshCmd.Run(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pool, err := store.Load("cmd-test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pool.Entries) != 3 {
+		t.Errorf("Expected 3 cached entries, got %v", pool.Entries)
+	}
+
+	listCmd := &CacheListCmd{JokesCmd: JokesCmd{Providers: providers}}
+	if err := listCmd.Run(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	clearCmd := &CacheClearCmd{JokesCmd: JokesCmd{Providers: providers}}
+	if err := clearCmd.Run(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if pools, _ := store.List(); len(pools) != 0 {
+		t.Errorf("Expected the cache to be cleared, got %d pools", len(pools))
+	}
+
+	missing := &CacheRefreshCmd{JokesCmd: JokesCmd{Providers: providers}, Provider: []string{"quotes"}}
+	if err := missing.Run(ctx); err == nil {
+		t.Error("Expected error when refreshing a provider that is not cached")
+	}
+}
+
+func TestCacheRefreshCmd_FilteredPool(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Write([]byte(fmt.Sprintf(`{"data": {"children": [
+			{"data": {"title": "Meme %d", "link_flair_text": "Meme"}},
+			{"data": {"title": "Other %d", "link_flair_text": "Meta"}}
+		]}}`, len(requested), len(requested))))
+	}))
+	defer server.Close()
+
+	defer func(url string) { jokelib.RedditURL = url }(jokelib.RedditURL)
+	jokelib.RedditURL = server.URL
+
+	logger := core.BuildSilentLogger()
+	options := jokelib.Options{Subreddit: []string{"programming"}, Flair: []string{"meme"}}
+	fetcher := jokelib.BuildJokeFetcherWithOptions(logger, "reddit", true, options)
+	if _, err := fetcher.Refresh(t.Context()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	refreshCmd := &CacheRefreshCmd{}
+	if err := refreshCmd.Run(&cmd.Context{Silent: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(requested) != 2 || requested[1] != "/r/programming.json" {
+		t.Errorf("Expected the filtered subreddit to be fetched again, got %v", requested)
+	}
+
+	pools, _ := jokelib.NewCacheStore(logger, "").List()
+	if len(pools) != 1 || pools[0].Provider != "reddit:programming,flair=meme" {
+		t.Fatalf("Expected only the filtered pool, got %v", pools)
+	}
+
+	if entries := pools[0].Entries; !slices.Equal(entries, []string{"Meme 1", "Meme 2"}) {
+		t.Errorf("Expected the filtered entries of both fetches, got %q", entries)
+	}
+
+	providersCmd := &ProvidersCmd{JokesCmd: JokesCmd{store: jokelib.NewCacheStore(logger, "")}}
+	if state := providersCmd.cacheState(jokelib.ProviderInfo{Name: "reddit", Cached: true}, pools); !strings.HasPrefix(state, "2 entries, 2 unseen") {
+		t.Errorf("Expected the filtered pool in the provider state, got %s", state)
+	}
+}
@@ -0,0 +1,50 @@
+package jokes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	formatters "github.com/williampsena/ebenezer-cli/internal/cmd/formatters"
+	jokelib "github.com/williampsena/ebenezer-cli/internal/jokes"
+)
+
+type FetchCmd struct {
+	JokesCmd
+	Provider string          `help:"Provider to fetch from, with an optional source (e.g. fortune:/usr/share/fortune)" default:"reddit"`
+	Format   string          `help:"Output format" enum:"text,json" default:"text"`
+	Cache    bool            `help:"Use the cached pool, marking the joke as shown" default:"true" negatable:""`
+	Filters  jokelib.Options `embed:""`
+}
+
+type fetchOutput struct {
+	Provider string `json:"provider"`
+	Joke     string `json:"joke"`
+}
+
+func (c *FetchCmd) Run(ctx *cmd.Context) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	fetcher := jokelib.BuildJokeFetcherWithOptions(c.Logger, c.Provider, c.Cache, c.Filters)
+	defer jokelib.WaitRefills()
+
+	joke, err := fetcher.FetchJokes(context.Background())
+	if err != nil {
+		c.Logger.Error("Error fetching joke", "provider", c.Provider, "error", err)
+		return err
+	}
+
+	if c.Format == "json" {
+		data, err := json.Marshal(fetchOutput{Provider: c.Provider, Joke: joke})
+		if err != nil {
+			return err
+		}
+
+		return formatters.WriteToStdout(string(data) + "\n")
+	}
+
+	return formatters.WriteToStdout(fmt.Sprintf("%s\n", joke))
+}
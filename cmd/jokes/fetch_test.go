@@ -0,0 +1,45 @@
+package jokes
+
+import (
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/cmd"
+	jokelib "github.com/williampsena/ebenezer-cli/internal/jokes"
+)
+
+func TestFetchCmd_Run(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		name      string
+		provider  string
+		format    string
+		options   jokelib.Options
+		expectErr bool
+	}{
+		{"Text", "bible", "text", jokelib.Options{}, false},
+		{"JSON", "votd", "json", jokelib.Options{}, false},
+		{"Filtered", "votd", "text", jokelib.Options{MaxLength: 1}, true},
+		{"UnknownProvider", "unknown", "text", jokelib.Options{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetchCmd := &FetchCmd{Provider: tt.provider, Format: tt.format, Filters: tt.options}
+
+			err := fetchCmd.Run(&cmd.Context{Silent: true})
+			if tt.expectErr != (err != nil) {
+				t.Errorf("Expected error %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestProvidersCmd_Run(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if err := (&ProvidersCmd{}).Run(&cmd.Context{Silent: true}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
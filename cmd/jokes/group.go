@@ -0,0 +1,13 @@
+package jokes
+
+type JokesGroup struct {
+	Fetch     FetchCmd     `cmd:"" help:"Fetch a joke from a provider"`
+	Providers ProvidersCmd `cmd:"" help:"List the registered providers and their cache state"`
+	Cache     CacheGroup   `cmd:"" help:"Manage the cached joke pools"`
+}
+
+type CacheGroup struct {
+	List    CacheListCmd    `cmd:"" help:"List the cached pools"`
+	Clear   CacheClearCmd   `cmd:"" help:"Remove the cached pools of providers (all when none is given)"`
+	Refresh CacheRefreshCmd `cmd:"" help:"Fetch new entries for providers (the cached ones when none is given)"`
+}
@@ -0,0 +1,22 @@
+package jokes
+
+import (
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	jokelib "github.com/williampsena/ebenezer-cli/internal/jokes"
+)
+
+type JokesCmd struct {
+	cmd.BaseCmd
+	Providers string              `help:"YAML file declaring HTTP providers (default: ~/.config/ebenezer/providers.yaml)" default:""`
+	store     *jokelib.CacheStore `kong:"-"`
+}
+
+func (c *JokesCmd) setup(ctx *cmd.Context) error {
+	c.SetupContext(ctx)
+
+	if c.store == nil {
+		c.store = jokelib.NewCacheStore(c.Logger, "")
+	}
+
+	return jokelib.RegisterConfiguredProviders(c.Providers)
+}
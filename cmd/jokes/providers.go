@@ -0,0 +1,67 @@
+package jokes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmd "github.com/williampsena/ebenezer-cli/internal/cmd"
+	formatters "github.com/williampsena/ebenezer-cli/internal/cmd/formatters"
+	jokelib "github.com/williampsena/ebenezer-cli/internal/jokes"
+)
+
+type ProvidersCmd struct {
+	JokesCmd
+}
+
+func (c *ProvidersCmd) Run(ctx *cmd.Context) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	pools, err := c.store.List()
+	if err != nil {
+		c.Logger.Error("Error listing cache", "error", err)
+		return err
+	}
+
+	var lines []string
+	for _, provider := range jokelib.Providers() {
+		lines = append(lines, fmt.Sprintf("%-20s %-8s %s", provider.Name, provider.Kind, c.cacheState(provider, pools)))
+	}
+
+	return formatters.WriteToStdout(strings.Join(lines, "\n") + "\n")
+}
+
+// cacheState sums the pools of provider, one per set of options for filtered providers.
+func (c *ProvidersCmd) cacheState(provider jokelib.ProviderInfo, pools []*jokelib.Pool) string {
+	if !provider.Cached {
+		return "-"
+	}
+
+	var matched, entries, unseen int
+	var fetchedAt time.Time
+	for _, pool := range pools {
+		if pool.Provider != provider.Name && !strings.HasPrefix(pool.Provider, provider.Name+":") {
+			continue
+		}
+
+		matched++
+		entries += len(pool.Entries)
+		unseen += len(pool.Remaining())
+		if pool.FetchedAt.After(fetchedAt) {
+			fetchedAt = pool.FetchedAt
+		}
+	}
+
+	if entries == 0 {
+		return "not cached"
+	}
+
+	state := fmt.Sprintf("%d entries, %d unseen, fetched %s", entries, unseen, fetchedAt.Format("2006-01-02 15:04:05"))
+	if matched > 1 {
+		state = fmt.Sprintf("%d pools, %s", matched, state)
+	}
+
+	return state
+}
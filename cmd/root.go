@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"github.com/williampsena/ebenezer-cli/cmd/config"
 	"github.com/williampsena/ebenezer-cli/cmd/desktop"
 	"github.com/williampsena/ebenezer-cli/cmd/hyprland"
+	"github.com/williampsena/ebenezer-cli/cmd/jokes"
 	"github.com/williampsena/ebenezer-cli/cmd/widgets"
 )
 
@@ -12,4 +14,6 @@ type CLI struct {
 	Desktop  desktop.DesktopGroup   `cmd:"" help:"Desktop commands"`
 	Widgets  widgets.WidgetGroup    `cmd:"" help:"Waybar commands (JSON mode)"`
 	Hyprland hyprland.HyprlandGroup `cmd:"" help:"Hyprland commands"`
+	Config   config.ConfigGroup     `cmd:"" help:"Generated config files (backups, rollback)"`
+	Jokes    jokes.JokesGroup       `cmd:"" help:"Joke and quote providers (fetch, providers, cache)"`
 }
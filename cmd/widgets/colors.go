@@ -1,5 +1,34 @@
 package widgets
 
+import (
+	"errors"
+	"os"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/theme"
+)
+
 var color_low = "#f8f8f2"
 var color_medium = "#ffff00"
 var color_high = "#ff0000"
+
+// loadThemeColors replaces the default colours with the ones generated from the wallpaper.
+func loadThemeColors(logger core.Logger, path string) {
+	colors, err := theme.LoadWidgetColors(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warning("Error loading theme colors", "path", path, "error", err)
+		}
+		return
+	}
+
+	if colors.Low != "" {
+		color_low = colors.Low
+	}
+	if colors.Medium != "" {
+		color_medium = colors.Medium
+	}
+	if colors.High != "" {
+		color_high = colors.High
+	}
+}
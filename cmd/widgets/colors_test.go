@@ -1,6 +1,12 @@
 package widgets
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
 
 func TestColors(t *testing.T) {
 	tests := []struct {
@@ -21,3 +27,33 @@ func TestColors(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadThemeColors(t *testing.T) {
+	defaults := []string{color_low, color_medium, color_high}
+	t.Cleanup(func() {
+		color_low, color_medium, color_high = defaults[0], defaults[1], defaults[2]
+	})
+
+	logger := core.BuildSilentLogger()
+	dir := t.TempDir()
+
+	loadThemeColors(logger, filepath.Join(dir, "missing.json"))
+	if color_low != defaults[0] {
+		t.Errorf("Expected defaults to be kept without a colors file, got %s", color_low)
+	}
+
+	path := filepath.Join(dir, "colors.json")
+	if err := os.WriteFile(path, []byte(`{"low": "#eeeeee", "high": "#dd2222"}`), 0644); err != nil {
+		t.Fatalf("Failed to write colors: %v", err)
+	}
+
+	loadThemeColors(logger, path)
+
+	if color_low != "#eeeeee" || color_high != "#dd2222" {
+		t.Errorf("Expected loaded colours, got low=%s high=%s", color_low, color_high)
+	}
+
+	if color_medium != defaults[1] {
+		t.Errorf("Expected medium to keep its default, got %s", color_medium)
+	}
+}
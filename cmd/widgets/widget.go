@@ -2,6 +2,7 @@ package widgets
 
 import (
 	"github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/theme"
 )
 
 type WidgetCmd struct {
@@ -12,4 +13,5 @@ type WidgetCmd struct {
 
 func (h *WidgetCmd) SetupContext(debug bool) {
 	h.logger = core.BuildLogger(debug)
+	loadThemeColors(h.logger, theme.WidgetColorsPath())
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/sanitize"
 )
 
 type WaybarOutput struct {
@@ -21,7 +22,7 @@ func (w WaybarFormatter) Format(data map[string]interface{}) (string, error) {
 	output := WaybarOutput{
 		Icon:    core.GetMapValue(data, "icon", "").(string),
 		Text:    w.buildWidgetText(data),
-		Tooltip: data["tooltip"].(string),
+		Tooltip: w.sanitize(data["tooltip"].(string)),
 		Class:   data["class"].(string),
 		Color:   data["color"].(string),
 	}
@@ -33,8 +34,18 @@ func (w WaybarFormatter) Format(data map[string]interface{}) (string, error) {
 	return string(jsonOutput), nil
 }
 
+// sanitize escapes widget text for the Pango markup Waybar renders.
+func (w WaybarFormatter) sanitize(text string) string {
+	sanitized, err := sanitize.Sanitize(sanitize.TargetWaybar, text)
+	if err != nil {
+		return text
+	}
+
+	return sanitized
+}
+
 func (w WaybarFormatter) buildWidgetText(data map[string]interface{}) string {
-	text := data["text"].(string)
+	text := w.sanitize(data["text"].(string))
 	color := data["color"].(string)
 	noIcon := core.GetMapValue(data, "no-icon", false).(bool)
 
@@ -151,3 +151,31 @@ func TestWaybarOutput(t *testing.T) {
 		}
 	})
 }
+
+func TestWaybarFormatter_EscapesMarkup(t *testing.T) {
+	formatter := WaybarFormatter{}
+	data := map[string]interface{}{
+		"text":    "R&D <beta>",
+		"tooltip": "Linux 6.1 <custom>",
+		"class":   "normal",
+		"color":   "#ffffff",
+		"no-icon": true,
+	}
+
+	result, err := formatter.Format(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var output WaybarOutput
+	if err := json.Unmarshal([]byte(result), &output); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	if output.Text != "<span foreground='#ffffff'>R&amp;D &lt;beta&gt;</span>" {
+		t.Errorf("Expected escaped text, got '%s'", output.Text)
+	}
+	if output.Tooltip != "Linux 6.1 &lt;custom&gt;" {
+		t.Errorf("Expected escaped tooltip, got '%s'", output.Tooltip)
+	}
+}
@@ -0,0 +1,264 @@
+package configfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+const (
+	DefaultKeep     = 5
+	backupExtension = ".bak"
+	timestampFormat = "20060102T150405.000000000"
+)
+
+// Backup is a saved copy of a config file, identified by its timestamp.
+type Backup struct {
+	ID   string
+	Path string
+	Time time.Time
+}
+
+// Writer replaces config files atomically, keeping timestamped backups of what it overwrites.
+type Writer interface {
+	// Write replaces path with data, backing up the previous content when it differs.
+	Write(path string, data []byte) error
+	// Backups returns the backups of path, newest first.
+	Backups(path string) ([]Backup, error)
+	// Rollback restores the backup with id, or the newest one that differs from the current
+	// file when id is empty. The replaced content is backed up, so a rollback can be undone.
+	Rollback(path string, id string) (Backup, error)
+}
+
+type Options struct {
+	// BackupDir holds one directory of backups per config file.
+	BackupDir string
+	// Keep is the number of backups kept per file.
+	Keep int
+}
+
+type writerImpl struct {
+	logger  core.Logger
+	options Options
+}
+
+// DefaultBackupDir returns the backups directory under the XDG state directory.
+func DefaultBackupDir() string {
+	return filepath.Join(core.StateDir(), "backups")
+}
+
+func NewWriter(logger core.Logger) Writer {
+	return NewWriterWithOptions(logger, Options{})
+}
+
+func NewWriterWithOptions(logger core.Logger, options Options) Writer {
+	if options.BackupDir == "" {
+		options.BackupDir = DefaultBackupDir()
+	}
+
+	if options.Keep <= 0 {
+		options.Keep = DefaultKeep
+	}
+
+	return &writerImpl{logger: logger, options: options}
+}
+
+func (w *writerImpl) Write(path string, data []byte) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	case bytes.Equal(current, data):
+		w.logger.Debug("Config file unchanged", "path", path)
+		return nil
+	default:
+		if err := w.backup(path, current); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteAtomic(path, data, 0644); err != nil {
+		return err
+	}
+
+	w.logger.Debug("Config file written", "path", path)
+	return nil
+}
+
+func (w *writerImpl) Backups(path string) ([]Backup, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := w.backupDir(path)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups of %s: %w", path, err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), backupExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		timestamp, err := time.ParseInLocation(timestampFormat, id, time.Local)
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, Backup{ID: id, Path: filepath.Join(dir, entry.Name()), Time: timestamp})
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		return strings.Compare(b.ID, a.ID)
+	})
+
+	return backups, nil
+}
+
+func (w *writerImpl) Rollback(path string, id string) (Backup, error) {
+	backups, err := w.Backups(path)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	if len(backups) == 0 {
+		return Backup{}, fmt.Errorf("no backups found for %s", path)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Backup{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for _, backup := range backups {
+		if id != "" && backup.ID != id {
+			continue
+		}
+
+		data, err := os.ReadFile(backup.Path)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to read backup %s: %w", backup.ID, err)
+		}
+
+		if id == "" && bytes.Equal(data, current) {
+			continue
+		}
+
+		if err := w.Write(path, data); err != nil {
+			return Backup{}, err
+		}
+
+		w.logger.Info("Config file restored", "path", path, "backup", backup.ID)
+		return backup, nil
+	}
+
+	if id != "" {
+		return Backup{}, fmt.Errorf("backup '%s' not found for %s", id, path)
+	}
+
+	return Backup{}, fmt.Errorf("every backup of %s matches the current file", path)
+}
+
+func (w *writerImpl) backup(path string, data []byte) error {
+	dir := w.backupDir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	id := time.Now().Format(timestampFormat)
+	if err := WriteAtomic(filepath.Join(dir, id+backupExtension), data, 0600); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	return w.prune(path)
+}
+
+func (w *writerImpl) prune(path string) error {
+	backups, err := w.Backups(path)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(len(backups), w.options.Keep):] {
+		if err := os.Remove(backup.Path); err != nil {
+			w.logger.Warning("Error removing old backup", "path", backup.Path, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// backupDir names the directory after the escaped absolute path of the config file.
+func (w *writerImpl) backupDir(path string) string {
+	return filepath.Join(w.options.BackupDir, url.PathEscape(path))
+}
+
+// WriteAtomic writes data to a temporary file next to path and renames it over path, so
+// readers see either the old or the new content. An existing file keeps its permissions and
+// symlinks are followed, so configs managed by dotfile tools stay links.
+func WriteAtomic(path string, data []byte, perm fs.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(temp.Name(), perm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
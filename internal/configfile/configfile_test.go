@@ -0,0 +1,146 @@
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func buildTestWriter(t *testing.T, keep int) (Writer, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	writer := NewWriterWithOptions(core.BuildSilentLogger(), Options{
+		BackupDir: filepath.Join(dir, "backups"),
+		Keep:      keep,
+	})
+
+	return writer, filepath.Join(dir, "config", "hyprlock.conf")
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestWriter_Write(t *testing.T) {
+	writer, path := buildTestWriter(t, 5)
+
+	for _, content := range []string{"v1", "v2", "v2", "v3"} {
+		if err := writer.Write(path, []byte(content)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if content := readFile(t, path); content != "v3" {
+		t.Errorf("Expected v3, got %s", content)
+	}
+
+	backups, err := writer.Backups(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups (unchanged writes are skipped), got %d", len(backups))
+	}
+
+	if readFile(t, backups[0].Path) != "v2" || readFile(t, backups[1].Path) != "v1" {
+		t.Errorf("Expected backups newest first")
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+}
+
+func TestWriter_Prune(t *testing.T) {
+	writer, path := buildTestWriter(t, 2)
+
+	for _, content := range []string{"v1", "v2", "v3", "v4", "v5"} {
+		if err := writer.Write(path, []byte(content)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	backups, _ := writer.Backups(path)
+	if len(backups) != 2 || readFile(t, backups[1].Path) != "v3" {
+		t.Errorf("Expected the 2 newest backups to be kept, got %+v", backups)
+	}
+}
+
+func TestWriter_Rollback(t *testing.T) {
+	writer, path := buildTestWriter(t, 5)
+
+	if _, err := writer.Rollback(path, ""); err == nil || !strings.Contains(err.Error(), "no backups") {
+		t.Errorf("Expected a no backups error, got %v", err)
+	}
+
+	for _, content := range []string{"v1", "v2", "broken"} {
+		writer.Write(path, []byte(content))
+	}
+
+	if _, err := writer.Rollback(path, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if content := readFile(t, path); content != "v2" {
+		t.Errorf("Expected v2 to be restored, got %s", content)
+	}
+
+	backups, _ := writer.Backups(path)
+	if readFile(t, backups[0].Path) != "broken" {
+		t.Errorf("Expected the replaced content to be backed up")
+	}
+
+	oldest := backups[len(backups)-1]
+	if _, err := writer.Rollback(path, oldest.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if content := readFile(t, path); content != "v1" {
+		t.Errorf("Expected v1 to be restored, got %s", content)
+	}
+
+	if _, err := writer.Rollback(path, "19990101T000000.000000000"); err == nil {
+		t.Error("Expected error for an unknown backup")
+	}
+}
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real.conf")
+	link := filepath.Join(dir, "link.conf")
+
+	if err := os.WriteFile(target, []byte("old"), 0600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	if err := WriteAtomic(link, []byte("new"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if info, _ := os.Lstat(link); info.Mode()&os.ModeSymlink == 0 {
+		t.Error("Expected the symlink to be preserved")
+	}
+
+	if readFile(t, target) != "new" {
+		t.Errorf("Expected the link target to be updated")
+	}
+
+	if info, _ := os.Stat(target); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected permissions 0600 to be kept, got %v", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,55 @@
+package configfile
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+)
+
+// watchDebounce groups the events of a single save, editors often write a file in steps.
+const watchDebounce = 200 * time.Millisecond
+
+// Watch reports changes of the file at path on the returned channel until ctx is done. The
+// directory of the file is watched, so files replaced by a rename are noticed, and symbolic
+// links are followed to the real file. Changes close together are reported once.
+func Watch(ctx context.Context, path string) (<-chan struct{}, error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	events := make(chan struct{}, 1)
+	if err := watchFile(ctx, path, events); err != nil {
+		return nil, err
+	}
+
+	changes := make(chan struct{}, 1)
+	go debounce(ctx, events, changes, watchDebounce)
+
+	return changes, nil
+}
+
+// debounce forwards a single change once events stop coming for delay.
+func debounce(ctx context.Context, events <-chan struct{}, changes chan<- struct{}, delay time.Duration) {
+	timer := time.NewTimer(delay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-events:
+			timer.Reset(delay)
+		case <-timer.C:
+			notify(changes)
+		}
+	}
+}
+
+// notify sends on channel without blocking; a pending notification covers the new one.
+func notify(channel chan<- struct{}) {
+	select {
+	case channel <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,63 @@
+package configfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchPollTimeout bounds how long the watcher waits for events before checking ctx, in milliseconds.
+const watchPollTimeout = 250
+
+// watchFile uses inotify on the directory of path and reports the events about the file.
+func watchFile(ctx context.Context, path string, events chan<- struct{}) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("failed to start inotify: %w", err)
+	}
+
+	dir, name := filepath.Split(path)
+	if _, err := unix.InotifyAddWatch(fd, dir, unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO|unix.IN_CREATE); err != nil {
+		unix.Close(fd)
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	go func() {
+		defer unix.Close(fd)
+
+		buffer := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+
+		for ctx.Err() == nil {
+			ready, err := unix.Poll(fds, watchPollTimeout)
+			if errors.Is(err, unix.EINTR) || ready <= 0 {
+				continue
+			}
+			if err != nil {
+				return
+			}
+
+			size, err := unix.Read(fd, buffer)
+			if err != nil {
+				continue
+			}
+
+			for offset := 0; offset+unix.SizeofInotifyEvent <= size; {
+				event := (*unix.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+				start := offset + unix.SizeofInotifyEvent
+				offset = start + int(event.Len)
+
+				if strings.TrimRight(string(buffer[start:offset]), "\x00") == name {
+					notify(events)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,49 @@
+//go:build !linux
+
+package configfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const watchPollInterval = 2 * time.Second
+
+// watchFile polls the modification time of path where inotify is not available.
+func watchFile(ctx context.Context, path string, events chan<- struct{}) error {
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", filepath.Dir(path), err)
+	}
+
+	modified := func() time.Time {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	last := modified()
+
+	go func() {
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if current := modified(); !current.Equal(last) {
+					last = current
+					notify(events)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package configfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func expectChange(t *testing.T, changes <-chan struct{}, expected bool) {
+	t.Helper()
+
+	timeout := 500 * time.Millisecond
+	if expected {
+		timeout = 5 * time.Second
+	}
+
+	select {
+	case <-changes:
+		if !expected {
+			t.Fatal("Unexpected change")
+		}
+	case <-time.After(timeout):
+		if expected {
+			t.Fatal("Expected a change")
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cron.yaml")
+	os.WriteFile(path, []byte("jobs: []\n"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := Watch(ctx, path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x"), 0644)
+	expectChange(t, changes, false)
+
+	os.WriteFile(path, []byte("jobs: [a]\n"), 0644)
+	expectChange(t, changes, true)
+
+	// editors often save by renaming a temporary file over the original
+	if err := WriteAtomic(path, []byte("jobs: [b]\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectChange(t, changes, true)
+	expectChange(t, changes, false)
+}
+
+func TestWatch_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "cron.yaml")
+	link := filepath.Join(dir, "cron.yaml")
+	os.MkdirAll(filepath.Dir(target), 0755)
+	os.WriteFile(target, []byte("jobs: []\n"), 0644)
+	os.Symlink(target, link)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := Watch(ctx, link)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	os.WriteFile(target, []byte("jobs: [a]\n"), 0644)
+	expectChange(t, changes, true)
+}
+
+func TestWatch_MissingDir(t *testing.T) {
+	if _, err := Watch(context.Background(), filepath.Join(t.TempDir(), "missing", "cron.yaml")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,149 @@
+// Package control is a small request/reply protocol over a Unix socket, used by long-running
+// commands such as the cron daemon so other invocations can query and drive them. Each
+// connection carries one JSON request and one JSON reply.
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+const callTimeout = 5 * time.Second
+
+// ErrNotRunning is returned by Call when nothing listens on the socket.
+var ErrNotRunning = errors.New("daemon is not running")
+
+// Request asks the daemon to run Command, with an optional target.
+type Request struct {
+	Command string `json:"command"`
+	Target  string `json:"target,omitempty"`
+}
+
+// Reply carries the result of a request, or its error.
+type Reply struct {
+	Error string          `json:"error,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// Handler answers a request; the result is encoded as JSON.
+type Handler func(request Request) (any, error)
+
+// SocketPath returns the socket named name under the runtime directory.
+func SocketPath(name string) string {
+	return filepath.Join(core.RuntimeDir(), name+".sock")
+}
+
+// Listen serves requests on the socket at path until ctx is done, then removes the socket.
+// A socket left by a daemon that died is replaced; a live one is an error.
+func Listen(ctx context.Context, logger core.Logger, path string, handler Handler) error {
+	if conn, err := net.DialTimeout("unix", path, callTimeout); err == nil {
+		conn.Close()
+		return fmt.Errorf("another daemon is listening on %s", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict socket %s: %w", path, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	go func() {
+		defer os.Remove(path)
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("Control socket stopped", "socket", path, "error", err)
+				}
+				return
+			}
+
+			go serve(logger, conn, handler)
+		}
+	}()
+
+	return nil
+}
+
+func serve(logger core.Logger, conn net.Conn, handler Handler) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(callTimeout))
+
+	var request Request
+	var reply Reply
+
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&request); err != nil {
+		reply.Error = fmt.Sprintf("invalid request: %v", err)
+	} else if result, err := handler(request); err != nil {
+		reply.Error = err.Error()
+	} else if result != nil {
+		if reply.Data, err = json.Marshal(result); err != nil {
+			reply.Error = fmt.Sprintf("failed to encode reply: %v", err)
+		}
+	}
+
+	if err := json.NewEncoder(conn).Encode(reply); err != nil {
+		logger.Warning("Failed to write control reply", "command", request.Command, "error", err)
+	}
+}
+
+// Call sends request to the daemon listening on path and decodes the reply data into result,
+// which may be nil.
+func Call(path string, request Request, result any) error {
+	conn, err := net.DialTimeout("unix", path, callTimeout)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("%w (no socket at %s)", ErrNotRunning, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", path, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(callTimeout))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return fmt.Errorf("failed to send '%s': %w", request.Command, err)
+	}
+
+	var reply Reply
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return fmt.Errorf("failed to read reply to '%s': %w", request.Command, err)
+	}
+
+	if reply.Error != "" {
+		return errors.New(reply.Error)
+	}
+
+	if result != nil && len(reply.Data) > 0 {
+		if err := json.Unmarshal(reply.Data, result); err != nil {
+			return fmt.Errorf("failed to decode reply to '%s': %w", request.Command, err)
+		}
+	}
+
+	return nil
+}
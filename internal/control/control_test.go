@@ -0,0 +1,102 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func socketPath(t *testing.T) string {
+	t.Helper()
+
+	// socket paths are limited to ~108 bytes, keep them short
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "test.sock")
+}
+
+func TestListenAndCall(t *testing.T) {
+	path := socketPath(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := Listen(ctx, core.BuildSilentLogger(), path, func(request Request) (any, error) {
+		switch request.Command {
+		case "echo":
+			return map[string]string{"target": request.Target}, nil
+		case "nothing":
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("unknown command '%s'", request.Command)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result map[string]string
+	if err := Call(path, Request{Command: "echo", Target: "wallpaper"}, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result["target"] != "wallpaper" {
+		t.Errorf("Unexpected result: %v", result)
+	}
+
+	if err := Call(path, Request{Command: "nothing"}, nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := Call(path, Request{Command: "boom"}, nil); err == nil || err.Error() != "unknown command 'boom'" {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a private socket, got %v %v", info, err)
+	}
+
+	if err := Listen(ctx, core.BuildSilentLogger(), path, nil); err == nil {
+		t.Error("Expected an error when a daemon is already listening")
+	}
+}
+
+func TestCall_NotRunning(t *testing.T) {
+	path := socketPath(t)
+
+	if err := Call(path, Request{Command: "list"}, nil); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Expected ErrNotRunning, got %v", err)
+	}
+
+	// a socket left by a dead daemon
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+
+	if err := Call(path, Request{Command: "list"}, nil); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Expected ErrNotRunning for a stale socket, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := Listen(ctx, core.BuildSilentLogger(), path, func(Request) (any, error) { return "ok", nil }); err != nil {
+		t.Fatalf("Expected the stale socket to be replaced, got %v", err)
+	}
+
+	var result string
+	if err := Call(path, Request{Command: "ping"}, &result); err != nil || result != "ok" {
+		t.Errorf("Unexpected reply %q: %v", result, err)
+	}
+}
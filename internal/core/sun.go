@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Coordinates is a position on Earth in decimal degrees, north and east positive.
+type Coordinates struct {
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+}
+
+// official zenith for sunrise/sunset, accounting for refraction and the solar disc
+const sunZenith = 90.833
+
+// SunTimes returns the sunrise and sunset of the day of date at coords, in date's location.
+// It uses the almanac algorithm from the US Naval Observatory, accurate to a couple of
+// minutes, and fails on polar days and nights.
+func SunTimes(date time.Time, coords Coordinates) (time.Time, time.Time, error) {
+	sunrise, err := sunEvent(date, coords, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	sunset, err := sunEvent(date, coords, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return sunrise, sunset, nil
+}
+
+func sunEvent(date time.Time, coords Coordinates, rising bool) (time.Time, error) {
+	lngHour := coords.Longitude / 15
+
+	base := 18.0
+	if rising {
+		base = 6
+	}
+	t := float64(date.YearDay()) + (base-lngHour)/24
+
+	meanAnomaly := 0.9856*t - 3.289
+	longitude := normalizeDegrees(meanAnomaly + 1.916*sinDeg(meanAnomaly) + 0.020*sinDeg(2*meanAnomaly) + 282.634)
+
+	rightAscension := normalizeDegrees(radToDeg(math.Atan(0.91764 * math.Tan(degToRad(longitude)))))
+	rightAscension += math.Floor(longitude/90)*90 - math.Floor(rightAscension/90)*90
+	rightAscension /= 15
+
+	sinDec := 0.39782 * sinDeg(longitude)
+	cosDec := math.Cos(math.Asin(sinDec))
+
+	cosHour := (cosDeg(sunZenith) - sinDec*sinDeg(coords.Latitude)) / (cosDec * cosDeg(coords.Latitude))
+	if cosHour > 1 {
+		return time.Time{}, fmt.Errorf("the sun does not rise on %s at %v", date.Format(time.DateOnly), coords)
+	}
+	if cosHour < -1 {
+		return time.Time{}, fmt.Errorf("the sun does not set on %s at %v", date.Format(time.DateOnly), coords)
+	}
+
+	hourAngle := radToDeg(math.Acos(cosHour))
+	if rising {
+		hourAngle = 360 - hourAngle
+	}
+	hourAngle /= 15
+
+	localMean := hourAngle + rightAscension - 0.06571*t - 6.622
+	universal := math.Mod(localMean-lngHour+48, 24)
+
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	event := midnight.Add(time.Duration(universal * float64(time.Hour))).In(date.Location())
+
+	// keep the clock time but move it to the requested local day
+	return time.Date(date.Year(), date.Month(), date.Day(), event.Hour(), event.Minute(), event.Second(), 0, date.Location()), nil
+}
+
+func normalizeDegrees(value float64) float64 {
+	value = math.Mod(value, 360)
+	if value < 0 {
+		value += 360
+	}
+	return value
+}
+
+func degToRad(value float64) float64 { return value * math.Pi / 180 }
+func radToDeg(value float64) float64 { return value * 180 / math.Pi }
+func sinDeg(value float64) float64   { return math.Sin(degToRad(value)) }
+func cosDeg(value float64) float64   { return math.Cos(degToRad(value)) }
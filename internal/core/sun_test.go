@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunTimes(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	london, _ := time.LoadLocation("Europe/London")
+	if saoPaulo == nil || london == nil {
+		t.Skip("time zone database not available")
+	}
+
+	tests := []struct {
+		name    string
+		date    time.Time
+		coords  Coordinates
+		sunrise string
+		sunset  string
+	}{
+		{"London", time.Date(2024, 6, 21, 12, 0, 0, 0, london), Coordinates{51.5074, -0.1278}, "04:43", "21:21"},
+		{"SaoPaulo", time.Date(2024, 12, 21, 12, 0, 0, 0, saoPaulo), Coordinates{-23.5505, -46.6333}, "05:15", "18:55"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sunrise, sunset, err := SunTimes(tt.date, tt.coords)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			assertNear(t, "sunrise", sunrise, tt.date, tt.sunrise)
+			assertNear(t, "sunset", sunset, tt.date, tt.sunset)
+		})
+	}
+
+	t.Run("PolarNight", func(t *testing.T) {
+		if _, _, err := SunTimes(time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC), Coordinates{78.22, 15.65}); err == nil {
+			t.Error("Expected error during the polar night")
+		}
+	})
+}
+
+func assertNear(t *testing.T, name string, got time.Time, date time.Time, expected string) {
+	t.Helper()
+
+	clock, _ := time.Parse("15:04", expected)
+	want := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, date.Location())
+
+	if diff := got.Sub(want).Abs(); diff > 5*time.Minute {
+		t.Errorf("Expected %s near %s, got %s", name, expected, got.Format("15:04"))
+	}
+}
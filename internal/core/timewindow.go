@@ -0,0 +1,130 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	AnchorClock   = "clock"
+	AnchorSunrise = "sunrise"
+	AnchorSunset  = "sunset"
+)
+
+// TimeOfDay is a point in the day: a clock time ("07:30") or sunrise/sunset with an
+// optional offset ("sunset-30m", "sunrise+1h").
+type TimeOfDay struct {
+	Anchor string
+	Clock  time.Duration
+	Offset time.Duration
+}
+
+// ParseTimeOfDay parses "HH:MM", "sunrise", "sunset" and their "+duration"/"-duration" forms.
+func ParseTimeOfDay(value string) (TimeOfDay, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	for _, anchor := range []string{AnchorSunrise, AnchorSunset} {
+		if !strings.HasPrefix(value, anchor) {
+			continue
+		}
+
+		result := TimeOfDay{Anchor: anchor}
+		if rest := strings.TrimPrefix(value, anchor); rest != "" {
+			if rest[0] != '+' && rest[0] != '-' {
+				return TimeOfDay{}, fmt.Errorf("invalid time '%s': expected +/- offset after %s", value, anchor)
+			}
+
+			offset, err := time.ParseDuration(rest)
+			if err != nil {
+				return TimeOfDay{}, fmt.Errorf("invalid time offset '%s': %w", value, err)
+			}
+			result.Offset = offset
+		}
+
+		return result, nil
+	}
+
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return TimeOfDay{}, fmt.Errorf("invalid time '%s': expected HH:MM, sunrise or sunset", value)
+	}
+
+	return TimeOfDay{
+		Anchor: AnchorClock,
+		Clock:  time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute,
+	}, nil
+}
+
+// UsesSun reports whether the time depends on sunrise or sunset.
+func (t TimeOfDay) UsesSun() bool {
+	return t.Anchor == AnchorSunrise || t.Anchor == AnchorSunset
+}
+
+// At returns the time on the day of date. coords is only needed for sun anchored times.
+func (t TimeOfDay) At(date time.Time, coords *Coordinates) (time.Time, error) {
+	if !t.UsesSun() {
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+		return midnight.Add(t.Clock), nil
+	}
+
+	if coords == nil {
+		return time.Time{}, fmt.Errorf("%s requires latitude and longitude", t.Anchor)
+	}
+
+	sunrise, sunset, err := SunTimes(date, *coords)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if t.Anchor == AnchorSunrise {
+		return sunrise.Add(t.Offset), nil
+	}
+
+	return sunset.Add(t.Offset), nil
+}
+
+// TimeWindow is the daily span [From, To). A window whose end comes before its start
+// wraps around midnight, e.g. sunset to sunrise.
+type TimeWindow struct {
+	From TimeOfDay
+	To   TimeOfDay
+}
+
+func ParseTimeWindow(from, to string) (TimeWindow, error) {
+	start, err := ParseTimeOfDay(from)
+	if err != nil {
+		return TimeWindow{}, err
+	}
+
+	end, err := ParseTimeOfDay(to)
+	if err != nil {
+		return TimeWindow{}, err
+	}
+
+	return TimeWindow{From: start, To: end}, nil
+}
+
+// UsesSun reports whether either bound depends on sunrise or sunset.
+func (w TimeWindow) UsesSun() bool {
+	return w.From.UsesSun() || w.To.UsesSun()
+}
+
+// Contains reports whether now falls inside the window.
+func (w TimeWindow) Contains(now time.Time, coords *Coordinates) (bool, error) {
+	start, err := w.From.At(now, coords)
+	if err != nil {
+		return false, err
+	}
+
+	end, err := w.To.At(now, coords)
+	if err != nil {
+		return false, err
+	}
+
+	if !end.Before(start) {
+		return !now.Before(start) && now.Before(end), nil
+	}
+
+	return !now.Before(start) || now.Before(end), nil
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected TimeOfDay
+		hasError bool
+	}{
+		{"07:30", TimeOfDay{Anchor: AnchorClock, Clock: 7*time.Hour + 30*time.Minute}, false},
+		{"sunrise", TimeOfDay{Anchor: AnchorSunrise}, false},
+		{"Sunset-30m", TimeOfDay{Anchor: AnchorSunset, Offset: -30 * time.Minute}, false},
+		{"sunrise+1h", TimeOfDay{Anchor: AnchorSunrise, Offset: time.Hour}, false},
+		{"sunrise1h", TimeOfDay{}, true},
+		{"sunset+soon", TimeOfDay{}, true},
+		{"25:00", TimeOfDay{}, true},
+		{"", TimeOfDay{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result, err := ParseTimeOfDay(tt.value)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("Expected error for '%s'", tt.value)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		now      time.Time
+		expected bool
+	}{
+		{"Inside", "06:00", "12:00", day(9, 0), true},
+		{"StartIsInclusive", "06:00", "12:00", day(6, 0), true},
+		{"EndIsExclusive", "06:00", "12:00", day(12, 0), false},
+		{"WrapsBeforeMidnight", "20:00", "06:00", day(23, 0), true},
+		{"WrapsAfterMidnight", "20:00", "06:00", day(2, 0), true},
+		{"OutsideWrapped", "20:00", "06:00", day(12, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := ParseTimeWindow(tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result, err := window.Contains(tt.now, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("SunWithoutCoordinates", func(t *testing.T) {
+		window, _ := ParseTimeWindow("sunset", "sunrise")
+		if _, err := window.Contains(day(12, 0), nil); err == nil {
+			t.Error("Expected error without coordinates")
+		}
+	})
+
+	t.Run("SunsetToSunrise", func(t *testing.T) {
+		window, _ := ParseTimeWindow("sunset", "sunrise")
+		equator := &Coordinates{Latitude: 0, Longitude: 0}
+
+		if night, _ := window.Contains(day(23, 0), equator); !night {
+			t.Error("Expected 23:00 to be night at the equator")
+		}
+		if night, _ := window.Contains(day(12, 0), equator); night {
+			t.Error("Expected noon to be day at the equator")
+		}
+	})
+}
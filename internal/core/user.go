@@ -0,0 +1,10 @@
+package core
+
+import (
+	"os"
+	"strconv"
+)
+
+func currentUID() string {
+	return strconv.Itoa(os.Getuid())
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const appName = "ebenezer"
+
+// StateDir returns the application state directory, $XDG_STATE_HOME/ebenezer
+// or ~/.local/state/ebenezer when the variable is not set.
+func StateDir() string {
+	return xdgDir("XDG_STATE_HOME", "~/.local/state")
+}
+
+// CacheDir returns the application cache directory, $XDG_CACHE_HOME/ebenezer
+// or ~/.cache/ebenezer when the variable is not set.
+func CacheDir() string {
+	return xdgDir("XDG_CACHE_HOME", "~/.cache")
+}
+
+// ConfigDir returns the application config directory, $XDG_CONFIG_HOME/ebenezer
+// or ~/.config/ebenezer when the variable is not set.
+func ConfigDir() string {
+	return xdgDir("XDG_CONFIG_HOME", "~/.config")
+}
+
+// RuntimeDir returns the application runtime directory, $XDG_RUNTIME_DIR/ebenezer
+// or a per-user directory under the system temp dir when the variable is not set.
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, appName)
+	}
+
+	return filepath.Join(os.TempDir(), appName+"-"+currentUID())
+}
+
+func xdgDir(envName, fallback string) string {
+	dir := os.Getenv(envName)
+	if dir == "" || !filepath.IsAbs(dir) {
+		dir = ResolvePath(fallback)
+	}
+
+	return filepath.Join(dir, appName)
+}
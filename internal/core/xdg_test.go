@@ -0,0 +1,36 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestXdgDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name     string
+		envName  string
+		value    string
+		dir      func() string
+		expected string
+	}{
+		{"StateFromEnv", "XDG_STATE_HOME", "/var/state", StateDir, "/var/state/ebenezer"},
+		{"StateFallback", "XDG_STATE_HOME", "", StateDir, filepath.Join(home, ".local/state/ebenezer")},
+		{"CacheFromEnv", "XDG_CACHE_HOME", "/var/cache", CacheDir, "/var/cache/ebenezer"},
+		{"CacheRelativeIgnored", "XDG_CACHE_HOME", "relative", CacheDir, filepath.Join(home, ".cache/ebenezer")},
+		{"ConfigFallback", "XDG_CONFIG_HOME", "", ConfigDir, filepath.Join(home, ".config/ebenezer")},
+		{"RuntimeFromEnv", "XDG_RUNTIME_DIR", "/run/user/1000", RuntimeDir, "/run/user/1000/ebenezer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.envName, tt.value)
+
+			if result := tt.dir(); result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
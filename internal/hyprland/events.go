@@ -0,0 +1,82 @@
+package hyprland
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const eventSocketName = ".socket2.sock"
+
+// Event is a single line received from the Hyprland event socket, e.g. `workspace>>2`.
+type Event struct {
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+// EventHandler receives every event read from the event socket.
+type EventHandler func(Event)
+
+// ParseEvent splits a raw `EVENT>>DATA` line into an Event.
+func ParseEvent(line string) (Event, error) {
+	name, data, found := strings.Cut(strings.TrimRight(line, "\r\n"), ">>")
+	if !found || name == "" {
+		return Event{}, fmt.Errorf("malformed event line: %q", line)
+	}
+
+	return Event{Name: name, Data: data}, nil
+}
+
+// Fields splits the event data on commas into at most n fields, so that the last
+// field may keep commas (window titles often contain them). n < 0 returns all fields.
+func (e Event) Fields(n int) []string {
+	return strings.SplitN(e.Data, ",", n)
+}
+
+func (e Event) String() string {
+	return e.Name + ">>" + e.Data
+}
+
+func (c *ipcClientImpl) Events(ctx context.Context, handler EventHandler) error {
+	path, err := c.socketPath(eventSocketName)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", path, err)
+	}
+	defer conn.Close()
+
+	c.logger.Debug("Listening for Hyprland events", "socket", path)
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		event, err := ParseEvent(scanner.Text())
+		if err != nil {
+			c.logger.Warning("Skipping malformed event", "error", err)
+			continue
+		}
+
+		handler(event)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read events: %w", err)
+	}
+
+	return fmt.Errorf("event socket %s closed", path)
+}
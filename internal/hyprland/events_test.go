@@ -0,0 +1,140 @@
+package hyprland
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		expected  Event
+		expectErr bool
+	}{
+		{"Workspace", "workspace>>2", Event{Name: "workspace", Data: "2"}, false},
+		{"ActiveWindow", "activewindow>>kitty,~: vim, main.go\n", Event{Name: "activewindow", Data: "kitty,~: vim, main.go"}, false},
+		{"EmptyData", "configreloaded>>", Event{Name: "configreloaded", Data: ""}, false},
+		{"Malformed", "garbage", Event{}, true},
+		{"MissingName", ">>data", Event{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEvent(tt.line)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.line)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if event != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, event)
+			}
+		})
+	}
+}
+
+func TestEvent_Fields(t *testing.T) {
+	event := Event{Name: "openwindow", Data: "80e62df0,2,kitty,title, with commas"}
+
+	fields := event.Fields(4)
+	if len(fields) != 4 {
+		t.Fatalf("Expected 4 fields, got %d", len(fields))
+	}
+
+	if fields[3] != "title, with commas" {
+		t.Errorf("Expected last field to keep commas, got '%s'", fields[3])
+	}
+
+	if all := event.Fields(-1); len(all) != 5 {
+		t.Errorf("Expected 5 fields, got %d", len(all))
+	}
+}
+
+func TestIPCClient_Events(t *testing.T) {
+	dir := t.TempDir()
+
+	listener, err := net.Listen("unix", filepath.Join(dir, eventSocketName))
+	if err != nil {
+		t.Fatalf("Failed to listen on fake socket: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte("workspace>>2\nbroken line\nmonitoradded>>HDMI-A-1\n"))
+		conn.Close()
+	}()
+
+	client := NewIPCClientWithDir(core.BuildSilentLogger(), dir)
+
+	var mu sync.Mutex
+	var events []Event
+
+	err = client.Events(context.Background(), func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+
+	if err == nil {
+		t.Error("Expected error when the event socket closes")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d: %+v", len(events), events)
+	}
+
+	if events[1].Name != "monitoradded" || events[1].Data != "HDMI-A-1" {
+		t.Errorf("Unexpected event: %+v", events[1])
+	}
+}
+
+func TestIPCClient_EventsCancel(t *testing.T) {
+	dir := t.TempDir()
+
+	listener, err := net.Listen("unix", filepath.Join(dir, eventSocketName))
+	if err != nil {
+		t.Fatalf("Failed to listen on fake socket: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		time.Sleep(5 * time.Second)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	client := NewIPCClientWithDir(core.BuildSilentLogger(), dir)
+
+	start := time.Now()
+	err = client.Events(ctx, func(Event) {})
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+
+	if time.Since(start) > 2*time.Second {
+		t.Error("Events did not stop when the context was cancelled")
+	}
+}
@@ -0,0 +1,244 @@
+package hyprland
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+const (
+	controlSocketName   = ".socket.sock"
+	hyprpaperSocketName = ".hyprpaper.sock"
+	defaultIPCTimeout   = 5 * time.Second
+	batchPrefix         = "[[BATCH]]"
+)
+
+var ErrNoInstance = errors.New("HYPRLAND_INSTANCE_SIGNATURE not set, not running inside a Hyprland session")
+
+// IPCClient talks to a running Hyprland instance through its Unix sockets.
+type IPCClient interface {
+	// Request sends a raw request to the control socket and returns the reply.
+	Request(request string) (string, error)
+	// RequestJSON sends a `j/` request to the control socket and decodes the reply into v.
+	RequestJSON(request string, v any) error
+	// Batch sends several commands in a single `[[BATCH]]` request and returns the combined reply.
+	Batch(commands ...string) (string, error)
+	// Monitors returns the connected monitors.
+	Monitors() ([]Monitor, error)
+	// Workspaces returns the existing workspaces.
+	Workspaces() ([]Workspace, error)
+	// Clients returns the mapped windows.
+	Clients() ([]Client, error)
+	// Devices returns the input devices known to Hyprland.
+	Devices() (Devices, error)
+	// Version returns the version of the running Hyprland instance.
+	Version() (Version, error)
+	// Dispatch runs a Hyprland dispatcher, e.g. Dispatch("workspace", "2").
+	Dispatch(dispatcher string, args ...string) error
+	// Reload reloads the Hyprland configuration.
+	Reload() error
+	// Hyprpaper sends a command to the hyprpaper socket of the current instance.
+	Hyprpaper(request string) error
+	// Events streams events from the event socket to handler until ctx is done or the socket closes.
+	Events(ctx context.Context, handler EventHandler) error
+}
+
+type ipcClientImpl struct {
+	logger  core.Logger
+	dir     string
+	dirErr  error
+	timeout time.Duration
+}
+
+// NewIPCClient builds a client for the instance referenced by HYPRLAND_INSTANCE_SIGNATURE.
+func NewIPCClient(logger core.Logger) IPCClient {
+	dir, err := InstanceDir()
+
+	return &ipcClientImpl{
+		logger:  logger,
+		dir:     dir,
+		dirErr:  err,
+		timeout: defaultIPCTimeout,
+	}
+}
+
+// NewIPCClientWithDir builds a client for the sockets located in dir.
+func NewIPCClientWithDir(logger core.Logger, dir string) IPCClient {
+	return &ipcClientImpl{
+		logger:  logger,
+		dir:     dir,
+		timeout: defaultIPCTimeout,
+	}
+}
+
+// InstanceDir resolves the socket directory of the current Hyprland instance,
+// preferring $XDG_RUNTIME_DIR/hypr and falling back to the legacy /tmp/hypr location.
+func InstanceDir() (string, error) {
+	signature := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
+	if signature == "" {
+		return "", ErrNoInstance
+	}
+
+	candidates := []string{filepath.Join("/tmp/hypr", signature)}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append([]string{filepath.Join(runtimeDir, "hypr", signature)}, candidates...)
+	}
+
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+
+	return candidates[0], nil
+}
+
+func (c *ipcClientImpl) socketPath(name string) (string, error) {
+	if c.dirErr != nil {
+		return "", c.dirErr
+	}
+
+	return filepath.Join(c.dir, name), nil
+}
+
+func (c *ipcClientImpl) send(socketName, request string) (string, error) {
+	path, err := c.socketPath(socketName)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Debug("Sending IPC request", "socket", path, "request", request)
+
+	conn, err := net.DialTimeout("unix", path, c.timeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", path, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return "", err
+	}
+
+	if _, err := conn.Write([]byte(request)); err != nil {
+		return "", fmt.Errorf("failed to write request '%s': %w", request, err)
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return "", fmt.Errorf("failed to read reply for '%s': %w", request, err)
+	}
+
+	c.logger.Debug("IPC reply", "request", request, "reply", string(reply))
+
+	return string(reply), nil
+}
+
+func (c *ipcClientImpl) Request(request string) (string, error) {
+	reply, err := c.send(controlSocketName, request)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(reply) == "unknown request" {
+		return "", fmt.Errorf("hyprland rejected request '%s': unknown request", request)
+	}
+
+	return reply, nil
+}
+
+func (c *ipcClientImpl) RequestJSON(request string, v any) error {
+	reply, err := c.Request("j/" + strings.TrimPrefix(request, "j/"))
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(reply), v); err != nil {
+		return fmt.Errorf("failed to decode reply for '%s': %w", request, err)
+	}
+
+	return nil
+}
+
+func (c *ipcClientImpl) Batch(commands ...string) (string, error) {
+	if len(commands) == 0 {
+		return "", fmt.Errorf("empty batch")
+	}
+
+	return c.Request(batchPrefix + strings.Join(commands, ";"))
+}
+
+func (c *ipcClientImpl) Monitors() ([]Monitor, error) {
+	var monitors []Monitor
+	if err := c.RequestJSON("monitors", &monitors); err != nil {
+		return nil, err
+	}
+
+	return monitors, nil
+}
+
+func (c *ipcClientImpl) Workspaces() ([]Workspace, error) {
+	var workspaces []Workspace
+	if err := c.RequestJSON("workspaces", &workspaces); err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+func (c *ipcClientImpl) Clients() ([]Client, error) {
+	var clients []Client
+	if err := c.RequestJSON("clients", &clients); err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (c *ipcClientImpl) Devices() (Devices, error) {
+	var devices Devices
+	err := c.RequestJSON("devices", &devices)
+
+	return devices, err
+}
+
+func (c *ipcClientImpl) Version() (Version, error) {
+	var version Version
+	err := c.RequestJSON("version", &version)
+
+	return version, err
+}
+
+func (c *ipcClientImpl) Dispatch(dispatcher string, args ...string) error {
+	request := strings.TrimSpace("dispatch " + dispatcher + " " + strings.Join(args, " "))
+
+	return c.expectOk(c.Request(request))
+}
+
+func (c *ipcClientImpl) Reload() error {
+	return c.expectOk(c.Request("reload"))
+}
+
+func (c *ipcClientImpl) Hyprpaper(request string) error {
+	return c.expectOk(c.send(hyprpaperSocketName, request))
+}
+
+func (c *ipcClientImpl) expectOk(reply string, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if reply = strings.TrimSpace(reply); reply != "ok" {
+		return fmt.Errorf("unexpected reply from hyprland: %s", reply)
+	}
+
+	return nil
+}
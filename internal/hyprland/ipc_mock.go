@@ -0,0 +1,141 @@
+package hyprland
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+// Stub implementation for testing
+type IPCClientMockImpl struct {
+	logger   core.Logger
+	monitors []Monitor
+	events   []Event
+	Requests []string
+}
+
+func NewIPCClientMock(logger core.Logger, monitors []Monitor) IPCClient {
+	if monitors == nil {
+		monitors = []Monitor{{ID: 0, Name: "eDP-1", Focused: true, ActiveWorkspace: WorkspaceRef{ID: 1, Name: "1"}}}
+	}
+
+	return &IPCClientMockImpl{
+		logger:   logger,
+		monitors: monitors,
+	}
+}
+
+func (m *IPCClientMockImpl) Request(request string) (string, error) {
+	m.logger.Debug("Mock IPC request", "request", request)
+	m.Requests = append(m.Requests, request)
+
+	switch strings.TrimPrefix(request, "j/") {
+	case "monitors":
+		return m.marshal(m.monitors)
+	case "workspaces":
+		workspaces, _ := m.Workspaces()
+		return m.marshal(workspaces)
+	case "clients":
+		return "[]", nil
+	case "devices":
+		return m.marshal(Devices{})
+	case "version":
+		return m.marshal(Version{Branch: "mock", Tag: "v0.0.0"})
+	}
+
+	return "ok", nil
+}
+
+func (m *IPCClientMockImpl) RequestJSON(request string, v any) error {
+	reply, err := m.Request("j/" + strings.TrimPrefix(request, "j/"))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(reply), v)
+}
+
+func (m *IPCClientMockImpl) Batch(commands ...string) (string, error) {
+	if len(commands) == 0 {
+		return "", fmt.Errorf("empty batch")
+	}
+
+	return m.Request(batchPrefix + strings.Join(commands, ";"))
+}
+
+func (m *IPCClientMockImpl) Monitors() ([]Monitor, error) {
+	m.Requests = append(m.Requests, "j/monitors")
+	return m.monitors, nil
+}
+
+func (m *IPCClientMockImpl) Workspaces() ([]Workspace, error) {
+	workspaces := make([]Workspace, 0, len(m.monitors))
+	for _, monitor := range m.monitors {
+		workspaces = append(workspaces, Workspace{
+			ID:        monitor.ActiveWorkspace.ID,
+			Name:      monitor.ActiveWorkspace.Name,
+			Monitor:   monitor.Name,
+			MonitorID: monitor.ID,
+		})
+	}
+
+	return workspaces, nil
+}
+
+func (m *IPCClientMockImpl) Clients() ([]Client, error) {
+	return []Client{}, nil
+}
+
+func (m *IPCClientMockImpl) Devices() (Devices, error) {
+	return Devices{}, nil
+}
+
+func (m *IPCClientMockImpl) Version() (Version, error) {
+	m.Requests = append(m.Requests, "j/version")
+	return Version{Branch: "mock", Tag: "v0.0.0"}, nil
+}
+
+func (m *IPCClientMockImpl) Dispatch(dispatcher string, args ...string) error {
+	_, err := m.Request(strings.TrimSpace("dispatch " + dispatcher + " " + strings.Join(args, " ")))
+	return err
+}
+
+func (m *IPCClientMockImpl) Reload() error {
+	_, err := m.Request("reload")
+	return err
+}
+
+func (m *IPCClientMockImpl) Hyprpaper(request string) error {
+	m.logger.Debug("Mock hyprpaper request", "request", request)
+	m.Requests = append(m.Requests, "hyprpaper "+request)
+	return nil
+}
+
+// SetEvents configures the events replayed by Events.
+func (m *IPCClientMockImpl) SetEvents(events []Event) {
+	m.events = events
+}
+
+func (m *IPCClientMockImpl) Events(ctx context.Context, handler EventHandler) error {
+	for _, event := range m.events {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		handler(event)
+	}
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (m *IPCClientMockImpl) marshal(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
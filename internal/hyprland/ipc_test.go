@@ -0,0 +1,262 @@
+package hyprland
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+type fakeHyprlandServer struct {
+	mu       sync.Mutex
+	requests []string
+	replies  map[string]string
+}
+
+func startFakeServer(t *testing.T, dir, socketName string, replies map[string]string) *fakeHyprlandServer {
+	t.Helper()
+
+	listener, err := net.Listen("unix", filepath.Join(dir, socketName))
+	if err != nil {
+		t.Fatalf("Failed to listen on fake socket: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeHyprlandServer{replies: replies}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			buf := make([]byte, 8192)
+			n, _ := conn.Read(buf)
+			request := string(buf[:n])
+
+			server.mu.Lock()
+			server.requests = append(server.requests, request)
+			server.mu.Unlock()
+
+			reply, ok := replies[request]
+			if !ok {
+				reply = "unknown request"
+			}
+
+			conn.Write([]byte(reply))
+			conn.Close()
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeHyprlandServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.requests...)
+}
+
+func TestIPCClient(t *testing.T) {
+	dir := t.TempDir()
+	logger := core.BuildSilentLogger()
+
+	server := startFakeServer(t, dir, controlSocketName, map[string]string{
+		"j/monitors": `[
+			{"id":0,"name":"eDP-1","width":1920,"height":1080,"refreshRate":60.0,"focused":false,"activeWorkspace":{"id":1,"name":"1"}},
+			{"id":1,"name":"HDMI-A-1","width":2560,"height":1440,"refreshRate":144.0,"focused":true,"activeWorkspace":{"id":2,"name":"2"}}
+		]`,
+		"j/workspaces":         `[{"id":1,"name":"1","monitor":"eDP-1","monitorID":0,"windows":3}]`,
+		"j/clients":            `[{"address":"0x1","class":"kitty","title":"shell","workspace":{"id":1,"name":"1"},"pid":42}]`,
+		"j/devices":            `{"mice":[{"address":"0x2","name":"mouse","defaultSpeed":0.0}],"keyboards":[{"name":"kbd","layout":"us","main":true}]}`,
+		"j/version":            `{"branch":"main","commit":"abc","tag":"v0.45.0","flags":[]}`,
+		"reload":               "ok",
+		"dispatch workspace 2": "ok",
+		"dispatch bogus":       "Invalid dispatcher",
+		"[[BATCH]]keyword general:gaps_in 5;reload": "okok",
+	})
+
+	client := NewIPCClientWithDir(logger, dir)
+
+	t.Run("Monitors", func(t *testing.T) {
+		monitors, err := client.Monitors()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(monitors) != 2 {
+			t.Fatalf("Expected 2 monitors, got %d", len(monitors))
+		}
+
+		if monitors[1].Name != "HDMI-A-1" || !monitors[1].Focused || monitors[1].RefreshRate != 144.0 {
+			t.Errorf("Unexpected monitor decoded: %+v", monitors[1])
+		}
+
+		if monitors[0].ActiveWorkspace.ID != 1 {
+			t.Errorf("Expected active workspace 1, got %d", monitors[0].ActiveWorkspace.ID)
+		}
+	})
+
+	t.Run("Workspaces", func(t *testing.T) {
+		workspaces, err := client.Workspaces()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(workspaces) != 1 || workspaces[0].Windows != 3 || workspaces[0].Monitor != "eDP-1" {
+			t.Errorf("Unexpected workspaces decoded: %+v", workspaces)
+		}
+	})
+
+	t.Run("Clients", func(t *testing.T) {
+		clients, err := client.Clients()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(clients) != 1 || clients[0].Class != "kitty" || clients[0].Pid != 42 {
+			t.Errorf("Unexpected clients decoded: %+v", clients)
+		}
+	})
+
+	t.Run("Devices", func(t *testing.T) {
+		devices, err := client.Devices()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(devices.Mice) != 1 || len(devices.Keyboards) != 1 || !devices.Keyboards[0].Main {
+			t.Errorf("Unexpected devices decoded: %+v", devices)
+		}
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		version, err := client.Version()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if version.Tag != "v0.45.0" {
+			t.Errorf("Expected tag v0.45.0, got %s", version.Tag)
+		}
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		if err := client.Reload(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Dispatch", func(t *testing.T) {
+		if err := client.Dispatch("workspace", "2"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		err := client.Dispatch("bogus")
+		if err == nil || !strings.Contains(err.Error(), "Invalid dispatcher") {
+			t.Errorf("Expected dispatcher error, got %v", err)
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		reply, err := client.Batch("keyword general:gaps_in 5", "reload")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if reply != "okok" {
+			t.Errorf("Expected 'okok', got '%s'", reply)
+		}
+
+		if _, err := client.Batch(); err == nil {
+			t.Error("Expected error for empty batch")
+		}
+	})
+
+	t.Run("UnknownRequest", func(t *testing.T) {
+		_, err := client.Request("nonsense")
+		if err == nil || !strings.Contains(err.Error(), "unknown request") {
+			t.Errorf("Expected unknown request error, got %v", err)
+		}
+	})
+
+	t.Run("RequestsReachServer", func(t *testing.T) {
+		requests := server.Requests()
+		if len(requests) == 0 || requests[0] != "j/monitors" {
+			t.Errorf("Expected first request to be j/monitors, got %v", requests)
+		}
+	})
+}
+
+func TestIPCClient_Hyprpaper(t *testing.T) {
+	dir := t.TempDir()
+	logger := core.BuildSilentLogger()
+
+	server := startFakeServer(t, dir, hyprpaperSocketName, map[string]string{
+		"preload /tmp/a.png": "ok",
+		"wallpaper ,/tmp/b":  "wallpaper failed (not preloaded)",
+	})
+
+	client := NewIPCClientWithDir(logger, dir)
+
+	if err := client.Hyprpaper("preload /tmp/a.png"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := client.Hyprpaper("wallpaper ,/tmp/b"); err == nil {
+		t.Error("Expected error for failed hyprpaper reply")
+	}
+
+	if len(server.Requests()) != 2 {
+		t.Errorf("Expected 2 hyprpaper requests, got %d", len(server.Requests()))
+	}
+}
+
+func TestIPCClient_NoSocket(t *testing.T) {
+	client := NewIPCClientWithDir(core.BuildSilentLogger(), t.TempDir())
+
+	if _, err := client.Monitors(); err == nil {
+		t.Error("Expected error when socket does not exist")
+	}
+}
+
+func TestInstanceDir(t *testing.T) {
+	t.Run("WithoutSignature", func(t *testing.T) {
+		t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+
+		if _, err := InstanceDir(); err != ErrNoInstance {
+			t.Errorf("Expected ErrNoInstance, got %v", err)
+		}
+
+		client := NewIPCClient(core.BuildSilentLogger())
+		if _, err := client.Version(); err != ErrNoInstance {
+			t.Errorf("Expected ErrNoInstance from client, got %v", err)
+		}
+	})
+
+	t.Run("RuntimeDir", func(t *testing.T) {
+		runtimeDir := t.TempDir()
+		expected := filepath.Join(runtimeDir, "hypr", "sig")
+		if err := os.MkdirAll(expected, 0755); err != nil {
+			t.Fatalf("Failed to create runtime dir: %v", err)
+		}
+
+		t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "sig")
+		t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
+		dir, err := InstanceDir()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if dir != expected {
+			t.Errorf("Expected %s, got %s", expected, dir)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package hyprland
+
+// WorkspaceRef is the short workspace reference embedded in monitors and clients.
+type WorkspaceRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Monitor mirrors the objects returned by `j/monitors`.
+type Monitor struct {
+	ID               int          `json:"id"`
+	Name             string       `json:"name"`
+	Description      string       `json:"description"`
+	Make             string       `json:"make"`
+	Model            string       `json:"model"`
+	Serial           string       `json:"serial"`
+	Width            int          `json:"width"`
+	Height           int          `json:"height"`
+	RefreshRate      float64      `json:"refreshRate"`
+	X                int          `json:"x"`
+	Y                int          `json:"y"`
+	ActiveWorkspace  WorkspaceRef `json:"activeWorkspace"`
+	SpecialWorkspace WorkspaceRef `json:"specialWorkspace"`
+	Reserved         []int        `json:"reserved"`
+	Scale            float64      `json:"scale"`
+	Transform        int          `json:"transform"`
+	Focused          bool         `json:"focused"`
+	DpmsStatus       bool         `json:"dpmsStatus"`
+	Vrr              bool         `json:"vrr"`
+	Disabled         bool         `json:"disabled"`
+}
+
+// Workspace mirrors the objects returned by `j/workspaces`.
+type Workspace struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	Monitor         string `json:"monitor"`
+	MonitorID       int    `json:"monitorID"`
+	Windows         int    `json:"windows"`
+	HasFullscreen   bool   `json:"hasfullscreen"`
+	LastWindow      string `json:"lastwindow"`
+	LastWindowTitle string `json:"lastwindowtitle"`
+}
+
+// Client mirrors the window objects returned by `j/clients`.
+type Client struct {
+	Address        string       `json:"address"`
+	Mapped         bool         `json:"mapped"`
+	Hidden         bool         `json:"hidden"`
+	At             [2]int       `json:"at"`
+	Size           [2]int       `json:"size"`
+	Workspace      WorkspaceRef `json:"workspace"`
+	Floating       bool         `json:"floating"`
+	Monitor        int          `json:"monitor"`
+	Class          string       `json:"class"`
+	Title          string       `json:"title"`
+	InitialClass   string       `json:"initialClass"`
+	InitialTitle   string       `json:"initialTitle"`
+	Pid            int          `json:"pid"`
+	Xwayland       bool         `json:"xwayland"`
+	Pinned         bool         `json:"pinned"`
+	FocusHistoryID int          `json:"focusHistoryID"`
+}
+
+// Mouse is a pointer device reported by `j/devices`.
+type Mouse struct {
+	Address      string  `json:"address"`
+	Name         string  `json:"name"`
+	DefaultSpeed float64 `json:"defaultSpeed"`
+}
+
+// Keyboard is a keyboard device reported by `j/devices`.
+type Keyboard struct {
+	Address      string `json:"address"`
+	Name         string `json:"name"`
+	Rules        string `json:"rules"`
+	Model        string `json:"model"`
+	Layout       string `json:"layout"`
+	Variant      string `json:"variant"`
+	Options      string `json:"options"`
+	ActiveKeymap string `json:"active_keymap"`
+	Main         bool   `json:"main"`
+}
+
+// Device is the generic shape used for tablets, touch devices and switches.
+type Device struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+// Devices mirrors the object returned by `j/devices`.
+type Devices struct {
+	Mice      []Mouse    `json:"mice"`
+	Keyboards []Keyboard `json:"keyboards"`
+	Tablets   []Device   `json:"tablets"`
+	Touch     []Device   `json:"touch"`
+	Switches  []Device   `json:"switches"`
+}
+
+// Version mirrors the object returned by `j/version`.
+type Version struct {
+	Branch        string   `json:"branch"`
+	Commit        string   `json:"commit"`
+	Dirty         bool     `json:"dirty"`
+	CommitMessage string   `json:"commit_message"`
+	CommitDate    string   `json:"commit_date"`
+	Tag           string   `json:"tag"`
+	Flags         []string `json:"flags"`
+}
@@ -0,0 +1,94 @@
+package hyprlang
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+// File is a document loaded from disk.
+type File struct {
+	*Document
+	Path string
+}
+
+// LoadFile parses the configuration at path.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &File{Document: document, Path: path}, nil
+}
+
+// Save atomically writes the document back to its file.
+func (f *File) Save() error {
+	return configfile.WriteAtomic(f.Path, f.Bytes(), 0644)
+}
+
+// LoadWithSources loads path and, recursively, every file it sources, in the order Hyprland
+// reads them. Source paths may use '~' and glob patterns and are relative to the sourcing
+// file; each file is loaded once.
+func LoadWithSources(path string) ([]*File, error) {
+	var files []*File
+	seen := map[string]bool{}
+
+	var load func(path string) error
+	load = func(path string) error {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+
+		if seen[absolute] {
+			return nil
+		}
+		seen[absolute] = true
+
+		file, err := LoadFile(absolute)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+
+		for _, source := range file.Sources() {
+			pattern := core.ResolvePath(os.ExpandEnv(file.Expand(source)))
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(filepath.Dir(absolute), pattern)
+			}
+
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid source '%s' in %s: %w", source, absolute, err)
+			}
+
+			if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+				return fmt.Errorf("source '%s' in %s not found", source, absolute)
+			}
+
+			for _, match := range matches {
+				if err := load(match); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := load(path); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -0,0 +1,73 @@
+package hyprlang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func TestLoadWithSources(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "hyprlock.conf")
+
+	writeConfig(t, main, "$conf = "+dir+"/conf.d\nsource = colors.conf\nsource = $conf/*.conf\nlabel {\n  text = main\n}\n")
+	writeConfig(t, filepath.Join(dir, "colors.conf"), "$accent = rgb(ffffff)\nsource = hyprlock.conf\n")
+	writeConfig(t, filepath.Join(dir, "conf.d", "a.conf"), "label {\n  text = a\n}\n")
+	writeConfig(t, filepath.Join(dir, "conf.d", "b.conf"), "label {\n  text = b\n}\n")
+
+	files, err := LoadWithSources(main)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file.Path))
+	}
+
+	if strings.Join(names, ",") != "hyprlock.conf,colors.conf,a.conf,b.conf" {
+		t.Errorf("Expected each file once in source order, got %v", names)
+	}
+}
+
+func TestLoadWithSources_MissingSource(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "hyprlock.conf")
+	writeConfig(t, main, "source = missing.conf\n")
+
+	if _, err := LoadWithSources(main); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a missing source error, got %v", err)
+	}
+}
+
+func TestFile_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hyprlock.conf")
+	writeConfig(t, path, "label {\n    text = old # keep\n}\n")
+
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	file.Blocks("label")[0].Get("text").SetValue("new")
+	if err := file.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "label {\n    text = new # keep\n}\n" {
+		t.Errorf("Unexpected content:\n%s", data)
+	}
+}
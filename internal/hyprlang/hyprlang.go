@@ -0,0 +1,311 @@
+// Package hyprlang parses and writes the configuration language shared by Hyprland,
+// hyprlock and hyprpaper. Documents keep every original line, so serializing an unmodified
+// document reproduces the input byte for byte and edits only touch the changed lines.
+package hyprlang
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type NodeKind int
+
+const (
+	KindBlank NodeKind = iota
+	KindComment
+	KindAssignment
+	KindBlock
+)
+
+var assignmentPattern = regexp.MustCompile(`^(\s*)([^=\s{}#][^={}#]*?)(\s*=[ \t]*)(.*)$`)
+
+// Node is a line of the document: a blank line, a comment, a `key = value` assignment or a
+// `name { ... }` block with its children.
+type Node struct {
+	Kind     NodeKind
+	Line     int
+	Key      string
+	Children []*Node
+
+	raw      string
+	closeRaw string
+	prefix   string
+	value    string
+	suffix   string
+}
+
+// Value returns the unescaped value of an assignment, without its inline comment.
+func (n *Node) Value() string {
+	return strings.ReplaceAll(n.value, "##", "#")
+}
+
+// RawValue returns the value as written in the file.
+func (n *Node) RawValue() string {
+	return n.value
+}
+
+// SetValue replaces the value of an assignment, keeping its key, spacing and inline comment.
+func (n *Node) SetValue(value string) {
+	n.value = strings.ReplaceAll(value, "#", "##")
+	n.raw = n.prefix + n.value + n.suffix
+}
+
+// Comment returns the text of a comment line, or the inline comment of an assignment or
+// block header, without the leading '#'.
+func (n *Node) Comment() string {
+	var comment string
+
+	switch n.Kind {
+	case KindComment:
+		comment = strings.TrimSpace(n.raw)
+	case KindAssignment:
+		comment = strings.TrimSpace(n.suffix)
+	case KindBlock:
+		_, comment = splitComment(n.raw)
+		comment = strings.TrimSpace(comment)
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(comment, "#"))
+}
+
+// IsVariable reports whether the assignment defines a `$variable`.
+func (n *Node) IsVariable() bool {
+	return n.Kind == KindAssignment && strings.HasPrefix(n.Key, "$")
+}
+
+// Get returns the first assignment named key among the children of a block.
+func (n *Node) Get(key string) *Node {
+	for _, child := range n.Children {
+		if child.Kind == KindAssignment && child.Key == key {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// Document is a parsed configuration file.
+type Document struct {
+	Nodes []*Node
+}
+
+// Parse reads a configuration, failing on unbalanced braces or lines it cannot classify.
+func Parse(data []byte) (*Document, error) {
+	root := &Node{Kind: KindBlock}
+	stack := []*Node{root}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		number := i + 1
+		parent := stack[len(stack)-1]
+		content, _ := splitComment(line)
+		trimmed := strings.TrimSpace(content)
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			parent.Children = append(parent.Children, &Node{Kind: KindBlank, Line: number, raw: line})
+		case strings.HasPrefix(strings.TrimSpace(line), "#"):
+			parent.Children = append(parent.Children, &Node{Kind: KindComment, Line: number, raw: line})
+		case trimmed == "}":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("line %d: unexpected '}'", number)
+			}
+			parent.closeRaw = line
+			stack = stack[:len(stack)-1]
+		case strings.HasSuffix(trimmed, "{"):
+			name := strings.TrimSpace(strings.TrimSuffix(trimmed, "{"))
+			if name == "" || strings.ContainsAny(name, "={}") {
+				return nil, fmt.Errorf("line %d: invalid block '%s'", number, trimmed)
+			}
+			block := &Node{Kind: KindBlock, Line: number, Key: name, raw: line}
+			parent.Children = append(parent.Children, block)
+			stack = append(stack, block)
+		default:
+			node, err := parseAssignment(line, number)
+			if err != nil {
+				return nil, err
+			}
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	if len(stack) > 1 {
+		block := stack[len(stack)-1]
+		return nil, fmt.Errorf("line %d: block '%s' is never closed", block.Line, block.Key)
+	}
+
+	return &Document{Nodes: root.Children}, nil
+}
+
+func parseAssignment(line string, number int) (*Node, error) {
+	content, comment := splitComment(line)
+
+	// keep the whitespace before an inline comment with the comment
+	trimmed := strings.TrimRight(content, " \t")
+	comment = content[len(trimmed):] + comment
+
+	match := assignmentPattern.FindStringSubmatch(trimmed)
+	if match == nil {
+		return nil, fmt.Errorf("line %d: expected 'key = value', got '%s'", number, strings.TrimSpace(line))
+	}
+
+	return &Node{
+		Kind:   KindAssignment,
+		Line:   number,
+		Key:    strings.TrimSpace(match[2]),
+		raw:    line,
+		prefix: match[1] + match[2] + match[3],
+		value:  match[4],
+		suffix: comment,
+	}, nil
+}
+
+// splitComment splits line at the first '#' that is not escaped as '##'.
+func splitComment(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		if line[i] != '#' {
+			continue
+		}
+		if i+1 < len(line) && line[i+1] == '#' {
+			i++
+			continue
+		}
+		return line[:i], line[i:]
+	}
+
+	return line, ""
+}
+
+// Bytes serializes the document.
+func (d *Document) Bytes() []byte {
+	var lines []string
+	appendLines(&lines, d.Nodes)
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func appendLines(lines *[]string, nodes []*Node) {
+	for _, node := range nodes {
+		*lines = append(*lines, node.raw)
+
+		if node.Kind == KindBlock {
+			appendLines(lines, node.Children)
+			*lines = append(*lines, node.closeRaw)
+		}
+	}
+}
+
+// Walk visits every node depth first with the blocks enclosing it. Returning false skips
+// the children of a block.
+func (d *Document) Walk(visit func(node *Node, parents []*Node) bool) {
+	walk(d.Nodes, nil, visit)
+}
+
+func walk(nodes []*Node, parents []*Node, visit func(node *Node, parents []*Node) bool) {
+	for _, node := range nodes {
+		if visit(node, parents) && node.Kind == KindBlock {
+			walk(node.Children, append(parents[:len(parents):len(parents)], node), visit)
+		}
+	}
+}
+
+// Blocks returns every block named name, at any depth, in file order.
+func (d *Document) Blocks(name string) []*Node {
+	var blocks []*Node
+
+	d.Walk(func(node *Node, _ []*Node) bool {
+		if node.Kind == KindBlock && node.Key == name {
+			blocks = append(blocks, node)
+		}
+		return true
+	})
+
+	return blocks
+}
+
+// Variables returns the top-level `$name = value` definitions, keyed without the '$'.
+func (d *Document) Variables() map[string]string {
+	variables := map[string]string{}
+
+	for _, node := range d.Nodes {
+		if node.IsVariable() {
+			variables[strings.TrimPrefix(node.Key, "$")] = node.Value()
+		}
+	}
+
+	return variables
+}
+
+// Expand replaces the defined `$variables` in value. Unknown variables are left as is,
+// since hyprlock resolves its own ($TIME, $USER, ...).
+func (d *Document) Expand(value string) string {
+	variables := d.Variables()
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+
+	// longest first, so $color10 is not expanded as $color1 followed by 0
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	for _, name := range names {
+		value = strings.ReplaceAll(value, "$"+name, variables[name])
+	}
+
+	return value
+}
+
+// Sources returns the values of the top-level `source = path` lines.
+func (d *Document) Sources() []string {
+	var sources []string
+
+	for _, node := range d.Nodes {
+		if node.Kind == KindAssignment && node.Key == "source" {
+			sources = append(sources, node.Value())
+		}
+	}
+
+	return sources
+}
+
+// BlockComments returns the comments attached to block: the comment lines right above it,
+// the inline comment of its header and the comment lines directly inside it.
+func (d *Document) BlockComments(block *Node) []string {
+	var comments []string
+
+	siblings := d.siblingsOf(block)
+	for i := slices.Index(siblings, block) - 1; i >= 0 && siblings[i].Kind == KindComment; i-- {
+		comments = append(comments, siblings[i].Comment())
+	}
+
+	if comment := block.Comment(); comment != "" {
+		comments = append(comments, comment)
+	}
+
+	for _, child := range block.Children {
+		if child.Kind == KindComment {
+			comments = append(comments, child.Comment())
+		}
+	}
+
+	return comments
+}
+
+func (d *Document) siblingsOf(target *Node) []*Node {
+	if slices.Contains(d.Nodes, target) {
+		return d.Nodes
+	}
+
+	var siblings []*Node
+	d.Walk(func(node *Node, _ []*Node) bool {
+		if node.Kind == KindBlock && slices.Contains(node.Children, target) {
+			siblings = node.Children
+		}
+		return siblings == nil
+	})
+
+	return siblings
+}
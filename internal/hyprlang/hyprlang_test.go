@@ -0,0 +1,151 @@
+package hyprlang
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleConfig = `# hyprlock
+$font = JetBrains Mono
+$accent = rgb(bd93f9)
+source = ~/.config/hypr/colors.conf
+
+general {
+    hide_cursor = true   # inline comment
+}
+
+input-field {
+    monitor =
+    outer_color = $accent
+}
+
+label { # ebenezer:clock
+    text = cmd[update:1000] echo "$TIME"
+    font_family = $font
+}
+
+# ebenezer:message
+label {
+    text = Hello ## not a comment
+    shadow {
+        passes = 2
+    }
+}
+`
+
+func TestParse_RoundTrip(t *testing.T) {
+	inputs := map[string]string{
+		"Sample":             sampleConfig,
+		"NoTrailingNewline":  "general {\n  a = b\n}",
+		"CRLF":               "a = b\r\nblock {\r\n  c = d\r\n}\r\n",
+		"Empty":              "",
+		"CommentsAndBlanks":  "\n\n# only comments\n   ## escaped comment\n\t\n",
+		"BraceWithoutSpace":  "label{\n text=x\n}\n",
+		"ClosingWithComment": "label {\n text = x\n} # end\n",
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			document, err := Parse([]byte(input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if output := string(document.Bytes()); output != input {
+				t.Errorf("Expected a byte for byte round trip, got:\n%q\nwant:\n%q", output, input)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		errorMsg string
+	}{
+		{"UnclosedBlock", "label {\n text = x\n", "line 1: block 'label' is never closed"},
+		{"UnexpectedClose", "a = b\n}\n", "line 2: unexpected '}'"},
+		{"NotAnAssignment", "general {\n just words\n}", "line 2: expected 'key = value'"},
+		{"InvalidBlock", "a = {\n}", "line 1: invalid block"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing '%s', got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestDocument_Structure(t *testing.T) {
+	document, err := Parse([]byte(sampleConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	labels := document.Blocks("label")
+	if len(labels) != 2 {
+		t.Fatalf("Expected 2 labels, got %d", len(labels))
+	}
+
+	if text := labels[1].Get("text").Value(); text != "Hello # not a comment" {
+		t.Errorf("Expected escaped '#' to be part of the value, got '%s'", text)
+	}
+
+	if shadows := document.Blocks("shadow"); len(shadows) != 1 || shadows[0].Get("passes").Value() != "2" {
+		t.Errorf("Expected the nested shadow block, got %+v", shadows)
+	}
+
+	general := document.Blocks("general")[0].Get("hide_cursor")
+	if general.Value() != "true" || general.Comment() != "inline comment" {
+		t.Errorf("Expected value 'true' with an inline comment, got '%s' / '%s'", general.Value(), general.Comment())
+	}
+
+	variables := document.Variables()
+	if variables["font"] != "JetBrains Mono" || variables["accent"] != "rgb(bd93f9)" {
+		t.Errorf("Unexpected variables: %v", variables)
+	}
+
+	if expanded := document.Expand("$accent $TIME"); expanded != "rgb(bd93f9) $TIME" {
+		t.Errorf("Expected only defined variables to expand, got '%s'", expanded)
+	}
+
+	if sources := document.Sources(); len(sources) != 1 || sources[0] != "~/.config/hypr/colors.conf" {
+		t.Errorf("Unexpected sources: %v", sources)
+	}
+}
+
+func TestDocument_BlockComments(t *testing.T) {
+	document, _ := Parse([]byte(sampleConfig))
+	labels := document.Blocks("label")
+
+	if comments := document.BlockComments(labels[0]); len(comments) != 1 || comments[0] != "ebenezer:clock" {
+		t.Errorf("Expected the header comment, got %v", comments)
+	}
+
+	if comments := document.BlockComments(labels[1]); len(comments) != 1 || comments[0] != "ebenezer:message" {
+		t.Errorf("Expected the comment above the block, got %v", comments)
+	}
+
+	shadow := document.Blocks("shadow")[0]
+	if comments := document.BlockComments(shadow); len(comments) != 0 {
+		t.Errorf("Expected no comments for the nested block, got %v", comments)
+	}
+}
+
+func TestNode_SetValue(t *testing.T) {
+	document, _ := Parse([]byte(sampleConfig))
+
+	document.Blocks("general")[0].Get("hide_cursor").SetValue("false")
+	document.Blocks("label")[1].Get("text").SetValue("Joke #1")
+
+	expected := strings.Replace(sampleConfig, "hide_cursor = true   # inline comment", "hide_cursor = false   # inline comment", 1)
+	expected = strings.Replace(expected, "text = Hello ## not a comment", "text = Joke ##1", 1)
+
+	if output := string(document.Bytes()); output != expected {
+		t.Errorf("Expected only the edited lines to change, got:\n%s", output)
+	}
+}
@@ -0,0 +1,338 @@
+package jokes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+const (
+	// poolLowWater is the number of unseen entries left that triggers a background refill.
+	poolLowWater = 3
+	// poolLimit caps the entries kept per provider; seen entries are dropped first.
+	poolLimit = 200
+)
+
+// refills tracks the background refills of every store, so short-lived commands can let
+// them finish before exiting.
+var refills sync.WaitGroup
+
+// PoolProvider is implemented by providers that fetch entries in batches, which are cached
+// per provider and handed out without repeating until the pool is exhausted.
+type PoolProvider interface {
+	FetchPool(ctx context.Context) ([]string, error)
+	CacheTTL() time.Duration
+}
+
+// Pool is the cached entries of a provider. Shown holds the entries already handed out.
+// Provider is the cache key; Source and Options are what the pool is fetched with.
+type Pool struct {
+	Provider  string    `json:"provider"`
+	Source    string    `json:"source,omitempty"`
+	Options   *Options  `json:"options,omitempty"`
+	Entries   []string  `json:"entries"`
+	Shown     []string  `json:"shown"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// PoolSource fetches the entries of a pool. Provider, as given to BuildJokeFetcher, and Options
+// are saved with the pool, so its fetcher can be built again.
+type PoolSource struct {
+	Provider string
+	Options  Options
+	Fetch    func(ctx context.Context) ([]string, error)
+}
+
+// Remaining returns the entries not shown yet.
+func (p *Pool) Remaining() []string {
+	return slices.DeleteFunc(slices.Clone(p.Entries), func(entry string) bool {
+		return slices.Contains(p.Shown, entry)
+	})
+}
+
+// Expired reports whether the pool was fetched longer than ttl ago.
+func (p *Pool) Expired(ttl time.Duration) bool {
+	return time.Since(p.FetchedAt) >= ttl
+}
+
+// merge adds the new entries, keeping what was already shown, and trims the pool to poolLimit.
+func (p *Pool) merge(entries []string) {
+	for _, entry := range entries {
+		if !slices.Contains(p.Entries, entry) {
+			p.Entries = append(p.Entries, entry)
+		}
+	}
+
+	for i := 0; len(p.Entries) > poolLimit && i < len(p.Entries); {
+		if slices.Contains(p.Shown, p.Entries[i]) {
+			p.Entries = slices.Delete(p.Entries, i, i+1)
+			continue
+		}
+		i++
+	}
+
+	p.Shown = slices.DeleteFunc(p.Shown, func(entry string) bool {
+		return !slices.Contains(p.Entries, entry)
+	})
+
+	p.FetchedAt = time.Now()
+}
+
+// CacheStore keeps one pool file per provider.
+type CacheStore struct {
+	dir     string
+	logger  core.Logger
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+// DefaultCacheDir returns the jokes cache under the XDG cache directory.
+func DefaultCacheDir() string {
+	return filepath.Join(core.CacheDir(), "jokes")
+}
+
+// NewCacheStore returns a store under dir, or under DefaultCacheDir when dir is empty.
+func NewCacheStore(logger core.Logger, dir string) *CacheStore {
+	return &CacheStore{dir: dir, logger: logger, pending: map[string]bool{}}
+}
+
+// Dir returns the directory holding the pools.
+func (c *CacheStore) Dir() string {
+	if c.dir == "" {
+		return DefaultCacheDir()
+	}
+
+	return c.dir
+}
+
+func (c *CacheStore) path(provider string) string {
+	return filepath.Join(c.Dir(), url.PathEscape(provider)+".json")
+}
+
+// Load reads the pool of provider, returning an empty pool when nothing is cached.
+func (c *CacheStore) Load(provider string) (*Pool, error) {
+	pool := &Pool{Provider: provider}
+
+	data, err := os.ReadFile(c.path(provider))
+	if errors.Is(err, os.ErrNotExist) {
+		return pool, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache of %s: %w", provider, err)
+	}
+
+	if err := json.Unmarshal(data, pool); err != nil {
+		return nil, fmt.Errorf("failed to decode cache of %s: %w", provider, err)
+	}
+
+	return pool, nil
+}
+
+// Save writes pool to its file.
+func (c *CacheStore) Save(pool *Pool) error {
+	data, err := json.MarshalIndent(pool, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := configfile.WriteAtomic(c.path(pool.Provider), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache of %s: %w", pool.Provider, err)
+	}
+
+	return nil
+}
+
+// List returns every cached pool, sorted by provider.
+func (c *CacheStore) List() ([]*Pool, error) {
+	files, err := filepath.Glob(filepath.Join(c.Dir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []*Pool
+	for _, file := range files {
+		provider, err := url.PathUnescape(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			continue
+		}
+
+		pool, err := c.Load(provider)
+		if err != nil {
+			c.logger.Warning("Skipping unreadable cache", "file", file, "error", err)
+			continue
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+// Clear removes the pools of providers, or every pool when none is given.
+func (c *CacheStore) Clear(providers ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(providers) == 0 {
+		if err := os.RemoveAll(c.Dir()); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		return nil
+	}
+
+	for _, provider := range providers {
+		if err := os.Remove(c.path(provider)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear cache of %s: %w", provider, err)
+		}
+	}
+
+	return nil
+}
+
+// Prune removes the pools fetched longer than maxAge ago and returns their providers.
+func (c *CacheStore) Prune(maxAge time.Duration) ([]string, error) {
+	pools, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []string
+	for _, pool := range pools {
+		if pool.Expired(maxAge) {
+			providers = append(providers, pool.Provider)
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, nil
+	}
+
+	return providers, c.Clear(providers...)
+}
+
+// Refresh fetches new entries for provider and merges them into its pool.
+func (c *CacheStore) Refresh(ctx context.Context, provider string, source PoolSource) (*Pool, error) {
+	entries, err := source.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("provider %s returned no entries", provider)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pool, err := c.Load(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Provider != "" {
+		pool.Source = source.Provider
+		pool.Options = &source.Options
+	}
+
+	pool.merge(entries)
+	return pool, c.Save(pool)
+}
+
+// Next hands out an entry of provider that was not shown yet. An expired or exhausted pool is
+// refreshed first; when few entries are left, a refill starts in the background.
+func (c *CacheStore) Next(ctx context.Context, provider string, ttl time.Duration, source PoolSource) (string, error) {
+	c.mu.Lock()
+	pool, err := c.Load(provider)
+	c.mu.Unlock()
+
+	if err != nil {
+		c.logger.Warning("Ignoring broken cache", "provider", provider, "error", err)
+		pool = &Pool{Provider: provider}
+	}
+
+	fetched := false
+	if len(pool.Remaining()) == 0 || pool.Expired(ttl) {
+		refreshed, err := c.Refresh(ctx, provider, source)
+		switch {
+		case err == nil:
+			pool, fetched = refreshed, true
+		case len(pool.Entries) == 0:
+			return "", err
+		default:
+			c.logger.Warning("Refresh failed, reusing cached entries", "provider", provider, "error", err)
+		}
+	} else {
+		c.logger.Debug("💾 Using cached jokes", "provider", provider)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// a background refill may have saved the pool since it was read
+	if latest, err := c.Load(provider); err == nil && len(latest.Entries) > 0 {
+		pool = latest
+	}
+
+	remaining := pool.Remaining()
+	if len(remaining) == 0 {
+		// every entry was shown and nothing new came in: start over
+		pool.Shown = nil
+		remaining = pool.Entries
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	entry := remaining[r.Intn(len(remaining))]
+
+	pool.Shown = append(pool.Shown, entry)
+	if err := c.Save(pool); err != nil {
+		c.logger.Warning("Warning: failed to save cache", "error", err)
+	}
+
+	if !fetched && len(remaining)-1 < poolLowWater {
+		c.refill(provider, source)
+	}
+
+	return entry, nil
+}
+
+// refill refreshes provider in the background, once at a time. The refill outlives the run
+// that started it, so it is not cancelled with it; the providers time their requests out.
+func (c *CacheStore) refill(provider string, source PoolSource) {
+	if c.pending[provider] {
+		return
+	}
+
+	c.pending[provider] = true
+	refills.Add(1)
+
+	go func() {
+		defer refills.Done()
+
+		if _, err := c.Refresh(context.Background(), provider, source); err != nil {
+			c.logger.Warning("Background refill failed", "provider", provider, "error", err)
+		} else {
+			c.logger.Debug("Cache refilled", "provider", provider)
+		}
+
+		c.mu.Lock()
+		delete(c.pending, provider)
+		c.mu.Unlock()
+	}()
+}
+
+// WaitRefills blocks until the background refills are done.
+func WaitRefills() {
+	refills.Wait()
+}
@@ -0,0 +1,179 @@
+package jokes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+// batches returns a fetch function handing out batches of size new entries per call.
+func batches(size int, calls *atomic.Int32) func(context.Context) ([]string, error) {
+	return func(context.Context) ([]string, error) {
+		call := calls.Add(1)
+
+		var entries []string
+		for i := range size {
+			entries = append(entries, fmt.Sprintf("joke %d.%d", call, i))
+		}
+
+		return entries, nil
+	}
+}
+
+func TestCacheStore_Next(t *testing.T) {
+	store := NewCacheStore(core.BuildSilentLogger(), t.TempDir())
+
+	var calls atomic.Int32
+	fetch := batches(10, &calls)
+
+	seen := map[string]bool{}
+	for range 10 {
+		entry, err := store.Next(t.Context(), "test", time.Hour, PoolSource{Fetch: fetch})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if seen[entry] {
+			t.Errorf("Entry '%s' was handed out twice", entry)
+		}
+		seen[entry] = true
+	}
+	WaitRefills()
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected one fetch and one background refill, got %d fetches", calls.Load())
+	}
+
+	pool, err := store.Load("test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(pool.Entries) != 20 || len(pool.Shown) != 10 {
+		t.Errorf("Expected 20 entries with 10 shown, got %d and %d", len(pool.Entries), len(pool.Shown))
+	}
+
+	if remaining := pool.Remaining(); slices.ContainsFunc(remaining, func(entry string) bool { return seen[entry] }) {
+		t.Errorf("Expected remaining entries to exclude the shown ones, got %v", remaining)
+	}
+}
+
+func TestCacheStore_NextExpired(t *testing.T) {
+	store := NewCacheStore(core.BuildSilentLogger(), t.TempDir())
+	store.Save(&Pool{Provider: "test", Entries: []string{"old 1", "old 2", "old 3", "old 4", "old 5"}, FetchedAt: time.Now().Add(-2 * time.Hour)})
+
+	var calls atomic.Int32
+	if _, err := store.Next(t.Context(), "test", time.Hour, PoolSource{Fetch: batches(1, &calls)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected the expired pool to be refreshed, got %d fetches", calls.Load())
+	}
+
+	pool, _ := store.Load("test")
+	if !slices.Contains(pool.Entries, "joke 1.0") || !slices.Contains(pool.Entries, "old 1") {
+		t.Errorf("Expected new entries merged with the old ones, got %v", pool.Entries)
+	}
+}
+
+func TestCacheStore_NextFetchFails(t *testing.T) {
+	store := NewCacheStore(core.BuildSilentLogger(), t.TempDir())
+	failing := func(context.Context) ([]string, error) { return nil, errors.New("offline") }
+
+	if _, err := store.Next(t.Context(), "test", time.Hour, PoolSource{Fetch: failing}); err == nil {
+		t.Error("Expected error without cached entries")
+	}
+
+	store.Save(&Pool{Provider: "test", Entries: []string{"only"}, Shown: []string{"only"}, FetchedAt: time.Now()})
+
+	entry, err := store.Next(t.Context(), "test", time.Hour, PoolSource{Fetch: failing})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	WaitRefills()
+
+	if entry != "only" {
+		t.Errorf("Expected the cached entry to be reused, got '%s'", entry)
+	}
+}
+
+func TestCacheStore_ListAndClear(t *testing.T) {
+	store := NewCacheStore(core.BuildSilentLogger(), t.TempDir())
+
+	for _, provider := range []string{"reddit", "my/api"} {
+		if err := store.Save(&Pool{Provider: provider, Entries: []string{"a"}}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	pools, err := store.List()
+	if err != nil || len(pools) != 2 {
+		t.Fatalf("Expected 2 pools, got %d (%v)", len(pools), err)
+	}
+
+	if err := store.Clear("my/api"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if pools, _ := store.List(); len(pools) != 1 || pools[0].Provider != "reddit" {
+		t.Errorf("Expected only the reddit pool, got %v", pools)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if pools, _ := store.List(); len(pools) != 0 {
+		t.Errorf("Expected no pools after clearing, got %d", len(pools))
+	}
+}
+
+func TestPool_Merge(t *testing.T) {
+	pool := &Pool{}
+	for i := range poolLimit {
+		pool.Entries = append(pool.Entries, fmt.Sprint(i))
+	}
+	pool.Shown = []string{"0", "1", "2"}
+
+	pool.merge([]string{"new 1", "new 2", "5"})
+
+	if len(pool.Entries) != poolLimit {
+		t.Errorf("Expected %d entries, got %d", poolLimit, len(pool.Entries))
+	}
+
+	if slices.Contains(pool.Entries, "0") || slices.Contains(pool.Entries, "1") || !slices.Contains(pool.Entries, "2") {
+		t.Errorf("Expected the oldest shown entries to be dropped first, got %v", pool.Entries[:3])
+	}
+
+	if !slices.Equal(pool.Shown, []string{"2"}) {
+		t.Errorf("Expected shown to follow the dropped entries, got %v", pool.Shown)
+	}
+}
+
+func TestCacheStore_Prune(t *testing.T) {
+	store := NewCacheStore(core.BuildSilentLogger(), t.TempDir())
+
+	store.Save(&Pool{Provider: "reddit", Entries: []string{"old"}, FetchedAt: time.Now().Add(-48 * time.Hour)})
+	store.Save(&Pool{Provider: "fortune", Entries: []string{"new"}, FetchedAt: time.Now()})
+
+	providers, err := store.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !slices.Equal(providers, []string{"reddit"}) {
+		t.Errorf("Expected the reddit pool to be pruned, got %v", providers)
+	}
+
+	pools, _ := store.List()
+	if len(pools) != 1 || pools[0].Provider != "fortune" {
+		t.Errorf("Expected only the fortune pool to be left, got %v", pools)
+	}
+}
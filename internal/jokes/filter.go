@@ -0,0 +1,49 @@
+package jokes
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Options tunes what the providers fetch and which jokes reach the lock screen. The tags
+// let commands embed it as flags.
+type Options struct {
+	Blocklist []string `json:"blocklist,omitempty" help:"Skip jokes containing any of these words (case-insensitive)"`
+	MaxLength int      `json:"max_length,omitempty" help:"Skip jokes longer than this many characters (0 for no limit)" default:"0"`
+	AllowNsfw bool     `json:"allow_nsfw,omitempty" help:"Keep reddit posts marked as NSFW"`
+	Subreddit []string `json:"subreddit,omitempty" help:"Subreddits read by the reddit provider (default: ProgrammerDadJokes)"`
+	Flair     []string `json:"flair,omitempty" help:"Only keep reddit posts with one of these flairs"`
+}
+
+// Allows reports whether joke passes the blocklist and the length limit.
+func (o Options) Allows(joke string) bool {
+	if o.MaxLength > 0 && utf8.RuneCountInString(joke) > o.MaxLength {
+		return false
+	}
+
+	for _, word := range o.Blocklist {
+		if word = strings.TrimSpace(word); word == "" {
+			continue
+		}
+
+		pattern := regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(word) + `($|\W)`)
+		if pattern.MatchString(joke) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// allowsFlair reports whether a reddit post with flair is kept.
+func (o Options) allowsFlair(flair string) bool {
+	if len(o.Flair) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(o.Flair, func(allowed string) bool {
+		return strings.EqualFold(strings.TrimSpace(allowed), strings.TrimSpace(flair))
+	})
+}
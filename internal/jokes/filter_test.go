@@ -0,0 +1,48 @@
+package jokes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func TestOptions_Allows(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		joke     string
+		expected bool
+	}{
+		{"NoFilters", Options{}, "anything goes", true},
+		{"BlockedWord", Options{Blocklist: []string{"darn"}}, "Well, DARN it!", false},
+		{"BlockedWordInsideAnother", Options{Blocklist: []string{"ass"}}, "Pass the class", true},
+		{"BlockedPhrase", Options{Blocklist: []string{"bad joke"}}, "This is a bad joke.", false},
+		{"TooLong", Options{MaxLength: 5}, "too long", false},
+		{"LengthInRunes", Options{MaxLength: 5}, "olá🙂!", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.options.Allows(tt.joke); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestJokeFetcher_Filters(t *testing.T) {
+	defer delete(PROVIDERS, "mock")
+
+	PROVIDERS["mock"] = &mockJokeProvider{joke: "a joke with a bad word"}
+
+	fetcher := BuildJokeFetcherWithOptions(core.BuildSilentLogger(), "mock", false, Options{Blocklist: []string{"bad"}})
+	if _, err := fetcher.FetchJokes(t.Context()); err == nil || !strings.Contains(err.Error(), "filters") {
+		t.Errorf("Expected the filters to reject every joke, got %v", err)
+	}
+
+	fetcher = BuildJokeFetcherWithOptions(core.BuildSilentLogger(), "mock", false, Options{Blocklist: []string{"good"}})
+	if joke, err := fetcher.FetchJokes(t.Context()); err != nil || joke != "a joke with a bad word" {
+		t.Errorf("Expected the joke to pass, got '%s' (%v)", joke, err)
+	}
+}
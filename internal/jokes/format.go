@@ -0,0 +1,188 @@
+package jokes
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/sanitize"
+)
+
+// MessageData is what a message format template can use, e.g.
+// "{{.Greeting}}, {{.User}} — {{.Message}}".
+type MessageData struct {
+	Message   string
+	Provider  string
+	Time      time.Time
+	Hostname  string
+	User      string
+	Uptime    time.Duration
+	Wallpaper string
+}
+
+// Greeting returns "Good morning", "Good afternoon", "Good evening" or "Good night" for Time.
+func (d MessageData) Greeting() string {
+	switch hour := d.Time.Hour(); {
+	case hour >= 5 && hour < 12:
+		return "Good morning"
+	case hour >= 12 && hour < 18:
+		return "Good afternoon"
+	case hour >= 18 && hour < 22:
+		return "Good evening"
+	default:
+		return "Good night"
+	}
+}
+
+// Date returns Time as "Mon, 02 Jan".
+func (d MessageData) Date() string {
+	return d.Time.Format("Mon, 02 Jan")
+}
+
+// Clock returns Time as "15:04".
+func (d MessageData) Clock() string {
+	return d.Time.Format("15:04")
+}
+
+// UptimeText returns Uptime as "2d 3h 12m".
+func (d MessageData) UptimeText() string {
+	minutes := int(d.Uptime.Minutes())
+	days, hours := minutes/(24*60), minutes/60%24
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes%60)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes%60)
+	default:
+		return fmt.Sprintf("%dm", minutes%60)
+	}
+}
+
+var formatFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"truncate": func(length int, text string) string {
+		return sanitize.Truncate(text, length, "…")
+	},
+	"wrap": wrap,
+}
+
+// ParseFormat compiles a message format. Go templates get MessageData and the upper, lower,
+// trim, truncate and wrap helpers; printf-style formats are still accepted, with every %s
+// standing for the message. When escape is set, the output of every action goes through it,
+// so helpers see raw text while the markup written in the format is kept.
+func ParseFormat(format string, escape func(string) string) (*template.Template, error) {
+	if !strings.Contains(format, "{{") {
+		format = strings.ReplaceAll(format, "%s", "{{.Message}}")
+		format = strings.ReplaceAll(format, "%%", "%")
+	}
+
+	// actions may print any value, such as .Uptime or .Time.Hour, so it is printed first
+	funcs := template.FuncMap{escapeFunc: fmt.Sprint}
+	if escape != nil {
+		funcs[escapeFunc] = func(value any) string { return escape(fmt.Sprint(value)) }
+	}
+
+	tmpl, err := template.New("format").Funcs(formatFuncs).Funcs(funcs).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message format: %w", err)
+	}
+
+	if escape != nil {
+		escapeActions(tmpl.Tree, tmpl.Tree.Root)
+	}
+
+	return tmpl, nil
+}
+
+// RenderFormat renders format with data, escaping the values with escape when it is set.
+func RenderFormat(format string, data MessageData, escape func(string) string) (string, error) {
+	tmpl, err := ParseFormat(format, escape)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", fmt.Errorf("failed to render message format: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+// ApplyFormat renders format with joke as the message, returning the joke unchanged when
+// the format is invalid.
+func ApplyFormat(joke string, format string) string {
+	message, err := RenderFormat(format, MessageData{Message: joke, Time: time.Now()}, nil)
+	if err != nil {
+		return joke
+	}
+
+	return message
+}
+
+const escapeFunc = "_escape"
+
+// escapeActions appends the escape function to every action that prints a value, the way
+// html/template does.
+func escapeActions(tree *parse.Tree, list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+
+	for _, node := range list.Nodes {
+		switch node := node.(type) {
+		case *parse.ActionNode:
+			if len(node.Pipe.Decl) > 0 {
+				continue
+			}
+			identifier := parse.NewIdentifier(escapeFunc).SetTree(tree).SetPos(node.Pos)
+			node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Pos:      node.Pos,
+				Args:     []parse.Node{identifier},
+			})
+		case *parse.IfNode:
+			escapeActions(tree, node.List)
+			escapeActions(tree, node.ElseList)
+		case *parse.RangeNode:
+			escapeActions(tree, node.List)
+			escapeActions(tree, node.ElseList)
+		case *parse.WithNode:
+			escapeActions(tree, node.List)
+			escapeActions(tree, node.ElseList)
+		}
+	}
+}
+
+// wrap breaks text into lines of at most width graphemes at word boundaries.
+func wrap(width int, text string) string {
+	if width <= 0 {
+		return text
+	}
+
+	var lines []string
+	var line string
+
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case sanitize.Length(line)+1+sanitize.Length(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,120 @@
+package jokes
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderFormat(t *testing.T) {
+	data := MessageData{
+		Message:   "Why did the gopher cross the road?",
+		Provider:  "reddit",
+		Time:      time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC),
+		Hostname:  "archlinux",
+		User:      "will",
+		Uptime:    26*time.Hour + 12*time.Minute,
+		Wallpaper: "mountains",
+	}
+
+	tests := []struct {
+		name      string
+		format    string
+		expected  string
+		expectErr bool
+	}{
+		{"Template", "{{.Greeting}}, {{.User}} — {{.Message}}", "Good morning, will — Why did the gopher cross the road?", false},
+		{"Fields", "{{.Provider}}@{{.Hostname}} {{.Date}} {{.Clock}} up {{.UptimeText}} on {{.Wallpaper}}", "reddit@archlinux Mon, 04 Mar 09:30 up 1d 2h 12m on mountains", false},
+		{"Upper", "{{.User | upper}}", "WILL", false},
+		{"Truncate", "{{.Message | truncate 20}}", "Why did the gopher…", false},
+		{"Wrap", "{{wrap 18 .Message}}", "Why did the gopher\ncross the road?", false},
+		{"Printf", "👉 %s 🤪", "👉 Why did the gopher cross the road? 🤪", false},
+		{"PrintfPercent", "100%% %s", "100% Why did the gopher cross the road?", false},
+		{"Static", "Locked", "Locked", false},
+		{"InvalidTemplate", "{{.Message", "", true},
+		{"UnknownField", "{{.Missing}}", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderFormat(tt.format, data, nil)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRenderFormat_Escape(t *testing.T) {
+	escape := func(text string) string { return strings.ReplaceAll(text, "&", "&amp;") }
+	data := MessageData{Message: "fish & chips", User: "tom&jerry", Uptime: 90 * time.Minute, Time: time.Date(2025, 1, 1, 21, 5, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{"Values", "<b>{{.Message}}</b> & {{.User}}", "<b>fish &amp; chips</b> & tom&amp;jerry"},
+		{"HelpersSeeRawText", "{{.Message | upper}}", "FISH &amp; CHIPS"},
+		{"Conditional", "{{if .User}}{{.User}}{{else}}nobody{{end}}", "tom&amp;jerry"},
+		{"Variable", "{{$name := .User}}{{$name}}", "tom&amp;jerry"},
+		{"Printf", "%s & more", "fish &amp; chips & more"},
+		{"Duration", "up {{.Uptime}}", "up 1h30m0s"},
+		{"Int", "{{.Time.Hour}}h & {{len .User}}", "21h & 9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderFormat(tt.format, data, escape)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMessageData_Greeting(t *testing.T) {
+	tests := []struct {
+		hour     int
+		expected string
+	}{
+		{5, "Good morning"},
+		{12, "Good afternoon"},
+		{18, "Good evening"},
+		{22, "Good night"},
+		{3, "Good night"},
+	}
+
+	for _, tt := range tests {
+		data := MessageData{Time: time.Date(2024, time.March, 4, tt.hour, 0, 0, 0, time.UTC)}
+		if greeting := data.Greeting(); greeting != tt.expected {
+			t.Errorf("Greeting at %dh: expected %q, got %q", tt.hour, tt.expected, greeting)
+		}
+	}
+}
+
+func TestMessageData_UptimeText(t *testing.T) {
+	tests := []struct {
+		uptime   time.Duration
+		expected string
+	}{
+		{42 * time.Second, "0m"},
+		{5 * time.Minute, "5m"},
+		{3*time.Hour + 7*time.Minute, "3h 7m"},
+		{50 * time.Hour, "2d 2h 0m"},
+	}
+
+	for _, tt := range tests {
+		if text := (MessageData{Uptime: tt.uptime}).UptimeText(); text != tt.expected {
+			t.Errorf("UptimeText(%s): expected %q, got %q", tt.uptime, tt.expected, text)
+		}
+	}
+}
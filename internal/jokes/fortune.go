@@ -0,0 +1,211 @@
+package jokes
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	strfileHeaderSize = 24
+	strfileRotated    = 0x4
+)
+
+// DefaultFortuneDirs are searched when the fortune provider has no source.
+var DefaultFortuneDirs = []string{"/usr/share/fortune", "/usr/share/games/fortunes"}
+
+// fortuneJoke reads a random cookie from fortune(6) databases: a single file, or every
+// database of a directory weighted by its number of cookies.
+type fortuneJoke struct{ JokeProvider }
+
+func (j *fortuneJoke) FetchJokes(context.Context) (string, error) {
+	source := j.source
+	if source == "" {
+		source = firstExistingDir(DefaultFortuneDirs)
+		if source == "" {
+			return "", fmt.Errorf("no fortune database found in %s", strings.Join(DefaultFortuneDirs, ", "))
+		}
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	path, err := pickFortuneFile(source, r)
+	if err != nil {
+		return "", err
+	}
+
+	return RandomFortune(path, r)
+}
+
+// StrfileHeader is the header of a strfile(1) index.
+type StrfileHeader struct {
+	Version  uint32
+	Count    uint32
+	Longest  uint32
+	Shortest uint32
+	Flags    uint32
+	Delim    byte
+}
+
+// FortuneIndex is a parsed strfile .dat index: the header and the offset of every cookie.
+type FortuneIndex struct {
+	Header  StrfileHeader
+	Offsets []uint32
+}
+
+// ReadFortuneIndex parses the big-endian strfile index at path.
+func ReadFortuneIndex(path string) (*FortuneIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fortune index: %w", err)
+	}
+
+	if len(data) < strfileHeaderSize {
+		return nil, fmt.Errorf("invalid fortune index %s: header too short", path)
+	}
+
+	index := &FortuneIndex{
+		Header: StrfileHeader{
+			Version:  binary.BigEndian.Uint32(data[0:4]),
+			Count:    binary.BigEndian.Uint32(data[4:8]),
+			Longest:  binary.BigEndian.Uint32(data[8:12]),
+			Shortest: binary.BigEndian.Uint32(data[12:16]),
+			Flags:    binary.BigEndian.Uint32(data[16:20]),
+			Delim:    data[20],
+		},
+	}
+
+	offsets := data[strfileHeaderSize:]
+	if uint64(len(offsets)) < uint64(index.Header.Count)*4 {
+		return nil, fmt.Errorf("invalid fortune index %s: expected %d offsets", path, index.Header.Count)
+	}
+
+	for i := range index.Header.Count {
+		index.Offsets = append(index.Offsets, binary.BigEndian.Uint32(offsets[i*4:]))
+	}
+
+	return index, nil
+}
+
+// RandomFortune returns a random cookie of the fortune file at path, using its .dat index
+// when there is one and splitting the file on '%' lines otherwise.
+func RandomFortune(path string, r *rand.Rand) (string, error) {
+	path = strings.TrimSuffix(path, ".dat")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read fortune file: %w", err)
+	}
+
+	index, err := ReadFortuneIndex(path + ".dat")
+	if errors.Is(err, os.ErrNotExist) {
+		quotes := parseTextQuotes(string(data))
+		if len(quotes) == 0 {
+			return "", fmt.Errorf("no fortunes found in %s", path)
+		}
+		return quotes[r.Intn(len(quotes))].Text, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if index.Header.Count == 0 {
+		return "", fmt.Errorf("no fortunes found in %s", path)
+	}
+
+	return index.Cookie(data, r.Intn(int(index.Header.Count)))
+}
+
+// Cookie returns cookie i of the fortune text data, decoding rot13 when the index is flagged
+// as rotated.
+func (f *FortuneIndex) Cookie(data []byte, i int) (string, error) {
+	offset := int(f.Offsets[i])
+	if offset > len(data) {
+		return "", fmt.Errorf("fortune offset %d out of range", offset)
+	}
+
+	text := data[offset:]
+	delimiter := []byte{'\n', f.Header.Delim, '\n'}
+	if end := bytes.Index(text, delimiter); end >= 0 {
+		text = text[:end+1]
+	} else if bytes.HasSuffix(text, delimiter[:2]) {
+		text = text[:len(text)-1]
+	}
+
+	cookie := strings.TrimSpace(string(text))
+	if f.Header.Flags&strfileRotated != 0 {
+		cookie = rot13(cookie)
+	}
+
+	return cookie, nil
+}
+
+func pickFortuneFile(source string, r *rand.Rand) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to read fortune source: %w", err)
+	}
+
+	if !info.IsDir() {
+		return source, nil
+	}
+
+	indexes, _ := filepath.Glob(filepath.Join(source, "*.dat"))
+
+	var files []string
+	var weights []int
+	total := 0
+
+	for _, path := range indexes {
+		index, err := ReadFortuneIndex(path)
+		if err != nil || index.Header.Count == 0 {
+			continue
+		}
+
+		files = append(files, path)
+		weights = append(weights, int(index.Header.Count))
+		total += int(index.Header.Count)
+	}
+
+	if total == 0 {
+		return "", fmt.Errorf("no fortune database found in %s", source)
+	}
+
+	target := r.Intn(total)
+	for i, weight := range weights {
+		if target -= weight; target < 0 {
+			return files[i], nil
+		}
+	}
+
+	return files[len(files)-1], nil
+}
+
+func firstExistingDir(dirs []string) string {
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+func rot13(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,124 @@
+package jokes
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+// writeFortune writes a fortune file and its strfile index.
+func writeFortune(t *testing.T, path string, cookies []string, flags uint32) {
+	t.Helper()
+
+	var text []byte
+	var offsets []uint32
+	for _, cookie := range cookies {
+		offsets = append(offsets, uint32(len(text)))
+		text = append(text, cookie+"\n%\n"...)
+	}
+	offsets = append(offsets, uint32(len(text)))
+
+	index := make([]byte, strfileHeaderSize, strfileHeaderSize+len(offsets)*4)
+	binary.BigEndian.PutUint32(index[0:], 2)
+	binary.BigEndian.PutUint32(index[4:], uint32(len(cookies)))
+	binary.BigEndian.PutUint32(index[16:], flags)
+	index[20] = '%'
+	for _, offset := range offsets {
+		index = binary.BigEndian.AppendUint32(index, offset)
+	}
+
+	if err := os.WriteFile(path, text, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".dat", index, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadFortuneIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "computers")
+	writeFortune(t, path, []string{"one", "two\nlines"}, 0)
+
+	index, err := ReadFortuneIndex(path + ".dat")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if index.Header.Count != 2 || index.Header.Delim != '%' {
+		t.Errorf("Unexpected header: %+v", index.Header)
+	}
+
+	data, _ := os.ReadFile(path)
+	for i, expected := range []string{"one", "two\nlines"} {
+		cookie, err := index.Cookie(data, i)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cookie != expected {
+			t.Errorf("Expected '%s', got '%s'", expected, cookie)
+		}
+	}
+
+	os.WriteFile(path+".dat", []byte("short"), 0644)
+	if _, err := ReadFortuneIndex(path + ".dat"); err == nil {
+		t.Error("Expected error for a truncated index")
+	}
+}
+
+func TestRandomFortune(t *testing.T) {
+	dir := t.TempDir()
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("Rotated", func(t *testing.T) {
+		path := filepath.Join(dir, "offensive")
+		writeFortune(t, path, []string{"Uryyb, jbeyq!"}, strfileRotated)
+
+		cookie, err := RandomFortune(path, r)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cookie != "Hello, world!" {
+			t.Errorf("Expected 'Hello, world!', got '%s'", cookie)
+		}
+	})
+
+	t.Run("Without index", func(t *testing.T) {
+		path := filepath.Join(dir, "plain")
+		os.WriteFile(path, []byte("first\n%\nsecond\n%\n"), 0644)
+
+		cookie, err := RandomFortune(path, r)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !slices.Contains([]string{"first", "second"}, cookie) {
+			t.Errorf("Unexpected cookie '%s'", cookie)
+		}
+	})
+}
+
+func TestFortuneProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeFortune(t, filepath.Join(dir, "a"), []string{"from a"}, 0)
+	writeFortune(t, filepath.Join(dir, "b"), []string{"from b", "also b"}, 0)
+
+	fetcher := BuildJokeFetcher(core.BuildSilentLogger(), "fortune:"+dir, false)
+	for range 10 {
+		joke, err := fetcher.FetchJokes(t.Context())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !slices.Contains([]string{"from a", "from b", "also b"}, joke) {
+			t.Errorf("Unexpected fortune '%s'", joke)
+		}
+	}
+
+	empty := BuildJokeFetcher(core.BuildSilentLogger(), "fortune:"+t.TempDir(), false)
+	if _, err := empty.FetchJokes(t.Context()); err == nil {
+		t.Error("Expected error for a directory without fortunes")
+	}
+}
@@ -0,0 +1,226 @@
+package jokes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// httpProviderTimeout bounds the requests of the online providers.
+const httpProviderTimeout = 10 * time.Second
+
+// HttpProviderConfig declares a provider that fetches text from a JSON API.
+type HttpProviderConfig struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	// Path selects the text in the response; a list result is cached and picked from randomly.
+	Path string `yaml:"path"`
+	// AuthorPath optionally selects the authors, paired with the texts by position.
+	AuthorPath string `yaml:"author_path"`
+	// CacheTTL is how long the cached pool is used before it is fetched again, one hour by default.
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+// ProvidersConfig is the providers.yaml file.
+type ProvidersConfig struct {
+	Providers []HttpProviderConfig `yaml:"providers"`
+}
+
+// DefaultProvidersPath returns the providers file under the XDG config directory.
+func DefaultProvidersPath() string {
+	return filepath.Join(core.ConfigDir(), "providers.yaml")
+}
+
+// LoadProviders reads and validates a providers file.
+func LoadProviders(path string) ([]HttpProviderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read providers file: %w", err)
+	}
+
+	var config ProvidersConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse providers file '%s': %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for i, provider := range config.Providers {
+		if provider.Name == "" || provider.URL == "" || provider.Path == "" {
+			return nil, fmt.Errorf("provider %d in %s: name, url and path are required", i+1, path)
+		}
+
+		if seen[provider.Name] {
+			return nil, fmt.Errorf("provider '%s' is declared twice in %s", provider.Name, path)
+		}
+		seen[provider.Name] = true
+
+		for _, expression := range []string{provider.Path, provider.AuthorPath} {
+			if expression == "" {
+				continue
+			}
+			if _, err := ParseJSONPath(expression); err != nil {
+				return nil, fmt.Errorf("provider '%s': %w", provider.Name, err)
+			}
+		}
+	}
+
+	return config.Providers, nil
+}
+
+// RegisterProviders adds the HTTP providers to PROVIDERS. Built-in providers cannot be
+// replaced.
+func RegisterProviders(configs []HttpProviderConfig) error {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	for _, config := range configs {
+		if existing, ok := PROVIDERS[config.Name]; ok {
+			if _, isHttp := existing.(*httpJoke); !isHttp {
+				return fmt.Errorf("provider '%s' is built in and cannot be redefined", config.Name)
+			}
+		}
+
+		PROVIDERS[config.Name] = &httpJoke{config: config}
+	}
+
+	return nil
+}
+
+// RegisterProvidersFile registers the providers of path. A missing file is ignored unless
+// required is set.
+func RegisterProvidersFile(path string, required bool) error {
+	configs, err := LoadProviders(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return RegisterProviders(configs)
+}
+
+// RegisterConfiguredProviders registers the providers file at path, or the default one when
+// path is empty. Only an explicit file is required to exist.
+func RegisterConfiguredProviders(path string) error {
+	if path == "" {
+		return RegisterProvidersFile(DefaultProvidersPath(), false)
+	}
+
+	return RegisterProvidersFile(core.ResolvePath(os.ExpandEnv(path)), true)
+}
+
+// httpJoke fetches texts from a configured JSON API.
+type httpJoke struct {
+	JokeProvider
+	config HttpProviderConfig
+}
+
+func (j *httpJoke) FetchJokes(ctx context.Context) (string, error) {
+	return randomEntry(j.FetchPool(ctx))
+}
+
+// FetchPool returns every text matched by the configured path.
+func (j *httpJoke) FetchPool(ctx context.Context) ([]string, error) {
+	j.logger.Debug("Fetching jokes", "provider", j.config.Name, "url", j.config.URL)
+
+	texts, err := j.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("provider '%s': path '%s' matched no text", j.config.Name, j.config.Path)
+	}
+
+	return texts, nil
+}
+
+// CacheTTL returns the configured cache_ttl, one hour by default.
+func (j *httpJoke) CacheTTL() time.Duration {
+	if j.config.CacheTTL > 0 {
+		return j.config.CacheTTL
+	}
+
+	return cacheDuration
+}
+
+func (j *httpJoke) fetch(ctx context.Context) ([]string, error) {
+	method := j.config.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, j.config.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	for key, value := range j.config.Headers {
+		req.Header.Set(key, os.ExpandEnv(value))
+	}
+
+	client := &http.Client{Timeout: httpProviderTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jokes from '%s': %s", j.config.Name, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode response of '%s': %w", j.config.Name, err)
+	}
+
+	return j.extract(document)
+}
+
+func (j *httpJoke) extract(document any) ([]string, error) {
+	path, err := ParseJSONPath(j.config.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := path.Texts(document)
+	if j.config.AuthorPath == "" {
+		return texts, nil
+	}
+
+	authorPath, err := ParseJSONPath(j.config.AuthorPath)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := authorPath.Texts(document)
+	if len(authors) != len(texts) {
+		j.logger.Warning("Authors do not match the texts, ignoring them", "provider", j.config.Name)
+		return texts, nil
+	}
+
+	for i := range texts {
+		texts[i] = Quote{Text: texts[i], Author: authors[i]}.String()
+	}
+
+	return texts, nil
+}
@@ -0,0 +1,187 @@
+package jokes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func TestLoadProviders(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expectErr bool
+	}{
+		{"Valid", "providers:\n  - name: zen\n    url: http://localhost\n    path: $[*].q\n    cache_ttl: 6h\n", false},
+		{"MissingPath", "providers:\n  - name: zen\n    url: http://localhost\n", true},
+		{"Duplicated", "providers:\n  - {name: a, url: http://a, path: $.a}\n  - {name: a, url: http://b, path: $.b}\n", true},
+		{"InvalidPath", "providers:\n  - {name: a, url: http://a, path: \"$.a[\"}\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "providers.yaml")
+			os.WriteFile(path, []byte(tt.content), 0644)
+
+			providers, err := LoadProviders(path)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+
+			if !tt.expectErr && providers[0].CacheTTL != 6*time.Hour {
+				t.Errorf("Expected cache_ttl 6h, got %v", providers[0].CacheTTL)
+			}
+		})
+	}
+}
+
+func TestRegisterProviders(t *testing.T) {
+	defer delete(PROVIDERS, "custom")
+
+	if err := RegisterProviders([]HttpProviderConfig{{Name: "reddit", URL: "http://a", Path: "$.a"}}); err == nil {
+		t.Error("Expected error when redefining a built-in provider")
+	}
+
+	if err := RegisterProviders([]HttpProviderConfig{{Name: "custom", URL: "http://a", Path: "$.a"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, ok := PROVIDERS["custom"].(*httpJoke); !ok {
+		t.Error("Expected 'custom' to be registered as an HTTP provider")
+	}
+
+	if err := RegisterProvidersFile(filepath.Join(t.TempDir(), "missing.yaml"), false); err != nil {
+		t.Errorf("Expected a missing optional file to be ignored, got %v", err)
+	}
+
+	if err := RegisterProvidersFile(filepath.Join(t.TempDir(), "missing.yaml"), true); err == nil {
+		t.Error("Expected error for a missing required file")
+	}
+}
+
+func TestRegisterProviders_Concurrent(t *testing.T) {
+	defer delete(PROVIDERS, "concurrent")
+
+	config := HttpProviderConfig{Name: "concurrent", URL: "http://a", Path: "$.a"}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := RegisterProviders([]HttpProviderConfig{config}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			lookupProvider("concurrent")
+			Providers()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHttpJoke_FetchJokes(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("QUOTES_TOKEN", "secret")
+	defer delete(PROVIDERS, "test-quotes")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"q": "First quote", "a": "Ann"}, {"q": "Second quote", "a": "Bob"}]`))
+	}))
+	defer server.Close()
+
+	config := HttpProviderConfig{
+		Name:       "test-quotes",
+		URL:        server.URL,
+		Headers:    map[string]string{"Authorization": "Bearer $QUOTES_TOKEN"},
+		Path:       "$[*].q",
+		AuthorPath: "$[*].a",
+		CacheTTL:   time.Minute,
+	}
+
+	if err := RegisterProviders([]HttpProviderConfig{config}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var results []string
+	for range 2 {
+		fetcher := BuildJokeFetcher(core.BuildSilentLogger(), "test-quotes", true)
+
+		result, err := fetcher.FetchJokes(t.Context())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		results = append(results, result)
+	}
+	WaitRefills()
+
+	slices.Sort(results)
+	if !slices.Equal(results, []string{"First quote — Ann", "Second quote — Bob"}) {
+		t.Errorf("Expected both quotes without repeats, got %v", results)
+	}
+
+	if requests > 2 {
+		t.Errorf("Expected the cached pool to be reused, got %d requests", requests)
+	}
+
+	t.Run("HTTPError", func(t *testing.T) {
+		failing := &httpJoke{config: HttpProviderConfig{Name: "failing", URL: server.URL, Path: "$[*].q"}}
+		failing.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
+
+		_, err := failing.FetchJokes(t.Context())
+		if err == nil || !strings.Contains(err.Error(), "401") {
+			t.Errorf("Expected an unauthorized error, got %v", err)
+		}
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		empty := &httpJoke{config: HttpProviderConfig{
+			Name:    "empty",
+			URL:     server.URL,
+			Headers: config.Headers,
+			Path:    "$.missing",
+		}}
+		empty.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
+
+		if _, err := empty.FetchJokes(t.Context()); err == nil {
+			t.Error("Expected error when the path matches nothing")
+		}
+	})
+}
+
+func TestRedditURL(t *testing.T) {
+	defer func(url string) { RedditURL = url }(RedditURL)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"children": [{"data": {"title": "Only joke"}}]}}`))
+	}))
+	defer server.Close()
+	RedditURL = server.URL
+
+	joke := &redditJoke{}
+	joke.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
+
+	result, err := joke.FetchJokes(t.Context())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != "Only joke" {
+		t.Errorf("Expected 'Only joke', got '%s'", result)
+	}
+}
@@ -1,104 +1,84 @@
 package jokes
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"math/rand"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 )
 
 const (
-	icanhazjokeCacheFilePath = "/tmp/icanhazjoke_cache.json"
+	icanhazjokePageSize = 30
+	// icanhazjokePages is the number of search pages a refill picks from.
+	icanhazjokePages = 20
 )
 
-type icanhazjokeCacheData struct {
-	Joke      string    `json:"joke"`
-	Timestamp time.Time `json:"timestamp"`
-}
+// IcanhazjokeURL is the icanhazdadjoke endpoint, replaceable in tests.
+var IcanhazjokeURL = "https://icanhazdadjoke.com/"
 
-type icanhazjokeResponse struct {
-	Joke string `json:"joke"`
+type icanhazjokeSearchResponse struct {
+	Results []struct {
+		Joke string `json:"joke"`
+	} `json:"results"`
+	TotalPages int `json:"total_pages"`
 }
 
 type icanhazjoke struct{ JokeProvider }
 
-func (j *icanhazjoke) isCacheValid() bool {
-	if !j.useCache {
-		return false
-	}
-
-	info, err := os.Stat(icanhazjokeCacheFilePath)
-	if err != nil {
-		return false
-	}
-	return time.Since(info.ModTime()) < cacheDuration
+func (j *icanhazjoke) FetchJokes(ctx context.Context) (string, error) {
+	return randomEntry(j.FetchPool(ctx))
 }
 
-func (j *icanhazjoke) loadCache() (string, error) {
-	data, err := os.ReadFile(icanhazjokeCacheFilePath)
-	if err != nil {
-		return "", err
-	}
+// FetchPool reads a random page of the joke search, so the cached pool holds a batch of
+// jokes instead of a single one.
+func (j *icanhazjoke) FetchPool(ctx context.Context) ([]string, error) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	var cache icanhazjokeCacheData
-	err = json.Unmarshal(data, &cache)
-	if err != nil {
-		return "", err
+	jokes, err := j.search(ctx, r.Intn(icanhazjokePages)+1)
+	if err == nil && len(jokes) == 0 {
+		// fewer pages than expected, the first one always exists
+		jokes, err = j.search(ctx, 1)
 	}
 
-	return cache.Joke, nil
+	return jokes, err
 }
 
-func (j *icanhazjoke) saveCache(joke string) error {
-	cache := icanhazjokeCacheData{
-		Joke:      joke,
-		Timestamp: time.Now(),
-	}
+func (j *icanhazjoke) search(ctx context.Context, page int) ([]string, error) {
+	j.logger.Debug("Fetching new jokes from icanhazdadjoke", "page", page)
 
-	data, err := json.Marshal(cache)
+	endpoint := fmt.Sprintf("%s/search?limit=%d&page=%d", strings.TrimSuffix(IcanhazjokeURL, "/"), icanhazjokePageSize, page)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(icanhazjokeCacheFilePath, data, 0644)
-}
-
-func (j *icanhazjoke) FetchJokes() (string, error) {
-	if j.isCacheValid() {
-		joke, err := j.loadCache()
-		if err == nil && joke != "" {
-			j.logger.Debug("💾 Using cached jokes")
-			return joke, nil
-		}
-	}
-
-	j.logger.Debug("Fetching new joke from icanhazdadjoke")
-
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", "https://icanhazdadjoke.com/", nil)
-	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "Go Dad Joke Fetcher")
 
+	client := &http.Client{Timeout: httpProviderTimeout}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var jokeRes icanhazjokeResponse
-	err = json.NewDecoder(resp.Body).Decode(&jokeRes)
-	if err != nil {
-		return "", err
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jokes: %s", resp.Status)
 	}
 
-	err = j.saveCache(jokeRes.Joke)
-	if err != nil {
-		j.logger.Warning("Warning: failed to save cache", "error", err)
-		return "", err
+	var searchRes icanhazjokeSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchRes); err != nil {
+		return nil, err
+	}
+
+	var jokes []string
+	for _, result := range searchRes.Results {
+		if joke := strings.TrimSpace(result.Joke); joke != "" {
+			jokes = append(jokes, joke)
+		}
 	}
 
-	return jokeRes.Joke, nil
+	return jokes, nil
 }
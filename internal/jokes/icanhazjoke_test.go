@@ -2,16 +2,58 @@ package jokes
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"strings"
+	"slices"
 	"testing"
-	"time"
 
 	"github.com/williampsena/ebenezer-cli/internal/core"
 )
 
+// icanhazjokeServer serves totalPages pages of the joke search with two jokes each.
+func icanhazjokeServer(t *testing.T, totalPages int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			t.Errorf("Expected /search, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Error("Expected Accept header to be application/json")
+		}
+		if r.Header.Get("User-Agent") != "Go Dad Joke Fetcher" {
+			t.Error("Expected User-Agent header to be set correctly")
+		}
+
+		var page int
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+
+		response := icanhazjokeSearchResponse{TotalPages: totalPages}
+		if page <= totalPages {
+			response.Results = append(response.Results,
+				struct {
+					Joke string `json:"joke"`
+				}{fmt.Sprintf("joke %d.1", page)},
+				struct {
+					Joke string `json:"joke"`
+				}{fmt.Sprintf("joke %d.2", page)},
+			)
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}))
+
+	previous := IcanhazjokeURL
+	IcanhazjokeURL = server.URL + "/"
+	t.Cleanup(func() {
+		IcanhazjokeURL = previous
+		server.Close()
+	})
+
+	return server
+}
+
 func TestIcanhazjoke(t *testing.T) {
 	t.Run("Initialize", func(t *testing.T) {
 		logger := core.BuildSilentLogger()
@@ -35,378 +77,92 @@ func TestIcanhazjoke(t *testing.T) {
 		if joke.useCache != true {
 			t.Errorf("Expected useCache true, got %v", joke.useCache)
 		}
-	})
 
-	t.Run("isCacheValid", func(t *testing.T) {
-		t.Run("WhenCacheDisabled", func(t *testing.T) {
-			joke := &icanhazjoke{}
-			joke.useCache = false
-
-			if joke.isCacheValid() {
-				t.Error("Expected cache to be invalid when useCache is false")
-			}
-		})
-
-		t.Run("WhenFileDoesNotExist", func(t *testing.T) {
-			os.Remove(icanhazjokeCacheFilePath)
-
-			joke := &icanhazjoke{}
-			joke.useCache = true
-
-			if joke.isCacheValid() {
-				t.Error("Expected cache to be invalid when file doesn't exist")
-			}
-		})
-
-		t.Run("WhenFileIsOld", func(t *testing.T) {
-			cache := icanhazjokeCacheData{
-				Joke:      "Old joke",
-				Timestamp: time.Now().Add(-2 * time.Hour),
-			}
-
-			data, _ := json.Marshal(cache)
-			err := os.WriteFile(icanhazjokeCacheFilePath, data, 0644)
-			if err != nil {
-				t.Fatalf("Failed to create test cache file: %v", err)
-			}
-			defer os.Remove(icanhazjokeCacheFilePath)
-
-			oldTime := time.Now().Add(-2 * time.Hour)
-			os.Chtimes(icanhazjokeCacheFilePath, oldTime, oldTime)
-
-			joke := &icanhazjoke{}
-			joke.useCache = true
-
-			if joke.isCacheValid() {
-				t.Error("Expected cache to be invalid when file is older than 1 hour")
-			}
-		})
-
-		t.Run("WhenFileIsFresh", func(t *testing.T) {
-			cache := icanhazjokeCacheData{
-				Joke:      "Fresh joke",
-				Timestamp: time.Now(),
-			}
-
-			data, _ := json.Marshal(cache)
-			err := os.WriteFile(icanhazjokeCacheFilePath, data, 0644)
-			if err != nil {
-				t.Fatalf("Failed to create test cache file: %v", err)
-			}
-			defer os.Remove(icanhazjokeCacheFilePath)
-
-			joke := &icanhazjoke{}
-			joke.useCache = true
-
-			if !joke.isCacheValid() {
-				t.Error("Expected cache to be valid when file is fresh")
-			}
-		})
-	})
-
-	t.Run("loadCache", func(t *testing.T) {
-		t.Run("Success", func(t *testing.T) {
-			expectedJoke := "Test cached joke"
-			cache := icanhazjokeCacheData{
-				Joke:      expectedJoke,
-				Timestamp: time.Now(),
-			}
-
-			data, _ := json.Marshal(cache)
-			err := os.WriteFile(icanhazjokeCacheFilePath, data, 0644)
-			if err != nil {
-				t.Fatalf("Failed to create test cache file: %v", err)
-			}
-			defer os.Remove(icanhazjokeCacheFilePath)
-
-			joke := &icanhazjoke{}
-			loadedJoke, err := joke.loadCache()
-
-			if err != nil {
-				t.Errorf("Expected no error, got %v", err)
-			}
-
-			if loadedJoke != expectedJoke {
-				t.Errorf("Expected joke '%s', got '%s'", expectedJoke, loadedJoke)
-			}
-		})
-
-		t.Run("FileNotFound", func(t *testing.T) {
-			os.Remove(icanhazjokeCacheFilePath)
-
-			joke := &icanhazjoke{}
-			loadedJoke, err := joke.loadCache()
-
-			if err == nil {
-				t.Error("Expected error when cache file doesn't exist")
-			}
-
-			if loadedJoke != "" {
-				t.Errorf("Expected empty joke, got '%s'", loadedJoke)
-			}
-		})
-
-		t.Run("InvalidJSON", func(t *testing.T) {
-			err := os.WriteFile(icanhazjokeCacheFilePath, []byte("invalid json"), 0644)
-			if err != nil {
-				t.Fatalf("Failed to create test cache file: %v", err)
-			}
-			defer os.Remove(icanhazjokeCacheFilePath)
-
-			joke := &icanhazjoke{}
-			loadedJoke, err := joke.loadCache()
-
-			if err == nil {
-				t.Error("Expected error when cache file contains invalid JSON")
-			}
-
-			if loadedJoke != "" {
-				t.Errorf("Expected empty joke, got '%s'", loadedJoke)
-			}
-		})
+		if joke.CacheTTL() != cacheDuration {
+			t.Errorf("Expected cache TTL %v, got %v", cacheDuration, joke.CacheTTL())
+		}
 	})
 
-	t.Run("saveCache", func(t *testing.T) {
-		defer os.Remove(icanhazjokeCacheFilePath)
+	t.Run("FetchPool", func(t *testing.T) {
+		icanhazjokeServer(t, icanhazjokePages)
 
-		testJoke := "Test joke to save"
 		joke := &icanhazjoke{}
+		joke.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
 
-		err := joke.saveCache(testJoke)
-		if err != nil {
-			t.Errorf("Expected no error, got %v", err)
-		}
-
-		if _, err := os.Stat(icanhazjokeCacheFilePath); os.IsNotExist(err) {
-			t.Error("Expected cache file to be created")
-		}
-
-		data, err := os.ReadFile(icanhazjokeCacheFilePath)
-		if err != nil {
-			t.Fatalf("Failed to read cache file: %v", err)
-		}
-
-		var cache icanhazjokeCacheData
-		err = json.Unmarshal(data, &cache)
+		jokes, err := joke.FetchPool(t.Context())
 		if err != nil {
-			t.Fatalf("Failed to unmarshal cache data: %v", err)
-		}
-
-		if cache.Joke != testJoke {
-			t.Errorf("Expected cached joke '%s', got '%s'", testJoke, cache.Joke)
+			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		if time.Since(cache.Timestamp) > time.Minute {
-			t.Error("Expected timestamp to be recent")
+		if len(jokes) != 2 {
+			t.Errorf("Expected a page of 2 jokes, got %v", jokes)
 		}
 	})
 
-	t.Run("FetchJokes", func(t *testing.T) {
-		t.Run("FromCache_Success", func(t *testing.T) {
-			expectedJoke := "Cached dad joke"
-			cache := icanhazjokeCacheData{
-				Joke:      expectedJoke,
-				Timestamp: time.Now(),
-			}
-
-			data, _ := json.Marshal(cache)
-			err := os.WriteFile(icanhazjokeCacheFilePath, data, 0644)
-			if err != nil {
-				t.Fatalf("Failed to create test cache file: %v", err)
-			}
-			defer os.Remove(icanhazjokeCacheFilePath)
-
-			logger := core.BuildSilentLogger()
-			joke := &icanhazjoke{}
-			joke.Initialize(&JokeFetcherSettings{
-				logger:   logger,
-				provider: "icanhazdadjoke",
-				useCache: true,
-			})
-
-			result, err := joke.FetchJokes()
-
-			if err != nil {
-				t.Errorf("Expected no error, got %v", err)
-			}
-
-			if result != expectedJoke {
-				t.Errorf("Expected joke '%s', got '%s'", expectedJoke, result)
-			}
-		})
-
-		t.Run("FromHTTP Success", func(t *testing.T) {
-			defer os.Remove(icanhazjokeCacheFilePath)
-
-			expectedJoke := "Why don't scientists trust atoms? Because they make up everything!"
-
-			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if r.Header.Get("Accept") != "application/json" {
-					t.Error("Expected Accept header to be application/json")
-				}
-				if r.Header.Get("User-Agent") != "Go Dad Joke Fetcher" {
-					t.Error("Expected User-Agent header to be set correctly")
-				}
-
-				response := icanhazjokeResponse{Joke: expectedJoke}
-				json.NewEncoder(w).Encode(response)
-			}))
-			defer server.Close()
-
-			logger := core.BuildSilentLogger()
-			joke := &icanhazjoke{}
-			joke.Initialize(&JokeFetcherSettings{
-				logger:   logger,
-				provider: "icanhazdadjoke",
-				useCache: false, // Disable cache to force HTTP request
-			})
-
-			_, err := joke.FetchJokes()
-			if err != nil {
-				if strings.Contains(err.Error(), "dial") || strings.Contains(err.Error(), "lookup") {
-					t.Skip("Skipping HTTP test due to network unavailability")
-				} else {
-					t.Errorf("Unexpected error: %v", err)
-				}
-			}
-		})
-
-		t.Run("FromHTTP_Error", func(t *testing.T) {
-			defer os.Remove(icanhazjokeCacheFilePath)
-
-			logger := core.BuildSilentLogger()
-			joke := &icanhazjoke{}
-			joke.Initialize(&JokeFetcherSettings{
-				logger:   logger,
-				provider: "icanhazdadjoke",
-				useCache: false,
-			})
-
-			oldTransport := http.DefaultTransport
-			http.DefaultTransport = &http.Transport{
-				ResponseHeaderTimeout: 1 * time.Nanosecond, // Force timeout
-			}
-			defer func() { http.DefaultTransport = oldTransport }()
-
-			_, err := joke.FetchJokes()
-
-			if err != nil {
-				t.Logf("Got expected error: %v", err)
-			} else {
-				t.Logf("Request succeeded despite timeout setting")
-			}
-		})
-	})
+	t.Run("FetchPool_FewerPages", func(t *testing.T) {
+		icanhazjokeServer(t, 1)
 
-	t.Run("MalformedResponse", func(t *testing.T) {
-		defer os.Remove(icanhazjokeCacheFilePath)
-
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte("invalid json response"))
-		}))
-		defer server.Close()
-
-		logger := core.BuildSilentLogger()
 		joke := &icanhazjoke{}
-		joke.Initialize(&JokeFetcherSettings{
-			logger:   logger,
-			provider: "icanhazdadjoke",
-			useCache: false,
-		})
-	})
-
-	t.Run("EmptyResponse", func(t *testing.T) {
-		expectedJoke := "Test joke for JSON"
-		response := icanhazjokeResponse{Joke: expectedJoke}
-
-		data, err := json.Marshal(response)
-		if err != nil {
-			t.Errorf("Failed to marshal response: %v", err)
-		}
+		joke.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
 
-		var unmarshaled icanhazjokeResponse
-		err = json.Unmarshal(data, &unmarshaled)
+		jokes, err := joke.FetchPool(t.Context())
 		if err != nil {
-			t.Errorf("Failed to unmarshal response: %v", err)
+			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		if unmarshaled.Joke != expectedJoke {
-			t.Errorf("Expected joke '%s', got '%s'", expectedJoke, unmarshaled.Joke)
+		if !slices.Equal(jokes, []string{"joke 1.1", "joke 1.2"}) {
+			t.Errorf("Expected the first page, got %v", jokes)
 		}
 	})
 
-	t.Run("JSONMarshaling", func(t *testing.T) {
-		expectedJoke := "Test cached joke"
-		expectedTime := time.Now().Round(time.Second)
-
-		cache := icanhazjokeCacheData{
-			Joke:      expectedJoke,
-			Timestamp: expectedTime,
-		}
+	t.Run("FetchJokes", func(t *testing.T) {
+		icanhazjokeServer(t, 1)
 
-		data, err := json.Marshal(cache)
-		if err != nil {
-			t.Errorf("Failed to marshal cache data: %v", err)
-		}
+		joke := &icanhazjoke{}
+		joke.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
 
-		var unmarshaled icanhazjokeCacheData
-		err = json.Unmarshal(data, &unmarshaled)
+		result, err := joke.FetchJokes(t.Context())
 		if err != nil {
-			t.Errorf("Failed to unmarshal cache data: %v", err)
-		}
-
-		if unmarshaled.Joke != expectedJoke {
-			t.Errorf("Expected joke '%s', got '%s'", expectedJoke, unmarshaled.Joke)
+			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		if !unmarshaled.Timestamp.Equal(expectedTime) {
-			t.Errorf("Expected timestamp %v, got %v", expectedTime, unmarshaled.Timestamp)
+		if !slices.Contains([]string{"joke 1.1", "joke 1.2"}, result) {
+			t.Errorf("Unexpected joke '%s'", result)
 		}
 	})
 
-	t.Run("CacheFilePath", func(t *testing.T) {
-		expectedPath := "/tmp/icanhazjoke_cache.json"
-		if icanhazjokeCacheFilePath != expectedPath {
-			t.Errorf("Expected cache file path '%s', got '%s'", expectedPath, icanhazjokeCacheFilePath)
-		}
-	})
-
-	t.Run("FetchJokes_EmptyResponse", func(t *testing.T) {
-		if testing.Short() {
-			t.Skip("Skipping integration test in short mode")
-		}
+	t.Run("MalformedResponse", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("invalid json response"))
+		}))
+		defer server.Close()
 
-		defer os.Remove(icanhazjokeCacheFilePath)
+		defer func(url string) { IcanhazjokeURL = url }(IcanhazjokeURL)
+		IcanhazjokeURL = server.URL
 
-		logger := core.BuildSilentLogger()
 		joke := &icanhazjoke{}
-		joke.Initialize(&JokeFetcherSettings{
-			logger:   logger,
-			provider: "icanhazdadjoke",
-			useCache: true,
-		})
+		joke.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
 
-		result1, err1 := joke.FetchJokes()
-		if err1 != nil {
-			t.Skipf("Skipping integration test due to network error: %v", err1)
+		if _, err := joke.FetchJokes(t.Context()); err == nil {
+			t.Error("Expected error for a malformed response")
 		}
+	})
 
-		if result1 == "" {
-			t.Error("Expected non-empty joke from HTTP")
-		}
+	t.Run("HTTPError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
 
-		result2, err2 := joke.FetchJokes()
-		if err2 != nil {
-			t.Errorf("Expected no error on cached fetch, got %v", err2)
-		}
+		defer func(url string) { IcanhazjokeURL = url }(IcanhazjokeURL)
+		IcanhazjokeURL = server.URL
 
-		if result2 != result1 {
-			t.Error("Expected same joke from cache")
-		}
+		joke := &icanhazjoke{}
+		joke.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger()})
 
-		if _, err := os.Stat(icanhazjokeCacheFilePath); os.IsNotExist(err) {
-			t.Error("Expected cache file to exist after HTTP fetch")
+		if _, err := joke.FetchPool(t.Context()); err == nil {
+			t.Error("Expected error for a failed request")
 		}
 	})
 }
@@ -1,8 +1,12 @@
 package jokes
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/williampsena/ebenezer-cli/internal/core"
@@ -10,14 +14,32 @@ import (
 
 var cacheDuration = time.Hour
 
+// maxFilterAttempts bounds how many jokes are fetched looking for one that passes the filters.
+const maxFilterAttempts = 10
+
 type JokesInterface interface {
-	FetchJokes() (string, error)
+	FetchJokes(ctx context.Context) (string, error)
 	Initialize(settings *JokeFetcherSettings)
 }
 
+// providersMu guards PROVIDERS, which providers.yaml extends while other runs fetch.
+var providersMu sync.RWMutex
+
 var PROVIDERS = map[string]JokesInterface{
 	"icanhazdadjoke": &icanhazjoke{},
 	"reddit":         &redditJoke{},
+	"quotes":         &quotesJoke{},
+	"fortune":        &fortuneJoke{},
+	"bible":          &bibleJoke{},
+	"votd":           &bibleJoke{daily: true},
+}
+
+// lookupProvider returns the registered provider called name, or nil.
+func lookupProvider(name string) JokesInterface {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	return PROVIDERS[name]
 }
 
 type JokeProvider struct {
@@ -28,47 +50,167 @@ func (j *JokeProvider) Initialize(settings *JokeFetcherSettings) {
 	j.JokeFetcherSettings = *settings
 }
 
+// CacheTTL is how long a cached pool is used before it is fetched again.
+func (j *JokeProvider) CacheTTL() time.Duration {
+	return cacheDuration
+}
+
 type JokeFetcher struct {
 	settings *JokeFetcherSettings
 }
 
 type JokeFetcherSettings struct {
 	logger   core.Logger
+	name     string
 	provider string
+	source   string
 	useCache bool
+	cache    *CacheStore
+	options  Options
+}
+
+// cacheKey names the cached pool of the provider.
+func (s *JokeFetcherSettings) cacheKey() string {
+	if s.provider == "reddit" {
+		return redditCacheKey(s.provider, s.options)
+	}
+
+	return s.provider
 }
 
+// BuildJokeFetcher returns a fetcher for provider. Offline providers take a file or
+// directory after a colon, e.g. "fortune:/usr/share/fortune/computers".
 func BuildJokeFetcher(logger core.Logger, provider string, useCache bool) JokeFetcher {
+	return BuildJokeFetcherWithOptions(logger, provider, useCache, Options{})
+}
+
+// BuildJokeFetcherWithOptions returns a fetcher whose jokes are filtered by options.
+func BuildJokeFetcherWithOptions(logger core.Logger, provider string, useCache bool, options Options) JokeFetcher {
+	name, source, _ := strings.Cut(provider, ":")
+
 	return JokeFetcher{
 		settings: &JokeFetcherSettings{
 			logger:   logger,
-			provider: provider,
+			name:     provider,
+			provider: name,
+			source:   core.ResolvePath(source),
 			useCache: useCache,
+			cache:    NewCacheStore(logger, ""),
+			options:  options,
 		},
 	}
 }
 
-func (j *JokeFetcher) FetchJokes() (string, error) {
-	if provider := PROVIDERS[j.settings.provider]; provider != nil {
-		provider.Initialize(j.settings)
-		return provider.FetchJokes()
+// FetchJokes returns a joke that passes the filters; ctx cancels the requests of online providers.
+func (j *JokeFetcher) FetchJokes(ctx context.Context) (string, error) {
+	provider := lookupProvider(j.settings.provider)
+	if provider == nil {
+		return "", fmt.Errorf("provider %s not found", j.settings.provider)
 	}
 
-	return "", fmt.Errorf("provider %s not found", j.settings.provider)
+	provider.Initialize(j.settings)
+
+	for range maxFilterAttempts {
+		joke, err := j.fetch(ctx, provider)
+		if err != nil {
+			return "", err
+		}
+
+		if j.settings.options.Allows(joke) {
+			return joke, nil
+		}
+
+		j.settings.logger.Debug("Joke filtered out", "provider", j.settings.provider, "joke", joke)
+	}
+
+	return "", fmt.Errorf("no joke from %s passed the filters after %d attempts", j.settings.provider, maxFilterAttempts)
 }
 
-func ParseJokeHtml(joke string) string {
-	joke = strings.ReplaceAll(joke, "\n", "<br/>")
-	joke = strings.ReplaceAll(joke, "\"", "󰉾")
-	joke = strings.ReplaceAll(joke, "\\", " ")
+func (j *JokeFetcher) fetch(ctx context.Context, provider JokesInterface) (string, error) {
+	if pooled, ok := provider.(PoolProvider); ok && j.settings.useCache && j.settings.cache != nil {
+		return j.settings.cache.Next(ctx, j.settings.cacheKey(), pooled.CacheTTL(), j.poolSource(pooled))
+	}
 
-	if len(joke) > 100 {
-		return joke[:100] + "..."
+	return provider.FetchJokes(ctx)
+}
+
+// Refresh fetches a new batch of a pooled provider into the cache.
+func (j *JokeFetcher) Refresh(ctx context.Context) (*Pool, error) {
+	provider := lookupProvider(j.settings.provider)
+	if provider == nil {
+		return nil, fmt.Errorf("provider %s not found", j.settings.provider)
 	}
 
-	return joke
+	pooled, ok := provider.(PoolProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s is not cached", j.settings.provider)
+	}
+
+	provider.Initialize(j.settings)
+	return j.settings.cache.Refresh(ctx, j.settings.cacheKey(), j.poolSource(pooled))
+}
+
+func (j *JokeFetcher) poolSource(pooled PoolProvider) PoolSource {
+	return PoolSource{Provider: j.settings.name, Options: j.settings.options, Fetch: pooled.FetchPool}
+}
+
+// ProviderInfo describes a registered provider.
+type ProviderInfo struct {
+	Name string `json:"name"`
+	// Kind is "online" for the built-in web providers, "http" for the ones declared in
+	// providers.yaml and "offline" for the local ones.
+	Kind string `json:"kind"`
+	// Cached reports whether the entries are kept in a cache pool.
+	Cached bool `json:"cached"`
+}
+
+// Providers returns the registered providers, sorted by name.
+func Providers() []ProviderInfo {
+	var providers []ProviderInfo
+
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	for name, provider := range PROVIDERS {
+		info := ProviderInfo{Name: name, Kind: "offline"}
+
+		if _, ok := provider.(PoolProvider); ok {
+			info.Kind, info.Cached = "online", true
+		}
+
+		if _, ok := provider.(*httpJoke); ok {
+			info.Kind = "http"
+		}
+
+		providers = append(providers, info)
+	}
+
+	slices.SortFunc(providers, func(a, b ProviderInfo) int { return strings.Compare(a.Name, b.Name) })
+	return providers
 }
 
-func ApplyFormat(joke string, format string) string {
-	return fmt.Sprintf(format, joke)
+// PooledProviders returns the names of the providers whose entries are cached, sorted.
+func PooledProviders() []string {
+	var names []string
+	for _, provider := range Providers() {
+		if provider.Cached {
+			names = append(names, provider.Name)
+		}
+	}
+
+	return names
+}
+
+// randomEntry picks one of the entries returned by a pool fetch.
+func randomEntry(entries []string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no jokes found")
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return entries[r.Intn(len(entries))], nil
 }
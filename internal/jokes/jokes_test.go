@@ -1,7 +1,7 @@
 package jokes
 
 import (
-	"strings"
+	"context"
 	"testing"
 	"time"
 
@@ -13,7 +13,7 @@ type mockJokeProvider struct {
 	joke              string
 }
 
-func (m *mockJokeProvider) FetchJokes() (string, error) {
+func (m *mockJokeProvider) FetchJokes(context.Context) (string, error) {
 	if m.shouldReturnError {
 		return "", &mockError{"mock error"}
 	}
@@ -68,7 +68,7 @@ func TestJokeFetcher(t *testing.T) {
 			logger := core.BuildSilentLogger()
 			fetcher := BuildJokeFetcher(logger, "mock", true)
 
-			joke, err := fetcher.FetchJokes()
+			joke, err := fetcher.FetchJokes(t.Context())
 
 			if err != nil {
 				t.Errorf("Expected no error, got %v", err)
@@ -83,7 +83,7 @@ func TestJokeFetcher(t *testing.T) {
 			logger := core.BuildSilentLogger()
 			fetcher := BuildJokeFetcher(logger, "nonexistent", true)
 
-			joke, err := fetcher.FetchJokes()
+			joke, err := fetcher.FetchJokes(t.Context())
 
 			if err == nil {
 				t.Error("Expected error for nonexistent provider")
@@ -111,7 +111,7 @@ func TestJokeFetcher(t *testing.T) {
 			logger := core.BuildSilentLogger()
 			fetcher := BuildJokeFetcher(logger, "mock_error", true)
 
-			joke, err := fetcher.FetchJokes()
+			joke, err := fetcher.FetchJokes(t.Context())
 
 			if err == nil {
 				t.Error("Expected error from mock provider")
@@ -128,59 +128,6 @@ func TestJokeFetcher(t *testing.T) {
 	})
 }
 
-func TestParseJokeHtml(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "Replace newlines with br tags",
-			input:    "Line 1\nLine 2\nLine 3",
-			expected: "Line 1<br/>Line 2<br/>Line 3",
-		},
-		{
-			name:     "Replace quotes with special character",
-			input:    `Say "Hello World"`,
-			expected: "Say 󰉾Hello World󰉾",
-		},
-		{
-			name:     "Replace backslashes with spaces",
-			input:    "Path\\to\\file",
-			expected: "Path to file",
-		},
-		{
-			name:     "Truncate long jokes",
-			input:    strings.Repeat("a", 150),
-			expected: strings.Repeat("a", 100) + "...",
-		},
-		{
-			name:     "Short joke unchanged",
-			input:    "Short joke",
-			expected: "Short joke",
-		},
-		{
-			name:     "Exactly 100 characters",
-			input:    strings.Repeat("a", 100),
-			expected: strings.Repeat("a", 100),
-		},
-		{
-			name:     "Combined transformations",
-			input:    "Line 1\nSay \"Hello\" with\\backslash",
-			expected: "Line 1<br/>Say 󰉾Hello󰉾 with backslash",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := ParseJokeHtml(tt.input)
-			if result != tt.expected {
-				t.Errorf("ParseJokeHtml() = %v, want %v", result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestApplyFormat(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -204,7 +151,19 @@ func TestApplyFormat(t *testing.T) {
 			name:     "No format placeholder",
 			joke:     "Test joke",
 			format:   "Static text",
-			expected: "Static text%!(EXTRA string=Test joke)",
+			expected: "Static text",
+		},
+		{
+			name:     "Two placeholders",
+			joke:     "Test joke",
+			format:   "%s | %s (100%%)",
+			expected: "Test joke | Test joke (100%)",
+		},
+		{
+			name:     "Template",
+			joke:     "Test joke",
+			format:   "{{.Message | upper}}!",
+			expected: "TEST JOKE!",
 		},
 	}
 
@@ -0,0 +1,171 @@
+package jokes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is one segment of a JSON path: a key, an index or a wildcard.
+type jsonPathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// JSONPath is a compiled expression of the JSON path subset understood by HTTP providers:
+// an optional leading '$', '.key' or '["key"]' members, '[n]' indexes (negative ones count
+// from the end) and '*' or '[*]' wildcards, e.g. "$.data.children[*].data.title".
+type JSONPath struct {
+	expression string
+	steps      []jsonPathStep
+}
+
+// ParseJSONPath compiles expression.
+func ParseJSONPath(expression string) (*JSONPath, error) {
+	path := &JSONPath{expression: expression}
+	rest := strings.TrimPrefix(strings.TrimSpace(expression), "$")
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("invalid JSON path '%s': empty key", expression)
+			}
+
+			path.steps = append(path.steps, jsonPathStep{key: key, wildcard: key == "*"})
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSON path '%s': missing ']'", expression)
+			}
+
+			step, err := parseBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON path '%s': %w", expression, err)
+			}
+
+			path.steps = append(path.steps, step)
+			rest = rest[end+1:]
+		default:
+			if len(path.steps) > 0 {
+				return nil, fmt.Errorf("invalid JSON path '%s': unexpected '%c'", expression, rest[0])
+			}
+			// a path may start with a bare key: "data.title"
+			rest = "." + rest
+		}
+	}
+
+	return path, nil
+}
+
+func parseBracket(content string) (jsonPathStep, error) {
+	content = strings.TrimSpace(content)
+
+	if content == "*" {
+		return jsonPathStep{wildcard: true}, nil
+	}
+
+	if len(content) >= 2 && (content[0] == '"' || content[0] == '\'') && content[len(content)-1] == content[0] {
+		return jsonPathStep{key: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return jsonPathStep{}, fmt.Errorf("invalid index '%s'", content)
+	}
+
+	return jsonPathStep{index: index, isIndex: true}, nil
+}
+
+// String returns the original expression.
+func (p *JSONPath) String() string {
+	return p.expression
+}
+
+// Find returns every value matched by the path in a document decoded by encoding/json.
+func (p *JSONPath) Find(document any) []any {
+	values := []any{document}
+
+	for _, step := range p.steps {
+		var next []any
+
+		for _, value := range values {
+			next = append(next, step.apply(value)...)
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+func (s jsonPathStep) apply(value any) []any {
+	switch node := value.(type) {
+	case map[string]any:
+		if s.wildcard {
+			values := make([]any, 0, len(node))
+			for _, child := range node {
+				values = append(values, child)
+			}
+			return values
+		}
+
+		if child, ok := node[s.key]; ok && !s.isIndex {
+			return []any{child}
+		}
+	case []any:
+		if s.wildcard {
+			return node
+		}
+
+		if s.isIndex {
+			index := s.index
+			if index < 0 {
+				index += len(node)
+			}
+			if index >= 0 && index < len(node) {
+				return []any{node[index]}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Texts returns the matched values as strings. Matched arrays are flattened, so a path
+// that ends at a list yields every item; objects and nulls are skipped.
+func (p *JSONPath) Texts(document any) []string {
+	var texts []string
+
+	var collect func(value any)
+	collect = func(value any) {
+		switch value := value.(type) {
+		case string:
+			if text := strings.TrimSpace(value); text != "" {
+				texts = append(texts, text)
+			}
+		case float64, bool:
+			texts = append(texts, fmt.Sprint(value))
+		case []any:
+			for _, item := range value {
+				collect(item)
+			}
+		}
+	}
+
+	for _, value := range p.Find(document) {
+		collect(value)
+	}
+
+	return texts
+}
@@ -0,0 +1,57 @@
+package jokes
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	var document any
+	json.Unmarshal([]byte(`{
+		"joke": "single",
+		"data": {"children": [
+			{"data": {"title": "first"}},
+			{"data": {"title": "second"}}
+		]},
+		"list": ["a", "b", 3],
+		"odd key": {"value": "quoted"}
+	}`), &document)
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   []string
+	}{
+		{"Member", "$.joke", []string{"single"}},
+		{"BareKey", "joke", []string{"single"}},
+		{"Wildcard", "$.data.children[*].data.title", []string{"first", "second"}},
+		{"Index", "$.data.children[1].data.title", []string{"second"}},
+		{"NegativeIndex", "$.data.children[-1].data.title", []string{"second"}},
+		{"ListFlattened", "$.list", []string{"a", "b", "3"}},
+		{"QuotedKey", `$["odd key"].value`, []string{"quoted"}},
+		{"Missing", "$.nothing.here", nil},
+		{"IndexOnObject", "$.joke[0]", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ParseJSONPath(tt.expression)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if texts := path.Texts(document); !slices.Equal(texts, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, texts)
+			}
+		})
+	}
+}
+
+func TestParseJSONPath_Invalid(t *testing.T) {
+	for _, expression := range []string{"$.data[", "$.data[x]", "$..data"} {
+		if _, err := ParseJSONPath(expression); err == nil {
+			t.Errorf("Expected error for '%s'", expression)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package jokes
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	yaml "gopkg.in/yaml.v3"
+)
+
+//go:embed verses.txt
+var defaultVerses string
+
+// Quote is an entry of a quotes or verses file. Author holds the reference for verses.
+type Quote struct {
+	Text   string `yaml:"text"`
+	Author string `yaml:"author"`
+}
+
+func (q Quote) String() string {
+	if q.Author == "" {
+		return q.Text
+	}
+
+	return fmt.Sprintf("%s — %s", q.Text, q.Author)
+}
+
+// quotesJoke picks a random entry of a local quotes file, ~/.config/ebenezer/quotes.yaml
+// by default.
+type quotesJoke struct{ JokeProvider }
+
+func (j *quotesJoke) FetchJokes(context.Context) (string, error) {
+	path := j.source
+	if path == "" {
+		path = filepath.Join(core.ConfigDir(), "quotes.yaml")
+	}
+
+	quotes, err := LoadQuotes(path)
+	if err != nil {
+		return "", err
+	}
+
+	return randomQuote(quotes).String(), nil
+}
+
+// bibleJoke picks a verse from a local file, or from the bundled verses when no file is
+// given. In daily mode the same verse is returned for the whole day.
+type bibleJoke struct {
+	JokeProvider
+	daily bool
+}
+
+func (j *bibleJoke) FetchJokes(context.Context) (string, error) {
+	var verses []Quote
+	var err error
+
+	if j.source == "" {
+		verses, err = ParseQuotes([]byte(defaultVerses), "verses.txt")
+	} else {
+		verses, err = LoadQuotes(j.source)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if j.daily {
+		return VerseOfTheDay(verses, time.Now()).String(), nil
+	}
+
+	return randomQuote(verses).String(), nil
+}
+
+// VerseOfTheDay returns the verse for the calendar day of now, cycling through verses.
+func VerseOfTheDay(verses []Quote, now time.Time) Quote {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return verses[int(day%int64(len(verses)))]
+}
+
+// LoadQuotes reads a quotes file, see ParseQuotes.
+func LoadQuotes(path string) ([]Quote, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quotes file: %w", err)
+	}
+
+	return ParseQuotes(data, path)
+}
+
+// ParseQuotes decodes quotes. YAML files (.yaml/.yml) hold a list of strings or of
+// {text, author} entries, optionally under a "quotes" key. Other files are plain text with
+// one quote per line, or fortune-style entries separated by "%" lines; "text | author"
+// sets the author and lines starting with '#' are comments.
+func ParseQuotes(data []byte, path string) ([]Quote, error) {
+	var quotes []Quote
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		quotes, err = parseYamlQuotes(data)
+	default:
+		quotes = parseTextQuotes(string(data))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse quotes file '%s': %w", path, err)
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no quotes found in %s", path)
+	}
+
+	return quotes, nil
+}
+
+func parseYamlQuotes(data []byte) ([]Quote, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	node := document.Content[0]
+	if node.Kind == yaml.MappingNode {
+		var wrapper struct {
+			Quotes yaml.Node `yaml:"quotes"`
+		}
+		if err := node.Decode(&wrapper); err != nil {
+			return nil, err
+		}
+		node = &wrapper.Quotes
+	}
+
+	var quotes []Quote
+	for _, item := range node.Content {
+		var quote Quote
+
+		if item.Kind == yaml.ScalarNode {
+			quote.Text = item.Value
+		} else if err := item.Decode(&quote); err != nil {
+			return nil, err
+		}
+
+		if quote.Text = strings.TrimSpace(quote.Text); quote.Text != "" {
+			quotes = append(quotes, quote)
+		}
+	}
+
+	return quotes, nil
+}
+
+func parseTextQuotes(content string) []Quote {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var entries []string
+	if strings.HasPrefix(content, "%\n") || strings.Contains(content, "\n%\n") {
+		entries = strings.Split(content, "\n%\n")
+	} else {
+		entries = strings.Split(content, "\n")
+	}
+
+	var quotes []Quote
+	for _, entry := range entries {
+		entry = strings.TrimSpace(strings.Trim(entry, "%"))
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		text, author, _ := strings.Cut(entry, " | ")
+		quotes = append(quotes, Quote{Text: strings.TrimSpace(text), Author: strings.TrimSpace(author)})
+	}
+
+	return quotes
+}
+
+func randomQuote(quotes []Quote) Quote {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return quotes[r.Intn(len(quotes))]
+}
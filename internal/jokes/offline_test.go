@@ -0,0 +1,125 @@
+package jokes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func TestParseQuotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		expected []Quote
+	}{
+		{
+			name:     "YAML strings",
+			path:     "quotes.yaml",
+			content:  "- Stay hungry\n- Stay foolish\n",
+			expected: []Quote{{Text: "Stay hungry"}, {Text: "Stay foolish"}},
+		},
+		{
+			name:     "YAML entries under quotes",
+			path:     "quotes.yml",
+			content:  "quotes:\n  - text: Simplicity is prerequisite for reliability.\n    author: Dijkstra\n  - Plain one\n",
+			expected: []Quote{{Text: "Simplicity is prerequisite for reliability.", Author: "Dijkstra"}, {Text: "Plain one"}},
+		},
+		{
+			name:     "Text lines with authors and comments",
+			path:     "quotes.txt",
+			content:  "# my quotes\nFirst | Someone\n\nSecond\n",
+			expected: []Quote{{Text: "First", Author: "Someone"}, {Text: "Second"}},
+		},
+		{
+			name:     "Percent separated entries",
+			path:     "quotes",
+			content:  "Line one\nline two\n%\nAnother\n%\n",
+			expected: []Quote{{Text: "Line one\nline two"}, {Text: "Another"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes, err := ParseQuotes([]byte(tt.content), tt.path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(quotes) != len(tt.expected) {
+				t.Fatalf("Expected %d quotes, got %d: %v", len(tt.expected), len(quotes), quotes)
+			}
+
+			for i := range quotes {
+				if quotes[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected[i], quotes[i])
+				}
+			}
+		})
+	}
+
+	if _, err := ParseQuotes([]byte("# nothing\n"), "empty.txt"); err == nil {
+		t.Error("Expected error for a file without quotes")
+	}
+}
+
+func TestVerseOfTheDay(t *testing.T) {
+	verses, err := ParseQuotes([]byte(defaultVerses), "verses.txt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	morning := time.Date(2025, 3, 10, 6, 0, 0, 0, time.Local)
+	evening := time.Date(2025, 3, 10, 23, 0, 0, 0, time.Local)
+	tomorrow := morning.AddDate(0, 0, 1)
+
+	if VerseOfTheDay(verses, morning) != VerseOfTheDay(verses, evening) {
+		t.Error("Expected the same verse for the whole day")
+	}
+
+	if VerseOfTheDay(verses, morning) == VerseOfTheDay(verses, tomorrow) {
+		t.Error("Expected a different verse on the next day")
+	}
+}
+
+func TestOfflineProviders(t *testing.T) {
+	dir := t.TempDir()
+	quotesPath := filepath.Join(dir, "quotes.yaml")
+	os.WriteFile(quotesPath, []byte("- text: Only quote\n  author: Me\n"), 0644)
+
+	tests := []struct {
+		name     string
+		provider string
+		expected string
+	}{
+		{"Quotes file", "quotes:" + quotesPath, "Only quote — Me"},
+		{"Bundled verses", "bible", " — "},
+		{"Verse of the day", "votd", " — "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := BuildJokeFetcher(core.BuildSilentLogger(), tt.provider, false)
+
+			joke, err := fetcher.FetchJokes(t.Context())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !strings.Contains(joke, tt.expected) {
+				t.Errorf("Expected '%s' in '%s'", tt.expected, joke)
+			}
+		})
+	}
+
+	t.Run("Missing quotes file", func(t *testing.T) {
+		fetcher := BuildJokeFetcher(core.BuildSilentLogger(), "quotes:"+filepath.Join(dir, "missing.yaml"), false)
+		if _, err := fetcher.FetchJokes(t.Context()); err == nil {
+			t.Error("Expected error for a missing quotes file")
+		}
+	})
+}
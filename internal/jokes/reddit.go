@@ -1,25 +1,20 @@
 package jokes
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"math/rand"
 	"net/http"
-	"os"
-	"time"
+	"strings"
 )
 
 type redditJoke struct{ JokeProvider }
 
-const (
-	cacheFilePath = "/tmp/reddit_jokes.json"
-)
+const defaultSubreddit = "ProgrammerDadJokes"
 
-type cacheData struct {
-	Jokes     []string  `json:"jokes"`
-	Timestamp time.Time `json:"timestamp"`
-}
+// RedditURL is the reddit site the subreddit listings are read from, replaceable in tests.
+var RedditURL = "https://www.reddit.com"
 
 type redditListing struct {
 	Data struct {
@@ -27,73 +22,78 @@ type redditListing struct {
 			Data struct {
 				Title    string `json:"title"`
 				Selftext string `json:"selftext"`
+				Over18   bool   `json:"over_18"`
+				Stickied bool   `json:"stickied"`
+				Flair    string `json:"link_flair_text"`
 			} `json:"data"`
 		} `json:"children"`
 	} `json:"data"`
 }
 
-func (j *redditJoke) isCacheValid() bool {
-	if !j.useCache {
-		return false
+func (j *redditJoke) FetchJokes(ctx context.Context) (string, error) {
+	return randomEntry(j.FetchPool(ctx))
+}
+
+// subreddits returns the configured subreddits, ProgrammerDadJokes by default.
+func subreddits(options Options) []string {
+	var names []string
+	for _, name := range options.Subreddit {
+		if name = strings.TrimPrefix(strings.TrimSpace(name), "r/"); name != "" {
+			names = append(names, name)
+		}
 	}
 
-	info, err := os.Stat(cacheFilePath)
-	if err != nil {
-		return false
+	if len(names) == 0 {
+		return []string{defaultSubreddit}
 	}
-	return time.Since(info.ModTime()) < cacheDuration
+
+	return names
 }
 
-func (j *redditJoke) loadCache() ([]string, error) {
-	data, err := os.ReadFile(cacheFilePath)
-	if err != nil {
-		return nil, err
-	}
+// redditCacheKey names the pool after the options that change what is fetched, so
+// different subreddits or filters do not share cached posts.
+func redditCacheKey(provider string, options Options) string {
+	var parts []string
 
-	var cache cacheData
-	err = json.Unmarshal(data, &cache)
-	if err != nil {
-		return nil, err
+	if names := subreddits(options); len(names) != 1 || names[0] != defaultSubreddit {
+		parts = append(parts, strings.Join(names, "+"))
 	}
 
-	return cache.Jokes, nil
-}
+	if options.AllowNsfw {
+		parts = append(parts, "nsfw")
+	}
 
-func (j *redditJoke) saveCache(jokes []string) error {
-	cache := cacheData{
-		Jokes:     jokes,
-		Timestamp: time.Now(),
+	if len(options.Flair) > 0 {
+		parts = append(parts, "flair="+strings.Join(options.Flair, "+"))
 	}
 
-	data, err := json.Marshal(cache)
-	if err != nil {
-		return err
+	if len(parts) == 0 {
+		return provider
 	}
 
-	return os.WriteFile(cacheFilePath, data, 0644)
+	return provider + ":" + strings.Join(parts, ",")
 }
 
-func (j *redditJoke) FetchJokes() (string, error) {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+// FetchPool reads the posts of the subreddit listings, merged by reddit with "r/a+b", and
+// drops NSFW, pinned and unwanted flair posts.
+func (j *redditJoke) FetchPool(ctx context.Context) ([]string, error) {
+	names := subreddits(j.options)
+	j.logger.Debug("Fetching new jokes from Reddit", "subreddits", names)
 
-	if j.isCacheValid() {
-		jokes, err := j.loadCache()
-		if err == nil && len(jokes) > 0 {
-			j.logger.Debug("💾 Using cached jokes")
-			return jokes[r.Intn(len(jokes))], nil
-		}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/r/%s.json", strings.TrimSuffix(RedditURL, "/"), strings.Join(names, "+")), nil)
+	if err != nil {
+		return nil, err
 	}
 
-	j.logger.Debug("Fetching new jokes from Reddit")
-
-	resp, err := http.Get("https://www.reddit.com/r/ProgrammerDadJokes.json")
+	client := &http.Client{Timeout: httpProviderTimeout}
+	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch jokes: %s", resp.Status)
+		return nil, fmt.Errorf("failed to fetch jokes: %s", resp.Status)
 	}
 
 	body, _ := io.ReadAll(resp.Body)
@@ -101,11 +101,15 @@ func (j *redditJoke) FetchJokes() (string, error) {
 	var listing redditListing
 	err = json.Unmarshal(body, &listing)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var jokes []string
 	for _, post := range listing.Data.Children {
+		if post.Data.Stickied || (post.Data.Over18 && !j.options.AllowNsfw) || !j.options.allowsFlair(post.Data.Flair) {
+			continue
+		}
+
 		title := post.Data.Title
 		selftext := post.Data.Selftext
 		if selftext != "" {
@@ -115,16 +119,5 @@ func (j *redditJoke) FetchJokes() (string, error) {
 		}
 	}
 
-	if len(jokes) == 0 {
-		return "Sorry 🥺, no jokes found", nil
-	}
-
-	err = j.saveCache(jokes)
-
-	if err != nil {
-		j.logger.Warning("Warning: failed to save cache", "error", err)
-		return "", err
-	}
-
-	return jokes[r.Intn(len(jokes))], nil
+	return jokes, nil
 }
@@ -0,0 +1,77 @@
+package jokes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func TestRedditJoke_FetchPool(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write([]byte(`{"data": {"children": [
+			{"data": {"title": "Rules", "stickied": true}},
+			{"data": {"title": "Clean", "link_flair_text": "Pun"}},
+			{"data": {"title": "Spicy", "over_18": true, "link_flair_text": "Pun"}},
+			{"data": {"title": "Long", "selftext": "story", "link_flair_text": "Meta"}}
+		]}}`))
+	}))
+	defer server.Close()
+
+	defer func(url string) { RedditURL = url }(RedditURL)
+	RedditURL = server.URL
+
+	tests := []struct {
+		name     string
+		options  Options
+		path     string
+		expected []string
+	}{
+		{"Defaults", Options{}, "/r/ProgrammerDadJokes.json", []string{"Clean", "Long\nstory"}},
+		{"AllowNsfw", Options{AllowNsfw: true}, "/r/ProgrammerDadJokes.json", []string{"Clean", "Spicy", "Long\nstory"}},
+		{"Flair", Options{Flair: []string{"pun"}}, "/r/ProgrammerDadJokes.json", []string{"Clean"}},
+		{"Subreddits", Options{Subreddit: []string{"dadjokes", "r/puns"}}, "/r/dadjokes+puns.json", []string{"Clean", "Long\nstory"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joke := &redditJoke{}
+			joke.Initialize(&JokeFetcherSettings{logger: core.BuildSilentLogger(), options: tt.options})
+
+			jokes, err := joke.FetchPool(t.Context())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if requested != tt.path {
+				t.Errorf("Expected request to %s, got %s", tt.path, requested)
+			}
+
+			if !slices.Equal(jokes, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, jokes)
+			}
+		})
+	}
+}
+
+func TestRedditCacheKey(t *testing.T) {
+	tests := []struct {
+		options  Options
+		expected string
+	}{
+		{Options{}, "reddit"},
+		{Options{Subreddit: []string{"ProgrammerDadJokes"}, Blocklist: []string{"x"}}, "reddit"},
+		{Options{Subreddit: []string{"a", "b"}, AllowNsfw: true}, "reddit:a+b,nsfw"},
+		{Options{Flair: []string{"Pun"}}, "reddit:flair=Pun"},
+	}
+
+	for _, tt := range tests {
+		if key := redditCacheKey("reddit", tt.options); key != tt.expected {
+			t.Errorf("Expected cache key '%s', got '%s'", tt.expected, key)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const zeroWidthJoiner = '\u200d'
+
+// Graphemes splits text into user-perceived characters: a base rune with its combining
+// marks, variation selectors, skin tone modifiers and zero-width joiner sequences, so
+// emojis such as 👩‍💻 or flags are never split. It approximates the Unicode extended
+// grapheme cluster rules for the text shown on lock screens and bars.
+func Graphemes(text string) []string {
+	var clusters []string
+
+	for len(text) > 0 {
+		size := clusterSize(text)
+		clusters = append(clusters, text[:size])
+		text = text[size:]
+	}
+
+	return clusters
+}
+
+// Length returns the number of graphemes of text.
+func Length(text string) int {
+	count := 0
+	for len(text) > 0 {
+		text = text[clusterSize(text):]
+		count++
+	}
+
+	return count
+}
+
+func clusterSize(text string) int {
+	first, size := utf8.DecodeRuneInString(text)
+
+	if first == '\r' && strings.HasPrefix(text[size:], "\n") {
+		return size + 1
+	}
+
+	// a flag is a pair of regional indicators
+	if isRegionalIndicator(first) {
+		if next, nextSize := utf8.DecodeRuneInString(text[size:]); isRegionalIndicator(next) {
+			return size + nextSize
+		}
+		return size
+	}
+
+	for size < len(text) {
+		next, nextSize := utf8.DecodeRuneInString(text[size:])
+
+		switch {
+		case next == zeroWidthJoiner:
+			size += nextSize
+			// the joiner glues the following rune to the cluster
+			if size < len(text) {
+				_, joinedSize := utf8.DecodeRuneInString(text[size:])
+				size += joinedSize
+			}
+		case isExtender(next):
+			size += nextSize
+		default:
+			return size
+		}
+	}
+
+	return size
+}
+
+// isExtender reports whether r attaches to the previous rune.
+func isExtender(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc): // combining marks, keycaps
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f, r >= 0xe0100 && r <= 0xe01ef: // variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // emoji tag sequences
+		return true
+	}
+
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// Truncate shortens text to at most limit graphemes, ellipsis included. The cut moves back to
+// the last word boundary when one is close enough, so words are not split in half.
+func Truncate(text string, limit int, ellipsis string) string {
+	if limit <= 0 || Length(text) <= limit {
+		return text
+	}
+
+	keep := limit - Length(ellipsis)
+	if keep <= 0 {
+		return strings.Join(Graphemes(ellipsis)[:limit], "")
+	}
+
+	clusters := Graphemes(text)[:keep]
+
+	// only back off to a nearby space, long words are still cut
+	floor := keep - max(keep/4, 12)
+	for i := len(clusters) - 1; i >= floor && i > 0; i-- {
+		if strings.TrimSpace(clusters[i]) == "" {
+			clusters = clusters[:i]
+			break
+		}
+	}
+
+	cut := strings.TrimRightFunc(strings.Join(clusters, ""), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",;:-–—", r)
+	})
+
+	return cut + ellipsis
+}
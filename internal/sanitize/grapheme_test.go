@@ -0,0 +1,70 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{"ASCII", "hello", 5},
+		{"Accents", "olá", 3},
+		{"CombiningMark", "é", 1},
+		{"ZWJEmoji", "👩‍💻!", 2},
+		{"SkinTone", "👍🏽", 1},
+		{"Flag", "🇧🇷🇵🇹", 2},
+		{"Keycap", "1️⃣", 1},
+		{"CRLF", "a\r\nb", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if length := Length(tt.text); length != tt.expected {
+				t.Errorf("Expected %d graphemes, got %d (%q)", tt.expected, length, Graphemes(tt.text))
+			}
+
+			if joined := strings.Join(Graphemes(tt.text), ""); joined != tt.text {
+				t.Errorf("Expected graphemes to join back to %q, got %q", tt.text, joined)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		max      int
+		expected string
+	}{
+		{"Short", "short joke", 20, "short joke"},
+		{"NoLimit", "anything", 0, "anything"},
+		{"WordBoundary", "Why did the developer go broke", 20, "Why did the…"},
+		{"LongWord", strings.Repeat("a", 150), 100, strings.Repeat("a", 99) + "…"},
+		{"TrailingPunctuation", "first, second third", 9, "first…"},
+		{"EmojiNotSplit", "👩‍💻👩‍💻👩‍💻👩‍💻", 3, "👩‍💻👩‍💻…"},
+		{"EllipsisOnly", "abcdef", 1, "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Truncate(tt.text, tt.max, "…")
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+
+			if !utf8.ValidString(result) {
+				t.Errorf("Expected valid UTF-8, got %q", result)
+			}
+
+			if tt.max > 0 && Length(result) > tt.max {
+				t.Errorf("Expected at most %d graphemes, got %d", tt.max, Length(result))
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package sanitize prepares external text (jokes, quotes, API responses) for the targets
+// that display it. Each target runs a pipeline of steps: control character removal, quote
+// style, grapheme-aware truncation, Pango escaping and newline handling.
+package sanitize
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Step transforms text; a Pipeline runs steps in order.
+type Step func(text string) string
+
+// Pipeline is an ordered list of steps.
+type Pipeline []Step
+
+// Apply runs every step over text.
+func (p Pipeline) Apply(text string) string {
+	for _, step := range p {
+		text = step(text)
+	}
+
+	return text
+}
+
+// NewlineStrategy decides what happens to line breaks.
+type NewlineStrategy string
+
+const (
+	NewlinesKeep  NewlineStrategy = "keep"
+	NewlinesSpace NewlineStrategy = "space"
+	// NewlinesBreak uses the <br/> tag hyprlock renders as a line break.
+	NewlinesBreak NewlineStrategy = "br"
+)
+
+// QuoteStyle decides how quotation marks are written.
+type QuoteStyle string
+
+const (
+	QuotesKeep     QuoteStyle = "keep"
+	QuotesStraight QuoteStyle = "straight"
+	QuotesCurly    QuoteStyle = "curly"
+)
+
+// Target is a place external text is shown.
+type Target string
+
+const (
+	TargetHyprlock Target = "hyprlock"
+	TargetWaybar   Target = "waybar"
+	TargetText     Target = "text"
+)
+
+// Options configures a pipeline.
+type Options struct {
+	// MaxLength is the maximum number of graphemes, ellipsis included; 0 keeps everything.
+	MaxLength int
+	Ellipsis  string
+	Newlines  NewlineStrategy
+	Quotes    QuoteStyle
+	// Pango escapes &, <, >, ' and " for targets that render Pango markup.
+	Pango bool
+}
+
+// Targets holds the default options of each target.
+var Targets = map[Target]Options{
+	TargetHyprlock: {MaxLength: 100, Ellipsis: "…", Newlines: NewlinesBreak, Quotes: QuotesKeep, Pango: true},
+	TargetWaybar:   {Ellipsis: "…", Newlines: NewlinesKeep, Quotes: QuotesKeep, Pango: true},
+	TargetText:     {Ellipsis: "…", Newlines: NewlinesKeep, Quotes: QuotesKeep},
+}
+
+// ForTarget returns the options of target.
+func ForTarget(target Target) (Options, error) {
+	options, ok := Targets[target]
+	if !ok {
+		return Options{}, fmt.Errorf("unknown sanitize target '%s'", target)
+	}
+
+	return options, nil
+}
+
+// New builds the pipeline for options.
+func New(options Options) (Pipeline, error) {
+	pipeline := Pipeline{StripControl}
+
+	switch options.Quotes {
+	case QuotesKeep, "":
+	case QuotesStraight:
+		pipeline = append(pipeline, StraightQuotes)
+	case QuotesCurly:
+		pipeline = append(pipeline, CurlyQuotes)
+	default:
+		return nil, fmt.Errorf("unknown quote style '%s'", options.Quotes)
+	}
+
+	if options.MaxLength > 0 {
+		limit, ellipsis := options.MaxLength, options.Ellipsis
+		pipeline = append(pipeline, func(text string) string { return Truncate(text, limit, ellipsis) })
+	}
+
+	if options.Pango {
+		pipeline = append(pipeline, EscapePango)
+	}
+
+	switch options.Newlines {
+	case NewlinesKeep, "":
+	case NewlinesSpace:
+		pipeline = append(pipeline, func(text string) string { return replaceNewlines(text, " ") })
+	case NewlinesBreak:
+		pipeline = append(pipeline, func(text string) string { return replaceNewlines(text, "<br/>") })
+	default:
+		return nil, fmt.Errorf("unknown newline strategy '%s'", options.Newlines)
+	}
+
+	return pipeline, nil
+}
+
+// Sanitize runs the default pipeline of target over text.
+func Sanitize(target Target, text string) (string, error) {
+	options, err := ForTarget(target)
+	if err != nil {
+		return "", err
+	}
+
+	pipeline, err := New(options)
+	if err != nil {
+		return "", err
+	}
+
+	return pipeline.Apply(text), nil
+}
+
+// StripControl removes control and invisible formatting characters, turns tabs into spaces,
+// normalizes line breaks to '\n' and trims the text. Zero-width joiners are kept for emojis.
+func StripControl(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == zeroWidthJoiner:
+			return r
+		case r == '\t' || r == '\r':
+			return ' '
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r) && !isTag(r):
+			return -1
+		}
+		return r
+	}, text)
+
+	return strings.TrimSpace(text)
+}
+
+func isTag(r rune) bool {
+	return r >= 0xe0020 && r <= 0xe007f
+}
+
+var pangoReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"'", "&#39;",
+	`"`, "&quot;",
+)
+
+// EscapePango escapes the characters Pango markup would interpret.
+func EscapePango(text string) string {
+	return pangoReplacer.Replace(text)
+}
+
+var straightReplacer = strings.NewReplacer("“", `"`, "”", `"`, "„", `"`, "‘", "'", "’", "'", "‚", "'")
+
+// StraightQuotes replaces typographic quotes with ASCII ones.
+func StraightQuotes(text string) string {
+	return straightReplacer.Replace(text)
+}
+
+// CurlyQuotes replaces ASCII quotes with typographic ones, opening after a space or an
+// opening bracket. Apostrophes inside words become ’.
+func CurlyQuotes(text string) string {
+	var builder strings.Builder
+	previous := ' '
+
+	for _, r := range text {
+		opening := unicode.IsSpace(previous) || strings.ContainsRune("([{“‘", previous)
+
+		switch {
+		case r == '"' && opening:
+			builder.WriteRune('“')
+		case r == '"':
+			builder.WriteRune('”')
+		case r == '\'' && opening:
+			builder.WriteRune('‘')
+		case r == '\'':
+			builder.WriteRune('’')
+		default:
+			builder.WriteRune(r)
+		}
+
+		previous = r
+	}
+
+	return builder.String()
+}
+
+func replaceNewlines(text string, separator string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.Join(lines, separator)
+}
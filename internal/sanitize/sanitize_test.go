@@ -0,0 +1,74 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   Target
+		input    string
+		expected string
+	}{
+		{"HyprlockNewlines", TargetHyprlock, "Line 1\nLine 2\r\nLine 3", "Line 1<br/>Line 2<br/>Line 3"},
+		{"HyprlockEscapes", TargetHyprlock, `Say "Hi" & <run>`, "Say &quot;Hi&quot; &amp; &lt;run&gt;"},
+		{"HyprlockBackslash", TargetHyprlock, `Path\to\file`, `Path\to\file`},
+		{"HyprlockTruncates", TargetHyprlock, strings.Repeat("a", 150), strings.Repeat("a", 99) + "…"},
+		{"HyprlockKeepsShort", TargetHyprlock, "Short joke", "Short joke"},
+		{"HyprlockTruncatesBeforeEscaping", TargetHyprlock, strings.Repeat("a", 98) + " & more", strings.Repeat("a", 98) + "…"},
+		{"ControlCharacters", TargetText, "\tbell\a and​ zero width ", "bell and zero width"},
+		{"WaybarKeepsNewlines", TargetWaybar, "a\nb", "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Sanitize(tt.target, tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := Sanitize("unknown", "text"); err == nil {
+		t.Error("Expected error for an unknown target")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		input    string
+		expected string
+	}{
+		{"Straight", Options{Quotes: QuotesStraight}, "“Hi” it’s", `"Hi" it's`},
+		{"Curly", Options{Quotes: QuotesCurly}, `He said "it's 'fine'"`, "He said “it’s ‘fine’”"},
+		{"Space", Options{Newlines: NewlinesSpace}, "one\n  two", "one two"},
+		{"PangoApostrophe", Options{Pango: true}, "it's", "it&#39;s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := New(tt.options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result := pipeline.Apply(tt.input); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	for _, options := range []Options{{Quotes: "fancy"}, {Newlines: "tabs"}} {
+		if _, err := New(options); err == nil {
+			t.Errorf("Expected error for %+v", options)
+		}
+	}
+}
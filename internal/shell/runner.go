@@ -2,6 +2,7 @@ package shell
 
 import (
 	"context"
+	"os"
 	"os/exec"
 	"syscall"
 	"time"
@@ -18,6 +19,8 @@ type RunnerExecutionArgs struct {
 	Setpgid   bool     `json:"setpgid,omitempty"`    // if true, sets cmd.SysProcAttr.Pgid to a new process group ID
 	NilStdout bool     `json:"nil_stdout,omitempty"` // if true, sets cmd.Stdout to nil
 	NilStderr bool     `json:"nil_stderr,omitempty"` // if true, sets cmd.Stderr to nil
+
+	Context context.Context `json:"-"` // if set, cancelling it kills the command before its timeout
 }
 
 // SetDefaults sets default values for RunnerExecutionArgs fields if they are not set.
@@ -30,12 +33,40 @@ func (a *RunnerExecutionArgs) SetDefaults() {
 type Runner interface {
 	// Run executes a command with the provided arguments and environment variables.
 	Run(args RunnerExecutionArgs) (string, error)
-	// Executes a command and returns its combined standard output and standard error.
+	// Executes a command and returns its combined standard output and standard error, also when it fails.
 	RunCombinedOutput(args RunnerExecutionArgs) (string, error)
 	// Executes a command with the provided arguments and environment variables, returning an error if it fails.
 	Start(args RunnerExecutionArgs) (int, error)
 }
 
+// contextRunner runs the commands of Runner with ctx, unless they carry a context of their own.
+type contextRunner struct {
+	Runner
+	ctx context.Context
+}
+
+// WithContext returns a runner whose commands are killed when ctx is done. Started commands
+// are left alone, since they are meant to outlive the caller.
+func WithContext(runner Runner, ctx context.Context) Runner {
+	return &contextRunner{Runner: runner, ctx: ctx}
+}
+
+func (r *contextRunner) Run(args RunnerExecutionArgs) (string, error) {
+	if args.Context == nil {
+		args.Context = r.ctx
+	}
+
+	return r.Runner.Run(args)
+}
+
+func (r *contextRunner) RunCombinedOutput(args RunnerExecutionArgs) (string, error) {
+	if args.Context == nil {
+		args.Context = r.ctx
+	}
+
+	return r.Runner.RunCombinedOutput(args)
+}
+
 type runnerImpl struct {
 	logger core.Logger
 }
@@ -74,7 +105,8 @@ func (r *runnerImpl) RunCombinedOutput(args RunnerExecutionArgs) (string, error)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		r.logger.Error("Command execution failed", "error", err)
-		return "", err
+		// the output usually explains the failure
+		return string(output), err
 	}
 
 	r.logger.Debug("Command combined output", "output", string(output))
@@ -110,7 +142,12 @@ func (r *runnerImpl) Start(args RunnerExecutionArgs) (int, error) {
 }
 
 func (r *runnerImpl) buildContext(args RunnerExecutionArgs) (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), time.Duration(args.Timeout)*time.Second)
+	parent := args.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	return context.WithTimeout(parent, time.Duration(args.Timeout)*time.Second)
 }
 
 func (r *runnerImpl) buildCmd(ctx context.Context, args RunnerExecutionArgs) *exec.Cmd {
@@ -124,7 +161,7 @@ func (r *runnerImpl) buildCmd(ctx context.Context, args RunnerExecutionArgs) *ex
 	}
 
 	if len(args.Env) > 0 {
-		cmd.Env = append(cmd.Env, args.Env...)
+		cmd.Env = append(os.Environ(), args.Env...)
 	}
 
 	if args.NilStdout {
@@ -0,0 +1,28 @@
+package shell
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	runner := WithContext(NewRunner(core.BuildSilentLogger()), ctx)
+
+	start := time.Now()
+	if _, err := runner.Run(RunnerExecutionArgs{Command: "sleep", Args: []string{"5"}}); err == nil {
+		t.Error("Expected the command to be killed")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the command to stop with the context, took %s", elapsed)
+	}
+
+	if _, err := runner.RunCombinedOutput(RunnerExecutionArgs{Command: "true", Context: context.Background()}); err != nil {
+		t.Errorf("Expected a context of the command to win, got %v", err)
+	}
+}
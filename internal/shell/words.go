@@ -0,0 +1,208 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrShellSyntax is returned by Split for pipes, redirections, command lists and substitutions,
+// which only a shell can run.
+var ErrShellSyntax = errors.New("shell syntax requires running through a shell")
+
+// Split breaks command into words the way a POSIX shell does, without running anything:
+// words are separated by blanks, single quotes keep their content as is, double quotes and
+// backslashes escape, $VAR and ${VAR} are expanded with lookup (os.LookupEnv when nil) and a
+// leading ~ stands for the home directory.
+func Split(command string, lookup func(string) (string, bool)) ([]string, error) {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+	)
+
+	flush := func() {
+		if inWord {
+			words = append(words, word.String())
+		}
+		word.Reset()
+		inWord = false
+	}
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", command)
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			inWord = true
+			i = end
+		case r == '"':
+			end, err := splitDoubleQuoted(runes, i+1, &word, lookup)
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, command)
+			}
+			inWord = true
+			i = end
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != '\n' {
+					word.WriteRune(runes[i])
+					inWord = true
+				}
+			}
+		case r == '$':
+			var value strings.Builder
+			next, err := expandVariable(runes, i, &value, lookup)
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, command)
+			}
+			i = next
+
+			// unquoted values are split into words, and empty ones make no word
+			expanded := value.String()
+			for j, field := range strings.FieldsFunc(expanded, isBlank) {
+				if j > 0 || isBlank(rune(expanded[0])) {
+					flush()
+				}
+				word.WriteString(field)
+				inWord = true
+			}
+			if expanded != "" && isBlank(rune(expanded[len(expanded)-1])) {
+				flush()
+			}
+		case r == '~' && !inWord && (i+1 == len(runes) || runes[i+1] == '/' || isBlank(runes[i+1])):
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand ~: %w", err)
+			}
+			word.WriteString(home)
+			inWord = true
+		case strings.ContainsRune("|&;<>()`", r):
+			return nil, fmt.Errorf("%w: %q in %q", ErrShellSyntax, r, command)
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	flush()
+
+	return words, nil
+}
+
+// splitDoubleQuoted writes the content of a double-quoted string starting at start, returning
+// the index of the closing quote.
+func splitDoubleQuoted(runes []rune, start int, word *strings.Builder, lookup func(string) (string, bool)) (int, error) {
+	for i := start; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '"':
+			return i, nil
+		case '\\':
+			// inside double quotes a backslash only escapes these
+			if i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+				i++
+				if runes[i] != '\n' {
+					word.WriteRune(runes[i])
+				}
+				continue
+			}
+			word.WriteRune(r)
+		case '`':
+			return 0, fmt.Errorf("%w: command substitution", ErrShellSyntax)
+		case '$':
+			next, err := expandVariable(runes, i, word, lookup)
+			if err != nil {
+				return 0, err
+			}
+			i = next
+		default:
+			word.WriteRune(r)
+		}
+	}
+
+	return 0, errors.New("unterminated double quote")
+}
+
+// expandVariable writes the value of the variable whose $ is at start, returning the index of
+// its last rune. A $ that starts no variable name is kept.
+func expandVariable(runes []rune, start int, word *strings.Builder, lookup func(string) (string, bool)) (int, error) {
+	i := start + 1
+	if i >= len(runes) {
+		word.WriteRune('$')
+		return start, nil
+	}
+
+	switch {
+	case runes[i] == '{':
+		end := indexRune(runes, i+1, '}')
+		if end < 0 {
+			return 0, errors.New("unterminated ${")
+		}
+		name := string(runes[i+1 : end])
+		if !isVariableName(name) {
+			return 0, fmt.Errorf("%w: ${%s}", ErrShellSyntax, name)
+		}
+		value, _ := lookup(name)
+		word.WriteString(value)
+		return end, nil
+	case runes[i] == '(':
+		return 0, fmt.Errorf("%w: command substitution", ErrShellSyntax)
+	case isVariableRune(runes[i], true):
+		end := i
+		for end+1 < len(runes) && isVariableRune(runes[end+1], false) {
+			end++
+		}
+		value, _ := lookup(string(runes[i : end+1]))
+		word.WriteString(value)
+		return end, nil
+	default:
+		word.WriteRune('$')
+		return start, nil
+	}
+}
+
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isBlank(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
+
+func isVariableRune(r rune, first bool) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || !first && r >= '0' && r <= '9'
+}
+
+func isVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		if !isVariableRune(r, i == 0) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,66 @@
+package shell
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	lookup := func(name string) (string, bool) {
+		value, ok := map[string]string{"NAME": "world", "DIR": "/tmp/my dir"}[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr error
+	}{
+		{"Fields", "notify-send  hello\tworld", []string{"notify-send", "hello", "world"}, nil},
+		{"SingleQuotes", `notify-send 'hello $NAME'`, []string{"notify-send", "hello $NAME"}, nil},
+		{"DoubleQuotes", `notify-send "hello $NAME" "a \"b\" \c"`, []string{"notify-send", "hello world", `a "b" \c`}, nil},
+		{"Adjacent", `echo a'b c'"d"`, []string{"echo", "ab cd"}, nil},
+		{"EmptyQuotes", `echo "" ''`, []string{"echo", "", ""}, nil},
+		{"Backslash", `echo a\ b \$NAME`, []string{"echo", "a b", "$NAME"}, nil},
+		{"Variables", "ls $DIR ${NAME}s $UNSET x$", []string{"ls", "/tmp/my", "dir", "worlds", "x$"}, nil},
+		{"QuotedVariable", `ls "$DIR"`, []string{"ls", "/tmp/my dir"}, nil},
+		{"Home", "ls ~ ~/Pictures a~b '~'", []string{"ls", home, home + "/Pictures", "a~b", "~"}, nil},
+		{"Empty", "  ", nil, nil},
+		{"Pipe", "ls | wc -l", nil, ErrShellSyntax},
+		{"Redirection", "echo hi > /tmp/out", nil, ErrShellSyntax},
+		{"List", "true; false", nil, ErrShellSyntax},
+		{"Substitution", `echo "$(date)"`, nil, ErrShellSyntax},
+		{"Backticks", "echo `date`", nil, ErrShellSyntax},
+		{"QuotedOperators", `echo "a | b" 'c > d'`, []string{"echo", "a | b", "c > d"}, nil},
+		{"UnterminatedSingle", "echo 'hello", nil, errors.New("unterminated")},
+		{"UnterminatedDouble", `echo "hello`, nil, errors.New("unterminated")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.command, lookup)
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("Expected an error, got %q", got)
+				}
+				if errors.Is(tt.wantErr, ErrShellSyntax) && !errors.Is(err, ErrShellSyntax) {
+					t.Errorf("Expected a shell syntax error, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
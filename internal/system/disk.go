@@ -0,0 +1,34 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// DiskUsage is the space of the filesystem holding a path.
+type DiskUsage struct {
+	Path string
+	// Total and Free are in bytes; Free is what unprivileged users may still write.
+	Total uint64
+	Free  uint64
+}
+
+// FreePercent returns the free space in percent of the total.
+func (d DiskUsage) FreePercent() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+
+	return float64(d.Free) / float64(d.Total) * 100
+}
+
+// ReadDisk returns the usage of the filesystem holding path.
+func ReadDisk(path string) (DiskUsage, error) {
+	usage, err := disk.Usage(path)
+	if err != nil {
+		return DiskUsage{}, fmt.Errorf("failed to read disk usage of %s: %w", path, err)
+	}
+
+	return DiskUsage{Path: path, Total: usage.Total, Free: usage.Free}, nil
+}
@@ -0,0 +1,38 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadDisk(t *testing.T) {
+	usage, err := ReadDisk(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if usage.Total == 0 || usage.Free > usage.Total {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+
+	if _, err := ReadDisk(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing path")
+	}
+}
+
+func TestDiskUsage_FreePercent(t *testing.T) {
+	tests := []struct {
+		usage DiskUsage
+		want  float64
+	}{
+		{DiskUsage{Total: 200, Free: 50}, 25},
+		{DiskUsage{Total: 100, Free: 100}, 100},
+		{DiskUsage{}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.usage.FreePercent(); got != tt.want {
+			t.Errorf("Expected %v for %+v, got %v", tt.want, tt.usage, got)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+// IdleTime returns how long the session has been idle according to systemd-logind. Logind
+// only knows it when an idle daemon sets the idle hint (swayidle's idlehint, for example);
+// otherwise the session never looks idle.
+func IdleTime(runner shell.Runner, now time.Time) (time.Duration, error) {
+	session := os.Getenv("XDG_SESSION_ID")
+	if session == "" {
+		session = "auto"
+	}
+
+	output, err := runner.Run(shell.RunnerExecutionArgs{
+		Command: "loginctl",
+		Args:    []string{"show-session", session, "--property=IdleHint", "--property=IdleSinceHint"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the idle hint of session %s: %w", session, err)
+	}
+
+	return ParseIdle(output, now)
+}
+
+// ParseIdle reads the IdleHint and IdleSinceHint properties printed by loginctl.
+func ParseIdle(output string, now time.Time) (time.Duration, error) {
+	properties := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			properties[key] = value
+		}
+	}
+
+	hint, ok := properties["IdleHint"]
+	if !ok {
+		return 0, fmt.Errorf("loginctl did not report IdleHint")
+	}
+
+	if hint != "yes" {
+		return 0, nil
+	}
+
+	since, err := strconv.ParseInt(properties["IdleSinceHint"], 10, 64)
+	if err != nil || since <= 0 {
+		return 0, fmt.Errorf("invalid IdleSinceHint '%s'", properties["IdleSinceHint"])
+	}
+
+	return max(now.Sub(time.UnixMicro(since)), 0), nil
+}
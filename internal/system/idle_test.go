@@ -0,0 +1,47 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+func TestParseIdle(t *testing.T) {
+	now := time.UnixMicro(1_700_000_600_000_000)
+
+	tests := []struct {
+		name      string
+		output    string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{"Idle", "IdleHint=yes\nIdleSinceHint=1700000000000000\n", 10 * time.Minute, false},
+		{"Active", "IdleHint=no\nIdleSinceHint=0\n", 0, false},
+		{"MissingHint", "Name=will\n", 0, true},
+		{"InvalidSince", "IdleHint=yes\nIdleSinceHint=soon\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idle, err := ParseIdle(tt.output, now)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+
+			if idle != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, idle)
+			}
+		})
+	}
+}
+
+func TestIdleTime_LoginctlFails(t *testing.T) {
+	logger := core.BuildSilentLogger()
+	runner := shell.NewRunnerMock(logger, nil, []string{"loginctl"}, nil)
+
+	if _, err := IdleTime(runner, time.Now()); err == nil {
+		t.Error("Expected an error when loginctl fails")
+	}
+}
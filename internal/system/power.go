@@ -0,0 +1,112 @@
+// Package system reads session and hardware state that jobs depend on: power supplies, idle
+// time and disk space.
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PowerSupplyDir is where the kernel exposes the power supplies.
+const PowerSupplyDir = "/sys/class/power_supply"
+
+// Battery is a battery reported by the kernel.
+type Battery struct {
+	Name string
+	// Capacity is the charge in percent.
+	Capacity int
+	// Status is Charging, Discharging, Full or Not charging.
+	Status string
+}
+
+// PowerState tells whether the machine runs on AC power and the state of its batteries.
+type PowerState struct {
+	OnAC      bool
+	Batteries []Battery
+}
+
+// OnBattery reports whether the machine runs on its batteries.
+func (p PowerState) OnBattery() bool {
+	return !p.OnAC
+}
+
+// Capacity returns the average charge of the batteries, or -1 without batteries.
+func (p PowerState) Capacity() int {
+	if len(p.Batteries) == 0 {
+		return -1
+	}
+
+	total := 0
+	for _, battery := range p.Batteries {
+		total += battery.Capacity
+	}
+
+	return total / len(p.Batteries)
+}
+
+// ReadPower reads the power supplies under dir, PowerSupplyDir when empty. A machine without
+// batteries is on AC; without an AC adapter entry, discharging batteries mean battery power.
+func ReadPower(dir string) (PowerState, error) {
+	if dir == "" {
+		dir = PowerSupplyDir
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return PowerState{}, fmt.Errorf("failed to read power supplies: %w", err)
+	}
+
+	var state PowerState
+	adapters, online := 0, false
+
+	for _, entry := range entries {
+		supply := filepath.Join(dir, entry.Name())
+
+		switch readAttribute(supply, "type") {
+		case "Mains", "USB":
+			adapters++
+			if readAttribute(supply, "online") == "1" {
+				online = true
+			}
+		case "Battery":
+			if present := readAttribute(supply, "present"); present == "0" {
+				continue
+			}
+
+			capacity, _ := strconv.Atoi(readAttribute(supply, "capacity"))
+			state.Batteries = append(state.Batteries, Battery{
+				Name:     entry.Name(),
+				Capacity: capacity,
+				Status:   readAttribute(supply, "status"),
+			})
+		}
+	}
+
+	switch {
+	case len(state.Batteries) == 0:
+		state.OnAC = true
+	case adapters > 0:
+		state.OnAC = online
+	default:
+		state.OnAC = true
+		for _, battery := range state.Batteries {
+			if battery.Status == "Discharging" {
+				state.OnAC = false
+			}
+		}
+	}
+
+	return state, nil
+}
+
+func readAttribute(supply string, name string) string {
+	data, err := os.ReadFile(filepath.Join(supply, name))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,77 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSupply(t *testing.T, dir string, name string, attributes map[string]string) {
+	t.Helper()
+
+	supply := filepath.Join(dir, name)
+	if err := os.MkdirAll(supply, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range attributes {
+		os.WriteFile(filepath.Join(supply, key), []byte(value+"\n"), 0644)
+	}
+}
+
+func TestReadPower(t *testing.T) {
+	tests := []struct {
+		name     string
+		supplies map[string]map[string]string
+		onAC     bool
+		capacity int
+	}{
+		{"Desktop", nil, true, -1},
+		{"Plugged", map[string]map[string]string{
+			"AC":   {"type": "Mains", "online": "1"},
+			"BAT0": {"type": "Battery", "capacity": "80", "status": "Charging"},
+		}, true, 80},
+		{"Unplugged", map[string]map[string]string{
+			"AC":   {"type": "Mains", "online": "0"},
+			"BAT0": {"type": "Battery", "capacity": "40", "status": "Discharging"},
+			"BAT1": {"type": "Battery", "capacity": "60", "status": "Discharging"},
+		}, false, 50},
+		{"NoAdapterDischarging", map[string]map[string]string{
+			"BAT0": {"type": "Battery", "capacity": "30", "status": "Discharging"},
+		}, false, 30},
+		{"NoAdapterFull", map[string]map[string]string{
+			"BAT0": {"type": "Battery", "capacity": "100", "status": "Full"},
+		}, true, 100},
+		{"MissingBattery", map[string]map[string]string{
+			"BAT0": {"type": "Battery", "present": "0"},
+		}, true, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, attributes := range tt.supplies {
+				writeSupply(t, dir, name, attributes)
+			}
+
+			state, err := ReadPower(dir)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if state.OnAC != tt.onAC || state.OnBattery() == tt.onAC {
+				t.Errorf("Expected on AC %v, got %+v", tt.onAC, state)
+			}
+
+			if capacity := state.Capacity(); capacity != tt.capacity {
+				t.Errorf("Expected capacity %d, got %d", tt.capacity, capacity)
+			}
+		})
+	}
+}
+
+func TestReadPower_MissingDir(t *testing.T) {
+	if _, err := ReadPower(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,101 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+)
+
+// Color is an opaque sRGB colour.
+type Color struct {
+	R, G, B uint8
+}
+
+// Hex returns the colour as #rrggbb.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// HexBare returns the colour as rrggbb, the form Hyprland expects inside rgb().
+func (c Color) HexBare() string {
+	return fmt.Sprintf("%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Luminance returns the relative luminance in [0, 1].
+func (c Color) Luminance() float64 {
+	return (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+}
+
+// HSL returns hue in degrees and saturation/lightness in [0, 1].
+func (c Color) HSL() (float64, float64, float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maxValue, minValue := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	lightness := (maxValue + minValue) / 2
+
+	if maxValue == minValue {
+		return 0, 0, lightness
+	}
+
+	delta := maxValue - minValue
+	saturation := delta / (1 - math.Abs(2*lightness-1))
+
+	var hue float64
+	switch maxValue {
+	case r:
+		hue = math.Mod((g-b)/delta, 6)
+	case g:
+		hue = (b-r)/delta + 2
+	default:
+		hue = (r-g)/delta + 4
+	}
+
+	hue *= 60
+	if hue < 0 {
+		hue += 360
+	}
+
+	return hue, saturation, lightness
+}
+
+// FromHSL builds a colour from hue in degrees and saturation/lightness in [0, 1].
+func FromHSL(hue, saturation, lightness float64) Color {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := lightness - chroma/2
+
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = chroma, x, 0
+	case hue < 120:
+		r, g, b = x, chroma, 0
+	case hue < 180:
+		r, g, b = 0, chroma, x
+	case hue < 240:
+		r, g, b = 0, x, chroma
+	case hue < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return Color{channel(r + m), channel(g + m), channel(b + m)}
+}
+
+// WithLightness returns the colour with its lightness clamped to [minimum, maximum].
+func (c Color) WithLightness(minimum, maximum float64) Color {
+	hue, saturation, lightness := c.HSL()
+	return FromHSL(hue, saturation, math.Min(math.Max(lightness, minimum), maximum))
+}
+
+func channel(value float64) uint8 {
+	return uint8(math.Round(math.Min(math.Max(value, 0), 1) * 255))
+}
+
+// hueDistance returns the angle between two hues, in [0, 180].
+func hueDistance(a, b float64) float64 {
+	distance := math.Abs(a - b)
+	if distance > 180 {
+		distance = 360 - distance
+	}
+	return distance
+}
@@ -0,0 +1,191 @@
+package theme
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"slices"
+)
+
+const (
+	maxSamplesPerSide = 128
+	maxIterations     = 20
+)
+
+// Swatch is a palette colour and the number of sampled pixels it represents.
+type Swatch struct {
+	Color      Color
+	Population int
+}
+
+// ExtractFile decodes the JPEG or PNG at path and extracts up to count colours.
+func ExtractFile(path string, count int) ([]Swatch, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image '%s': %w", path, err)
+	}
+
+	return Extract(img, count), nil
+}
+
+// Extract clusters the pixels of img with k-means and returns up to count colours, the most
+// common first. Large images are sampled on a grid and centroids are seeded farthest-first
+// from the darkest pixel, so the result is deterministic and small distinct areas survive.
+func Extract(img image.Image, count int) []Swatch {
+	samples := samplePixels(img)
+	if len(samples) == 0 || count <= 0 {
+		return nil
+	}
+
+	centroids := seedCentroids(samples, count)
+	count = len(centroids)
+
+	labels := make([]int, len(samples))
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		changed := false
+		for i, sample := range samples {
+			if nearest := nearestCentroid(centroids, sample); nearest != labels[i] {
+				labels[i] = nearest
+				changed = true
+			}
+		}
+
+		sums := make([][3]float64, count)
+		counts := make([]int, count)
+		for i, sample := range samples {
+			for channel := range sample {
+				sums[labels[i]][channel] += sample[channel]
+			}
+			counts[labels[i]]++
+		}
+
+		for i := range centroids {
+			if counts[i] == 0 {
+				continue
+			}
+			for channel := range centroids[i] {
+				centroids[i][channel] = sums[i][channel] / float64(counts[i])
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	populations := make([]int, count)
+	for _, label := range labels {
+		populations[label]++
+	}
+
+	var swatches []Swatch
+	for i, centroid := range centroids {
+		if populations[i] == 0 {
+			continue
+		}
+		swatches = append(swatches, Swatch{
+			Color:      Color{channel(centroid[0] / 255), channel(centroid[1] / 255), channel(centroid[2] / 255)},
+			Population: populations[i],
+		})
+	}
+
+	slices.SortStableFunc(swatches, func(a, b Swatch) int {
+		return b.Population - a.Population
+	})
+
+	return swatches
+}
+
+func samplePixels(img image.Image) [][3]float64 {
+	bounds := img.Bounds()
+	stepX := max(1, bounds.Dx()/maxSamplesPerSide)
+	stepY := max(1, bounds.Dy()/maxSamplesPerSide)
+
+	var samples [][3]float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			samples = append(samples, [3]float64{float64(r >> 8), float64(g >> 8), float64(b >> 8)})
+		}
+	}
+
+	return samples
+}
+
+func seedCentroids(samples [][3]float64, count int) [][3]float64 {
+	darkest := slices.MinFunc(samples, func(a, b [3]float64) int {
+		return compareFloat(luminance(a), luminance(b))
+	})
+	centroids := [][3]float64{darkest}
+
+	distances := make([]float64, len(samples))
+	for i, sample := range samples {
+		distances[i] = distance(sample, darkest)
+	}
+
+	for len(centroids) < count {
+		farthest := 0
+		for i := range samples {
+			if distances[i] > distances[farthest] {
+				farthest = i
+			}
+		}
+
+		if distances[farthest] == 0 {
+			break
+		}
+
+		centroids = append(centroids, samples[farthest])
+		for i, sample := range samples {
+			distances[i] = min(distances[i], distance(sample, samples[farthest]))
+		}
+	}
+
+	return centroids
+}
+
+func nearestCentroid(centroids [][3]float64, sample [3]float64) int {
+	nearest, best := 0, -1.0
+	for i, centroid := range centroids {
+		if d := distance(sample, centroid); best < 0 || d < best {
+			nearest, best = i, d
+		}
+	}
+
+	return nearest
+}
+
+func distance(a, b [3]float64) float64 {
+	total := 0.0
+	for channel := range a {
+		delta := a[channel] - b[channel]
+		total += delta * delta
+	}
+	return total
+}
+
+func luminance(sample [3]float64) float64 {
+	return 0.2126*sample[0] + 0.7152*sample[1] + 0.0722*sample[2]
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,88 @@
+package theme
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildImage paints horizontal bands, each covering the given share of the height.
+func buildImage(bands []color.RGBA, shares []int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+
+	y := 0
+	for i, band := range bands {
+		for row := 0; row < shares[i]; row++ {
+			for x := 0; x < 200; x++ {
+				img.Set(x, y, band)
+			}
+			y++
+		}
+	}
+
+	return img
+}
+
+func TestExtract(t *testing.T) {
+	img := buildImage(
+		[]color.RGBA{{20, 30, 60, 255}, {230, 80, 40, 255}, {240, 240, 235, 255}},
+		[]int{60, 30, 10},
+	)
+
+	swatches := Extract(img, 3)
+	if len(swatches) != 3 {
+		t.Fatalf("Expected 3 swatches, got %d", len(swatches))
+	}
+
+	expected := []string{"#141e3c", "#e65028", "#f0f0eb"}
+	for i, hex := range expected {
+		if swatches[i].Color.Hex() != hex {
+			t.Errorf("Expected swatch %d to be %s, got %s", i, hex, swatches[i].Color.Hex())
+		}
+	}
+
+	if swatches[0].Population <= swatches[1].Population {
+		t.Errorf("Expected swatches sorted by population, got %+v", swatches)
+	}
+}
+
+func TestExtract_FewerColorsThanRequested(t *testing.T) {
+	img := buildImage([]color.RGBA{{10, 10, 10, 255}}, []int{100})
+
+	swatches := Extract(img, 8)
+	if len(swatches) != 1 || swatches[0].Color.Hex() != "#0a0a0a" {
+		t.Errorf("Expected a single #0a0a0a swatch, got %+v", swatches)
+	}
+}
+
+func TestExtractFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wallpaper.png")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create image: %v", err)
+	}
+	if err := png.Encode(file, buildImage([]color.RGBA{{200, 0, 0, 255}}, []int{100})); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+	file.Close()
+
+	swatches, err := ExtractFile(path, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if swatches[0].Color.Hex() != "#c80000" {
+		t.Errorf("Expected #c80000, got %s", swatches[0].Color.Hex())
+	}
+
+	notImage := filepath.Join(dir, "notes.png")
+	os.WriteFile(notImage, []byte("not an image"), 0644)
+	if _, err := ExtractFile(notImage, 4); err == nil {
+		t.Error("Expected error for an invalid image")
+	}
+}
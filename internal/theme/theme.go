@@ -0,0 +1,83 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	hueRed         = 0.0
+	hueYellow      = 55.0
+	maxHueDistance = 30.0
+	minSaturation  = 0.25
+)
+
+// Theme is the set of colours derived from a wallpaper. Low, Medium and High are the
+// widget severity colours.
+type Theme struct {
+	Background Color
+	Foreground Color
+	Accent     Color
+	Low        Color
+	Medium     Color
+	High       Color
+	Palette    []Color
+}
+
+// NewTheme derives a theme from swatches sorted by population: the darkest colour becomes
+// the background, the lightest the foreground and the most saturated common colour the
+// accent. Severity colours keep their yellow/red meaning, borrowing a palette colour of a
+// close hue when there is one.
+func NewTheme(swatches []Swatch) (Theme, error) {
+	if len(swatches) == 0 {
+		return Theme{}, fmt.Errorf("empty palette")
+	}
+
+	total := 0
+	for _, swatch := range swatches {
+		total += swatch.Population
+	}
+
+	theme := Theme{}
+	darkest, lightest := swatches[0].Color, swatches[0].Color
+	bestAccent := -1.0
+
+	for _, swatch := range swatches {
+		color := swatch.Color
+		theme.Palette = append(theme.Palette, color)
+
+		if color.Luminance() < darkest.Luminance() {
+			darkest = color
+		}
+		if color.Luminance() > lightest.Luminance() {
+			lightest = color
+		}
+
+		_, saturation, lightness := color.HSL()
+		score := saturation * (1 - math.Abs(lightness-0.5)) * math.Sqrt(float64(swatch.Population)/float64(total))
+		if score > bestAccent {
+			bestAccent = score
+			theme.Accent = color
+		}
+	}
+
+	theme.Background = darkest.WithLightness(0, 0.15)
+	theme.Foreground = lightest.WithLightness(0.85, 1)
+	theme.Accent = theme.Accent.WithLightness(0.5, 0.75)
+	theme.Low = theme.Foreground
+	theme.Medium = severityColor(swatches, hueYellow)
+	theme.High = severityColor(swatches, hueRed)
+
+	return theme, nil
+}
+
+func severityColor(swatches []Swatch, targetHue float64) Color {
+	for _, swatch := range swatches {
+		hue, saturation, _ := swatch.Color.HSL()
+		if saturation >= minSaturation && hueDistance(hue, targetHue) <= maxHueDistance {
+			return swatch.Color.WithLightness(0.55, 0.7)
+		}
+	}
+
+	return FromHSL(targetHue, 0.9, 0.6)
+}
@@ -0,0 +1,71 @@
+package theme
+
+import (
+	"math"
+	"testing"
+)
+
+func TestColor_HSL(t *testing.T) {
+	tests := []struct {
+		color      Color
+		hue        float64
+		saturation float64
+		lightness  float64
+	}{
+		{Color{255, 0, 0}, 0, 1, 0.5},
+		{Color{0, 255, 0}, 120, 1, 0.5},
+		{Color{0, 0, 255}, 240, 1, 0.5},
+		{Color{128, 128, 128}, 0, 0, 0.502},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.color.Hex(), func(t *testing.T) {
+			hue, saturation, lightness := tt.color.HSL()
+			if math.Abs(hue-tt.hue) > 0.5 || math.Abs(saturation-tt.saturation) > 0.01 || math.Abs(lightness-tt.lightness) > 0.01 {
+				t.Errorf("Expected (%v, %v, %v), got (%v, %v, %v)", tt.hue, tt.saturation, tt.lightness, hue, saturation, lightness)
+			}
+
+			if back := FromHSL(hue, saturation, lightness); back != tt.color {
+				t.Errorf("Expected round trip to %s, got %s", tt.color.Hex(), back.Hex())
+			}
+		})
+	}
+}
+
+func TestNewTheme(t *testing.T) {
+	swatches := []Swatch{
+		{Color{20, 30, 60}, 600},
+		{Color{40, 120, 200}, 300},
+		{Color{230, 80, 40}, 150},
+		{Color{240, 240, 235}, 100},
+	}
+
+	theme, err := NewTheme(swatches)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if theme.Background.Luminance() >= theme.Foreground.Luminance() {
+		t.Errorf("Expected a dark background and light foreground, got %s/%s", theme.Background.Hex(), theme.Foreground.Hex())
+	}
+
+	if hue, _, _ := theme.Accent.HSL(); hueDistance(hue, 210) > 10 {
+		t.Errorf("Expected the blue swatch as accent, got %s", theme.Accent.Hex())
+	}
+
+	if hue, _, _ := theme.High.HSL(); hueDistance(hue, hueRed) > maxHueDistance {
+		t.Errorf("Expected a red high colour, got %s", theme.High.Hex())
+	}
+
+	if hue, _, _ := theme.Medium.HSL(); hueDistance(hue, hueYellow) > maxHueDistance {
+		t.Errorf("Expected a yellow medium colour, got %s", theme.Medium.Hex())
+	}
+
+	if len(theme.Palette) != len(swatches) {
+		t.Errorf("Expected %d palette colours, got %d", len(swatches), len(theme.Palette))
+	}
+
+	if _, err := NewTheme(nil); err == nil {
+		t.Error("Expected error for an empty palette")
+	}
+}
@@ -0,0 +1,160 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+const widgetColorsFile = "colors.json"
+
+// Output lists the files a theme is written to. Empty paths are skipped.
+type Output struct {
+	Waybar   string
+	Hyprland string
+	Widgets  string
+}
+
+// WidgetColors is the severity palette read by the widgets.
+type WidgetColors struct {
+	Low    string `json:"low"`
+	Medium string `json:"medium"`
+	High   string `json:"high"`
+}
+
+// DefaultOutput returns the Waybar fragment, Hyprland colors.conf and widget colours paths.
+func DefaultOutput() Output {
+	return Output{
+		Waybar:   core.ResolvePath("~/.config/waybar/ebenezer-colors.css"),
+		Hyprland: core.ResolvePath("~/.config/hypr/colors.conf"),
+		Widgets:  WidgetColorsPath(),
+	}
+}
+
+// WidgetColorsPath returns the widget colours file under the config directory.
+func WidgetColorsPath() string {
+	return filepath.Join(core.ConfigDir(), widgetColorsFile)
+}
+
+// LoadWidgetColors reads the widget colours written by Write.
+func LoadWidgetColors(path string) (WidgetColors, error) {
+	var colors WidgetColors
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return colors, err
+	}
+
+	if err := json.Unmarshal(data, &colors); err != nil {
+		return colors, fmt.Errorf("failed to decode widget colors '%s': %w", path, err)
+	}
+
+	return colors, nil
+}
+
+// Write renders the theme into every configured output file through writer.
+func Write(writer configfile.Writer, theme Theme, output Output) error {
+	widgets, err := json.MarshalIndent(WidgetColors{
+		Low:    theme.Low.Hex(),
+		Medium: theme.Medium.Hex(),
+		High:   theme.High.Hex(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{output.Waybar, []byte(theme.WaybarCSS())},
+		{output.Hyprland, []byte(theme.HyprlandConf())},
+		{output.Widgets, widgets},
+	}
+
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+
+		if err := writer.Write(file.path, file.content); err != nil {
+			return fmt.Errorf("failed to write theme file '%s': %w", file.path, err)
+		}
+	}
+
+	return nil
+}
+
+// WaybarCSS returns a stylesheet fragment defining the theme colours and overriding the
+// colours of assets/style.css. Import it after the main stylesheet.
+func (t Theme) WaybarCSS() string {
+	var css strings.Builder
+
+	css.WriteString("/* Generated by ebenezer-cli from the current wallpaper */\n")
+	for _, color := range t.named() {
+		fmt.Fprintf(&css, "@define-color %s %s;\n", color.name, color.value.Hex())
+	}
+
+	css.WriteString(`
+#custom-ebenezer-cpu.low,
+#custom-ebenezer-memory.low,
+#custom-ebenezer-temperature.low {
+    color: @low;
+}
+
+#custom-ebenezer-cpu.medium,
+#custom-ebenezer-memory.medium,
+#custom-ebenezer-temperature.medium {
+    color: @medium;
+}
+
+#custom-ebenezer-cpu.high,
+#custom-ebenezer-memory.high,
+#custom-ebenezer-temperature.high {
+    color: @high;
+}
+
+#custom-ebenezer-logo {
+    color: @accent;
+}
+`)
+
+	return css.String()
+}
+
+// HyprlandConf returns Hyprland variables for the theme, e.g. `col.active_border = $accent`.
+func (t Theme) HyprlandConf() string {
+	var conf strings.Builder
+
+	conf.WriteString("# Generated by ebenezer-cli from the current wallpaper\n")
+	for _, color := range t.named() {
+		fmt.Fprintf(&conf, "$%s = rgb(%s)\n", color.name, color.value.HexBare())
+	}
+
+	for i, color := range t.Palette {
+		fmt.Fprintf(&conf, "$color%d = rgb(%s)\n", i, color.HexBare())
+	}
+
+	return conf.String()
+}
+
+type namedColor struct {
+	name  string
+	value Color
+}
+
+func (t Theme) named() []namedColor {
+	return []namedColor{
+		{"background", t.Background},
+		{"foreground", t.Foreground},
+		{"accent", t.Accent},
+		{"low", t.Low},
+		{"medium", t.Medium},
+		{"high", t.High},
+	}
+}
@@ -0,0 +1,75 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	"github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+func testWriter(t *testing.T) configfile.Writer {
+	return configfile.NewWriterWithOptions(core.BuildSilentLogger(), configfile.Options{BackupDir: t.TempDir()})
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	output := Output{
+		Waybar:   filepath.Join(dir, "waybar", "colors.css"),
+		Hyprland: filepath.Join(dir, "hypr", "colors.conf"),
+		Widgets:  filepath.Join(dir, "ebenezer", "colors.json"),
+	}
+
+	theme := Theme{
+		Background: Color{10, 10, 20},
+		Foreground: Color{240, 240, 240},
+		Accent:     Color{80, 120, 250},
+		Low:        Color{240, 240, 240},
+		Medium:     Color{240, 220, 60},
+		High:       Color{230, 60, 50},
+		Palette:    []Color{{10, 10, 20}, {80, 120, 250}},
+	}
+
+	if err := Write(testWriter(t), theme, output); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	css, _ := os.ReadFile(output.Waybar)
+	for _, expected := range []string{"@define-color accent #5078fa;", "#custom-ebenezer-memory.high", "color: @high;"} {
+		if !strings.Contains(string(css), expected) {
+			t.Errorf("Expected CSS to contain '%s', got:\n%s", expected, css)
+		}
+	}
+
+	conf, _ := os.ReadFile(output.Hyprland)
+	for _, expected := range []string{"$accent = rgb(5078fa)", "$color1 = rgb(5078fa)"} {
+		if !strings.Contains(string(conf), expected) {
+			t.Errorf("Expected colors.conf to contain '%s', got:\n%s", expected, conf)
+		}
+	}
+
+	colors, err := LoadWidgetColors(output.Widgets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if colors != (WidgetColors{Low: "#f0f0f0", Medium: "#f0dc3c", High: "#e63c32"}) {
+		t.Errorf("Unexpected widget colors: %+v", colors)
+	}
+}
+
+func TestWrite_SkipsEmptyPaths(t *testing.T) {
+	dir := t.TempDir()
+	output := Output{Widgets: filepath.Join(dir, "colors.json")}
+
+	if err := Write(testWriter(t), Theme{}, output); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the widget colors file, got %d entries", len(entries))
+	}
+}
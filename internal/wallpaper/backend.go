@@ -0,0 +1,111 @@
+package wallpaper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/process"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+const BackendAuto = "auto"
+
+// Backend displays images on monitors.
+type Backend interface {
+	// Name returns the backend identifier used by --backend.
+	Name() string
+	// Apply displays every assignment, replacing the current wallpapers.
+	Apply(assignments []Assignment) error
+}
+
+// replacingBackend is implemented by backends that clear every output missing from Apply,
+// so they are handed the current wallpaper of the other monitors as well.
+type replacingBackend interface {
+	ReplacesAll() bool
+}
+
+// Transition holds the swww transition options. Zero values keep the swww defaults.
+type Transition struct {
+	Type     string  `yaml:"type"`
+	Step     int     `yaml:"step"`
+	Fps      int     `yaml:"fps"`
+	Duration float64 `yaml:"duration"`
+}
+
+type BackendOptions struct {
+	Logger         core.Logger
+	Shell          shell.Runner
+	IPC            hyprland.IPCClient
+	ProcessManager process.ProcessManager
+	Transition     Transition
+}
+
+type BackendBuilder func(options BackendOptions) Backend
+
+var BACKENDS = map[string]BackendBuilder{
+	"hyprpaper": newHyprpaperBackend,
+	"swww":      newSwwwBackend,
+	"swaybg":    newSwaybgBackend,
+	"feh":       newFehBackend,
+}
+
+// BackendNames returns the registered backends in a stable order.
+func BackendNames() []string {
+	names := make([]string, 0, len(BACKENDS))
+	for name := range BACKENDS {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// BuildBackend returns the backend registered as name, detecting it from the session for "auto".
+func BuildBackend(name string, options BackendOptions) (Backend, error) {
+	if name == "" || name == BackendAuto {
+		name = DetectBackend(DetectSession(), options.ProcessManager)
+		options.Logger.Debug("Detected wallpaper backend", "backend", name)
+	}
+
+	builder, exists := BACKENDS[name]
+	if !exists {
+		return nil, fmt.Errorf("unsupported wallpaper backend '%s' (available: %s)", name, strings.Join(BackendNames(), ", "))
+	}
+
+	return builder(options), nil
+}
+
+// DetectBackend picks a backend for session, preferring a running swww daemon.
+func DetectBackend(session string, processManager process.ProcessManager) string {
+	if processManager != nil && processManager.IsProcessRunning("swww-daemon") {
+		return "swww"
+	}
+
+	switch session {
+	case SessionHyprland:
+		return "hyprpaper"
+	case SessionSway, SessionWayland:
+		return "swaybg"
+	default:
+		return "feh"
+	}
+}
+
+// New builds a Wallpaper for backendName using the monitors of the running session.
+// hyprpaper only runs under Hyprland, so it always lists monitors through the Hyprland IPC.
+func New(backendName string, options BackendOptions) (*Wallpaper, error) {
+	backend, err := BuildBackend(backendName, options)
+	if err != nil {
+		return nil, err
+	}
+
+	session := DetectSession()
+	if backend.Name() == "hyprpaper" {
+		session = SessionHyprland
+	}
+
+	return NewWallpaper(options.Logger, backend, NewMonitorLister(session, options)), nil
+}
@@ -0,0 +1,157 @@
+package wallpaper
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/process"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+func TestDetectBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  string
+		running  []string
+		expected string
+	}{
+		{"Hyprland", SessionHyprland, nil, "hyprpaper"},
+		{"Sway", SessionSway, nil, "swaybg"},
+		{"GenericWayland", SessionWayland, nil, "swaybg"},
+		{"X11", SessionX11, nil, "feh"},
+		{"SwwwDaemonRunning", SessionHyprland, []string{"swww-daemon"}, "swww"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processManager := process.NewProcessManagerMock(tt.running, nil)
+			if backend := DetectBackend(tt.session, processManager); backend != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, backend)
+			}
+		})
+	}
+}
+
+func TestDetectSession(t *testing.T) {
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+	t.Setenv("SWAYSOCK", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("DISPLAY", ":0")
+
+	if session := DetectSession(); session != SessionX11 {
+		t.Errorf("Expected x11, got %s", session)
+	}
+
+	t.Setenv("SWAYSOCK", "/run/user/1000/sway-ipc.sock")
+	if session := DetectSession(); session != SessionSway {
+		t.Errorf("Expected sway, got %s", session)
+	}
+
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "sig")
+	if session := DetectSession(); session != SessionHyprland {
+		t.Errorf("Expected hyprland, got %s", session)
+	}
+}
+
+func TestBuildBackend(t *testing.T) {
+	options := BackendOptions{Logger: core.BuildSilentLogger()}
+
+	for _, name := range BackendNames() {
+		backend, err := BuildBackend(name, options)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", name, err)
+			continue
+		}
+
+		if backend.Name() != name {
+			t.Errorf("Expected backend %s, got %s", name, backend.Name())
+		}
+	}
+
+	_, err := BuildBackend("xwallpaper", options)
+	if err == nil || !strings.Contains(err.Error(), "unsupported wallpaper backend") {
+		t.Errorf("Expected unsupported backend error, got %v", err)
+	}
+}
+
+func TestHyprpaperBackend_Apply(t *testing.T) {
+	logger := core.BuildSilentLogger()
+	client := hyprland.NewIPCClientMock(logger, nil)
+
+	err := newHyprpaperBackend(BackendOptions{Logger: logger, IPC: client}).Apply([]Assignment{
+		{Monitor: "eDP-1", Image: "/a.png"},
+		{Monitor: "DP-1", Image: "/b.png"},
+		{Monitor: "DP-2", Image: "/a.png"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"hyprpaper unload all",
+		"hyprpaper preload /a.png",
+		"hyprpaper preload /b.png",
+		"hyprpaper wallpaper eDP-1,/a.png",
+		"hyprpaper wallpaper DP-1,/b.png",
+		"hyprpaper wallpaper DP-2,/a.png",
+	}
+
+	if requests := client.(*hyprland.IPCClientMockImpl).Requests; !slices.Equal(requests, expected) {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestSwwwBackend_buildArgs(t *testing.T) {
+	backend := &swwwBackend{transition: Transition{Type: "grow", Step: 90, Fps: 60, Duration: 1.5}}
+
+	args := backend.buildArgs(Assignment{Monitor: "DP-1", Image: "/a.png"})
+	expected := []string{"img", "--outputs", "DP-1", "--transition-type", "grow", "--transition-step", "90",
+		"--transition-fps", "60", "--transition-duration", "1.5", "/a.png"}
+
+	if !slices.Equal(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+
+	args = (&swwwBackend{}).buildArgs(Assignment{Image: "/a.png"})
+	if !slices.Equal(args, []string{"img", "/a.png"}) {
+		t.Errorf("Expected defaults to be omitted, got %v", args)
+	}
+}
+
+func TestSwaybgBackend_buildArgs(t *testing.T) {
+	args := (&swaybgBackend{}).buildArgs([]Assignment{{Monitor: "DP-1", Image: "/a.png"}, {Image: "/b.png"}})
+	expected := []string{"-o", "DP-1", "-i", "/a.png", "-m", "fill", "-o", "*", "-i", "/b.png", "-m", "fill"}
+
+	if !slices.Equal(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+}
+
+func TestFehBackend_Apply(t *testing.T) {
+	logger := core.BuildSilentLogger()
+
+	backend := newFehBackend(BackendOptions{Logger: logger, Shell: shell.NewRunnerMock(logger, []string{"feh"}, nil, nil)})
+	if err := backend.Apply([]Assignment{{Image: "/a.png"}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	backend = newFehBackend(BackendOptions{Logger: logger, Shell: shell.NewRunnerMock(logger, nil, []string{"feh"}, nil)})
+	if err := backend.Apply([]Assignment{{Image: "/a.png"}}); err == nil {
+		t.Error("Expected error when feh fails")
+	}
+}
+
+func TestParseXrandrMonitors(t *testing.T) {
+	output := `Monitors: 2
+ 0: +HDMI-1 2560/597x1440/336+1920+0  HDMI-1
+ 1: +*eDP-1 1920/344x1080/193+0+0  eDP-1
+`
+
+	monitors := parseXrandrMonitors(output)
+	if !slices.Equal(monitors, []string{"eDP-1", "HDMI-1"}) {
+		t.Errorf("Expected primary monitor first, got %v", monitors)
+	}
+}
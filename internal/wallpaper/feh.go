@@ -0,0 +1,39 @@
+package wallpaper
+
+import (
+	"fmt"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+type fehBackend struct {
+	logger core.Logger
+	shell  shell.Runner
+}
+
+func newFehBackend(options BackendOptions) Backend {
+	return &fehBackend{
+		logger: options.Logger,
+		shell:  options.Shell,
+	}
+}
+
+func (b *fehBackend) Name() string {
+	return "feh"
+}
+
+// Apply hands every image to a single feh call; feh maps them to the Xinerama screens
+// in order, which matches the order the monitors were listed in.
+func (b *fehBackend) Apply(assignments []Assignment) error {
+	args := []string{"--no-fehbg", "--bg-fill"}
+	for _, assignment := range assignments {
+		args = append(args, assignment.Image)
+	}
+
+	if _, err := b.shell.Run(shell.RunnerExecutionArgs{Command: "feh", Args: args}); err != nil {
+		return fmt.Errorf("failed to set wallpaper with feh: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/williampsena/ebenezer-cli/internal/configfile"
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+const (
+	rotationFile   = "wallpaper.json"
+	historyLimit   = 100
+	defaultWeight  = 1.0
+	favoriteWeight = 3.0
+)
+
+// HistoryEntry records an image shown on a monitor.
+type HistoryEntry struct {
+	Monitor string    `json:"monitor"`
+	Image   string    `json:"image"`
+	Time    time.Time `json:"time"`
+}
+
+// RotationState is the persisted wallpaper rotation. Shown holds the images displayed in the
+// current cycle, which are skipped until every other image of the pool has been shown.
+type RotationState struct {
+	Current   map[string]string  `json:"current"`
+	History   []HistoryEntry     `json:"history"`
+	Shown     []string           `json:"shown"`
+	Favorites []string           `json:"favorites"`
+	Banned    []string           `json:"banned"`
+	Weights   map[string]float64 `json:"weights"`
+}
+
+// Rotation picks wallpapers without repeating them and remembers what was shown.
+type Rotation struct {
+	path  string
+	state RotationState
+	rnd   *rand.Rand
+}
+
+// DefaultRotationPath returns the rotation state file under the XDG state directory.
+func DefaultRotationPath() string {
+	return filepath.Join(core.StateDir(), rotationFile)
+}
+
+// LoadRotation reads the rotation state from path, starting empty when the file does not exist.
+func LoadRotation(path string) (*Rotation, error) {
+	rotation := &Rotation{
+		path: path,
+		rnd:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read rotation state: %w", err)
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &rotation.state); err != nil {
+			return nil, fmt.Errorf("failed to decode rotation state %s: %w", path, err)
+		}
+	}
+
+	if rotation.state.Current == nil {
+		rotation.state.Current = map[string]string{}
+	}
+	if rotation.state.Weights == nil {
+		rotation.state.Weights = map[string]float64{}
+	}
+
+	return rotation, nil
+}
+
+// Save writes the rotation state back to its file.
+func (r *Rotation) Save() error {
+	data, err := json.MarshalIndent(r.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := configfile.WriteAtomic(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rotation state: %w", err)
+	}
+
+	return nil
+}
+
+// State returns a copy of the rotation state.
+func (r *Rotation) State() RotationState {
+	return r.state
+}
+
+// Pick chooses an image from pool that was not shown in the current cycle and is not in
+// taken, weighting favorites higher. Banned images are never picked. Once every image
+// was shown the cycle starts over, avoiding the images currently on screen when possible.
+func (r *Rotation) Pick(pool []string, taken map[string]bool) (string, error) {
+	allowed := slices.DeleteFunc(slices.Clone(pool), func(image string) bool {
+		return slices.Contains(r.state.Banned, image)
+	})
+
+	if len(allowed) == 0 {
+		return "", fmt.Errorf("every wallpaper in the pool is banned")
+	}
+
+	candidates := r.filter(allowed, func(image string) bool {
+		return taken[image] || slices.Contains(r.state.Shown, image)
+	})
+
+	if len(candidates) == 0 {
+		r.state.Shown = slices.DeleteFunc(r.state.Shown, func(image string) bool {
+			return slices.Contains(allowed, image)
+		})
+
+		candidates = r.filter(allowed, func(image string) bool {
+			return taken[image] || r.isCurrent(image)
+		})
+	}
+
+	if len(candidates) == 0 {
+		candidates = r.filter(allowed, func(image string) bool { return taken[image] })
+	}
+
+	if len(candidates) == 0 {
+		candidates = allowed
+	}
+
+	return r.weightedChoice(candidates), nil
+}
+
+// Record marks the assignments as shown and appends them to the history.
+func (r *Rotation) Record(assignments []Assignment) {
+	now := time.Now()
+
+	for _, assignment := range assignments {
+		if !slices.Contains(r.state.Shown, assignment.Image) {
+			r.state.Shown = append(r.state.Shown, assignment.Image)
+		}
+
+		r.state.Current[assignment.Monitor] = assignment.Image
+		r.state.History = append(r.state.History, HistoryEntry{
+			Monitor: assignment.Monitor,
+			Image:   assignment.Image,
+			Time:    now,
+		})
+	}
+
+	if len(r.state.History) > historyLimit {
+		r.state.History = r.state.History[len(r.state.History)-historyLimit:]
+	}
+}
+
+// Prune forgets the images for which exists returns false: they leave the history, the
+// current cycle, the favorites, the banned list and the weights. It returns how many
+// images were forgotten.
+func (r *Rotation) Prune(exists func(image string) bool) int {
+	missing := map[string]bool{}
+	check := func(image string) bool {
+		if _, checked := missing[image]; !checked {
+			missing[image] = !exists(image)
+		}
+		return missing[image]
+	}
+
+	r.state.History = slices.DeleteFunc(r.state.History, func(entry HistoryEntry) bool { return check(entry.Image) })
+	r.state.Shown = slices.DeleteFunc(r.state.Shown, check)
+	r.state.Favorites = slices.DeleteFunc(r.state.Favorites, check)
+	r.state.Banned = slices.DeleteFunc(r.state.Banned, check)
+	maps.DeleteFunc(r.state.Weights, func(image string, _ float64) bool { return check(image) })
+
+	forgotten := 0
+	for _, gone := range missing {
+		if gone {
+			forgotten++
+		}
+	}
+
+	return forgotten
+}
+
+// Current returns the image displayed on monitor.
+func (r *Rotation) Current(monitor string) (string, bool) {
+	image, ok := r.state.Current[monitor]
+	return image, ok && image != ""
+}
+
+// Previous drops the latest history entry of monitor and returns the image shown before it.
+func (r *Rotation) Previous(monitor string) (string, bool) {
+	latest := r.lastEntry(monitor, len(r.state.History))
+	if latest < 0 {
+		return "", false
+	}
+
+	previous := r.lastEntry(monitor, latest)
+	if previous < 0 {
+		return "", false
+	}
+
+	image := r.state.History[previous].Image
+	r.state.History = slices.Delete(r.state.History, latest, latest+1)
+	r.state.Current[monitor] = image
+
+	return image, true
+}
+
+// Favorite adds image to the favorites with weight, or removes it when remove is set.
+// A zero weight keeps the default favorite weight.
+func (r *Rotation) Favorite(image string, weight float64, remove bool) {
+	r.state.Favorites = slices.DeleteFunc(r.state.Favorites, func(item string) bool { return item == image })
+	delete(r.state.Weights, image)
+
+	if remove {
+		return
+	}
+
+	r.state.Favorites = append(r.state.Favorites, image)
+	if weight > 0 {
+		r.state.Weights[image] = weight
+	}
+}
+
+// Ban excludes image from the rotation, or allows it again when remove is set.
+func (r *Rotation) Ban(image string, remove bool) {
+	r.state.Banned = slices.DeleteFunc(r.state.Banned, func(item string) bool { return item == image })
+
+	if !remove {
+		r.state.Banned = append(r.state.Banned, image)
+	}
+}
+
+// Weight returns the selection weight of image.
+func (r *Rotation) Weight(image string) float64 {
+	if weight, ok := r.state.Weights[image]; ok && weight > 0 {
+		return weight
+	}
+
+	if slices.Contains(r.state.Favorites, image) {
+		return favoriteWeight
+	}
+
+	return defaultWeight
+}
+
+func (r *Rotation) filter(images []string, skip func(string) bool) []string {
+	var result []string
+	for _, image := range images {
+		if !skip(image) {
+			result = append(result, image)
+		}
+	}
+
+	return result
+}
+
+func (r *Rotation) isCurrent(image string) bool {
+	for _, current := range r.state.Current {
+		if current == image {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Rotation) lastEntry(monitor string, before int) int {
+	for i := before - 1; i >= 0; i-- {
+		if r.state.History[i].Monitor == monitor {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (r *Rotation) weightedChoice(images []string) string {
+	total := 0.0
+	for _, image := range images {
+		total += r.Weight(image)
+	}
+
+	target := r.rnd.Float64() * total
+	for _, image := range images {
+		target -= r.Weight(image)
+		if target < 0 {
+			return image
+		}
+	}
+
+	return images[len(images)-1]
+}
@@ -0,0 +1,195 @@
+package wallpaper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+)
+
+func TestRotation_Pick(t *testing.T) {
+	pool := []string{"a.png", "b.png", "c.png"}
+
+	t.Run("NoRepeatUntilPoolIsExhausted", func(t *testing.T) {
+		rotation, _ := LoadRotation(filepath.Join(t.TempDir(), "state.json"))
+
+		seen := map[string]bool{}
+		for range pool {
+			image, err := rotation.Pick(pool, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if seen[image] {
+				t.Fatalf("Image %s picked twice in the same cycle", image)
+			}
+			seen[image] = true
+			rotation.Record([]Assignment{{Monitor: "eDP-1", Image: image}})
+		}
+
+		current, _ := rotation.Current("eDP-1")
+		image, err := rotation.Pick(pool, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if image == current {
+			t.Errorf("Expected the new cycle to avoid the current image %s", current)
+		}
+	})
+
+	t.Run("SkipsBanned", func(t *testing.T) {
+		rotation, _ := LoadRotation(filepath.Join(t.TempDir(), "state.json"))
+		rotation.Ban("a.png", false)
+		rotation.Ban("b.png", false)
+
+		for range 10 {
+			if image, _ := rotation.Pick(pool, nil); image != "c.png" {
+				t.Fatalf("Expected c.png, got %s", image)
+			}
+		}
+
+		rotation.Ban("c.png", false)
+		if _, err := rotation.Pick(pool, nil); err == nil {
+			t.Error("Expected error when every image is banned")
+		}
+	})
+
+	t.Run("SkipsTaken", func(t *testing.T) {
+		rotation, _ := LoadRotation(filepath.Join(t.TempDir(), "state.json"))
+		image, _ := rotation.Pick([]string{"a.png", "b.png"}, map[string]bool{"a.png": true})
+		if image != "b.png" {
+			t.Errorf("Expected b.png, got %s", image)
+		}
+	})
+}
+
+func TestRotation_Weight(t *testing.T) {
+	rotation, _ := LoadRotation(filepath.Join(t.TempDir(), "state.json"))
+	rotation.Favorite("fav.png", 0, false)
+	rotation.Favorite("heavy.png", 10, false)
+
+	tests := []struct {
+		image    string
+		expected float64
+	}{
+		{"plain.png", defaultWeight},
+		{"fav.png", favoriteWeight},
+		{"heavy.png", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if weight := rotation.Weight(tt.image); weight != tt.expected {
+				t.Errorf("Expected weight %v, got %v", tt.expected, weight)
+			}
+		})
+	}
+
+	rotation.Favorite("heavy.png", 0, true)
+	if weight := rotation.Weight("heavy.png"); weight != defaultWeight {
+		t.Errorf("Expected removed favorite to fall back to %v, got %v", defaultWeight, weight)
+	}
+}
+
+func TestRotation_Previous(t *testing.T) {
+	rotation, _ := LoadRotation(filepath.Join(t.TempDir(), "state.json"))
+	rotation.Record([]Assignment{{Monitor: "eDP-1", Image: "a.png"}, {Monitor: "DP-1", Image: "x.png"}})
+	rotation.Record([]Assignment{{Monitor: "eDP-1", Image: "b.png"}})
+	rotation.Record([]Assignment{{Monitor: "eDP-1", Image: "c.png"}})
+
+	for _, expected := range []string{"b.png", "a.png"} {
+		image, ok := rotation.Previous("eDP-1")
+		if !ok || image != expected {
+			t.Fatalf("Expected %s, got %s (%v)", expected, image, ok)
+		}
+	}
+
+	if _, ok := rotation.Previous("eDP-1"); ok {
+		t.Error("Expected no wallpaper before the first one")
+	}
+
+	if current, _ := rotation.Current("DP-1"); current != "x.png" {
+		t.Errorf("Expected DP-1 to keep x.png, got %s", current)
+	}
+}
+
+func TestRotation_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	rotation, err := LoadRotation(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rotation.Record([]Assignment{{Monitor: "eDP-1", Image: "a.png"}})
+	rotation.Favorite("a.png", 2, false)
+
+	if err := rotation.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := LoadRotation(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if current, _ := loaded.Current("eDP-1"); current != "a.png" {
+		t.Errorf("Expected a.png, got %s", current)
+	}
+	if loaded.Weight("a.png") != 2 {
+		t.Errorf("Expected weight 2, got %v", loaded.Weight("a.png"))
+	}
+}
+
+func TestWallpaper_RotationRecordsApplied(t *testing.T) {
+	wallpaper, client := buildTestWallpaper([]hyprland.Monitor{{Name: "eDP-1"}})
+
+	dir := t.TempDir()
+	createImages(t, dir, "a.png", "b.png")
+
+	rotation, _ := LoadRotation(filepath.Join(t.TempDir(), "state.json"))
+	wallpaper.SetRotation(rotation)
+
+	if err := wallpaper.SetAllWallpapers(dir, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first, _ := rotation.Current("eDP-1")
+
+	if err := wallpaper.SetAllWallpapers(dir, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := rotation.Current("eDP-1")
+
+	if first == second {
+		t.Errorf("Expected a different wallpaper on the second run, got %s twice", first)
+	}
+
+	if err := wallpaper.Previous([]string{"eDP-1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	last := client.Requests[len(client.Requests)-1]
+	if last != "hyprpaper wallpaper eDP-1,"+first {
+		t.Errorf("Expected previous wallpaper %s to be restored, got %s", first, last)
+	}
+}
+
+func TestRotation_Prune(t *testing.T) {
+	rotation, _ := LoadRotation(filepath.Join(t.TempDir(), "state.json"))
+	rotation.Record([]Assignment{{Monitor: "eDP-1", Image: "gone.png"}})
+	rotation.Record([]Assignment{{Monitor: "eDP-1", Image: "kept.png"}})
+	rotation.Favorite("gone.png", 5, false)
+	rotation.Ban("deleted.png", false)
+
+	forgotten := rotation.Prune(func(image string) bool { return image == "kept.png" })
+	if forgotten != 2 {
+		t.Errorf("Expected 2 images to be forgotten, got %d", forgotten)
+	}
+
+	state := rotation.State()
+	if len(state.History) != 1 || state.History[0].Image != "kept.png" {
+		t.Errorf("Unexpected history: %v", state.History)
+	}
+
+	if len(state.Shown) != 1 || len(state.Favorites) != 0 || len(state.Banned) != 0 || len(state.Weights) != 0 {
+		t.Errorf("Expected the missing images to be forgotten, got %+v", state)
+	}
+}
@@ -0,0 +1,51 @@
+package wallpaper
+
+import (
+	"fmt"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+)
+
+type hyprpaperBackend struct {
+	logger core.Logger
+	ipc    hyprland.IPCClient
+}
+
+func newHyprpaperBackend(options BackendOptions) Backend {
+	return &hyprpaperBackend{
+		logger: options.Logger,
+		ipc:    options.IPC,
+	}
+}
+
+func (b *hyprpaperBackend) Name() string {
+	return "hyprpaper"
+}
+
+// Apply unloads the unused images, preloads every assigned image once and then
+// displays each one on its monitor.
+func (b *hyprpaperBackend) Apply(assignments []Assignment) error {
+	requests := []string{"unload all"}
+	preloaded := map[string]bool{}
+
+	for _, assignment := range assignments {
+		if !preloaded[assignment.Image] {
+			preloaded[assignment.Image] = true
+			requests = append(requests, fmt.Sprintf("preload %s", assignment.Image))
+		}
+	}
+
+	for _, assignment := range assignments {
+		requests = append(requests, fmt.Sprintf("wallpaper %s,%s", assignment.Monitor, assignment.Image))
+	}
+
+	for _, request := range requests {
+		if err := b.ipc.Hyprpaper(request); err != nil {
+			b.logger.Error("Error sending hyprpaper request", "request", request, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+const (
+	SessionHyprland = "hyprland"
+	SessionSway     = "sway"
+	SessionWayland  = "wayland"
+	SessionX11      = "x11"
+	SessionUnknown  = "unknown"
+)
+
+// DetectSession guesses the running graphical session from the environment.
+func DetectSession() string {
+	switch {
+	case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		return SessionHyprland
+	case os.Getenv("SWAYSOCK") != "":
+		return SessionSway
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		return SessionWayland
+	case os.Getenv("DISPLAY") != "":
+		return SessionX11
+	default:
+		return SessionUnknown
+	}
+}
+
+// MonitorLister lists the outputs of the running session.
+type MonitorLister interface {
+	// Monitors returns the names of the enabled outputs.
+	Monitors() ([]string, error)
+	// FocusedMonitor returns the name of the focused output.
+	FocusedMonitor() (string, error)
+}
+
+// NewMonitorLister returns the lister matching session. Sessions without a way to list
+// outputs report a single unnamed monitor, which backends treat as "every output".
+func NewMonitorLister(session string, options BackendOptions) MonitorLister {
+	switch session {
+	case SessionHyprland:
+		return &hyprlandMonitors{ipc: options.IPC}
+	case SessionSway:
+		return &swayMonitors{shell: options.Shell}
+	case SessionX11:
+		return &xrandrMonitors{shell: options.Shell}
+	default:
+		return &allMonitors{}
+	}
+}
+
+type hyprlandMonitors struct {
+	ipc hyprland.IPCClient
+}
+
+func (m *hyprlandMonitors) Monitors() ([]string, error) {
+	monitors, err := m.ipc.Monitors()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, monitor := range monitors {
+		if !monitor.Disabled {
+			names = append(names, monitor.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (m *hyprlandMonitors) FocusedMonitor() (string, error) {
+	monitors, err := m.ipc.Monitors()
+	if err != nil {
+		return "", err
+	}
+
+	if len(monitors) == 0 {
+		return "", fmt.Errorf("no monitors found")
+	}
+
+	for _, monitor := range monitors {
+		if monitor.Focused {
+			return monitor.Name, nil
+		}
+	}
+
+	return monitors[0].Name, nil
+}
+
+type swayOutput struct {
+	Name    string `json:"name"`
+	Active  bool   `json:"active"`
+	Focused bool   `json:"focused"`
+}
+
+type swayMonitors struct {
+	shell shell.Runner
+}
+
+func (m *swayMonitors) outputs() ([]swayOutput, error) {
+	output, err := m.shell.Run(shell.RunnerExecutionArgs{
+		Command: "swaymsg",
+		Args:    []string{"-t", "get_outputs", "-r"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []swayOutput
+	if err := json.Unmarshal([]byte(output), &outputs); err != nil {
+		return nil, fmt.Errorf("failed to decode sway outputs: %w", err)
+	}
+
+	return outputs, nil
+}
+
+func (m *swayMonitors) Monitors() ([]string, error) {
+	outputs, err := m.outputs()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, output := range outputs {
+		if output.Active {
+			names = append(names, output.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (m *swayMonitors) FocusedMonitor() (string, error) {
+	outputs, err := m.outputs()
+	if err != nil {
+		return "", err
+	}
+
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("no monitors found")
+	}
+
+	for _, output := range outputs {
+		if output.Focused {
+			return output.Name, nil
+		}
+	}
+
+	return outputs[0].Name, nil
+}
+
+type xrandrMonitors struct {
+	shell shell.Runner
+}
+
+// Monitors parses `xrandr --listmonitors`, whose lines look like ` 0: +*eDP-1 1920/344x1080/193+0+0  eDP-1`.
+func (m *xrandrMonitors) Monitors() ([]string, error) {
+	output, err := m.shell.Run(shell.RunnerExecutionArgs{
+		Command: "xrandr",
+		Args:    []string{"--listmonitors"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseXrandrMonitors(output), nil
+}
+
+// FocusedMonitor returns the primary output, X11 has no notion of focus.
+func (m *xrandrMonitors) FocusedMonitor() (string, error) {
+	names, err := m.Monitors()
+	if err != nil {
+		return "", err
+	}
+
+	if len(names) == 0 {
+		return "", fmt.Errorf("no monitors found")
+	}
+
+	return names[0], nil
+}
+
+func parseXrandrMonitors(output string) []string {
+	var primary, others []string
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+
+		name := fields[len(fields)-1]
+		if strings.HasPrefix(fields[1], "+*") {
+			primary = append(primary, name)
+		} else {
+			others = append(others, name)
+		}
+	}
+
+	return append(primary, others...)
+}
+
+type allMonitors struct{}
+
+func (m *allMonitors) Monitors() ([]string, error) {
+	return []string{""}, nil
+}
+
+func (m *allMonitors) FocusedMonitor() (string, error) {
+	return "", nil
+}
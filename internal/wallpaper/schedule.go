@@ -0,0 +1,118 @@
+package wallpaper
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Schedule maps daily time windows to wallpaper directories. The first set whose window
+// contains the current time wins; Default is used when none does.
+type Schedule struct {
+	Latitude  *float64      `yaml:"latitude"`
+	Longitude *float64      `yaml:"longitude"`
+	Default   string        `yaml:"default"`
+	Sets      []ScheduleSet `yaml:"sets"`
+}
+
+type ScheduleSet struct {
+	Name   string `yaml:"name"`
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Path   string `yaml:"path"`
+	window core.TimeWindow
+}
+
+// LoadSchedule reads and validates the schedule file at path.
+func LoadSchedule(path string) (*Schedule, error) {
+	data, err := os.ReadFile(core.ResolvePath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallpaper schedule '%s': %w", path, err)
+	}
+
+	return ParseSchedule(data)
+}
+
+// ParseSchedule decodes a schedule, checking every window and that sun based windows
+// come with coordinates.
+func ParseSchedule(data []byte) (*Schedule, error) {
+	var schedule Schedule
+	if err := yaml.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal wallpaper schedule: %w", err)
+	}
+
+	if len(schedule.Sets) == 0 {
+		return nil, fmt.Errorf("no wallpaper sets found in schedule")
+	}
+
+	for i := range schedule.Sets {
+		set := &schedule.Sets[i]
+		if set.Name == "" {
+			set.Name = fmt.Sprintf("set-%d", i+1)
+		}
+
+		if set.Path == "" {
+			return nil, fmt.Errorf("wallpaper set '%s': path is required", set.Name)
+		}
+
+		window, err := core.ParseTimeWindow(set.From, set.To)
+		if err != nil {
+			return nil, fmt.Errorf("wallpaper set '%s': %w", set.Name, err)
+		}
+
+		if window.UsesSun() && schedule.coordinates() == nil {
+			return nil, fmt.Errorf("wallpaper set '%s': sunrise and sunset require latitude and longitude", set.Name)
+		}
+
+		set.window = window
+	}
+
+	return &schedule, nil
+}
+
+// SetAt returns the set active at now, nil when only the default applies.
+func (s *Schedule) SetAt(now time.Time) (*ScheduleSet, error) {
+	for i := range s.Sets {
+		set := &s.Sets[i]
+
+		active, err := set.window.Contains(now, s.coordinates())
+		if err != nil {
+			return nil, fmt.Errorf("wallpaper set '%s': %w", set.Name, err)
+		}
+
+		if active {
+			return set, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// PathAt returns the wallpaper path active at now, or fallback when no set matches and
+// the schedule has no default.
+func (s *Schedule) PathAt(now time.Time, fallback string) (string, error) {
+	set, err := s.SetAt(now)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case set != nil:
+		return core.ResolvePath(set.Path), nil
+	case s.Default != "":
+		return core.ResolvePath(s.Default), nil
+	default:
+		return fallback, nil
+	}
+}
+
+func (s *Schedule) coordinates() *core.Coordinates {
+	if s.Latitude == nil || s.Longitude == nil {
+		return nil
+	}
+
+	return &core.Coordinates{Latitude: *s.Latitude, Longitude: *s.Longitude}
+}
@@ -0,0 +1,99 @@
+package wallpaper
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		errorMsg string
+	}{
+		{"NoSets", "sets: []", "no wallpaper sets found"},
+		{"MissingPath", "sets:\n  - name: day\n    from: \"06:00\"\n    to: \"18:00\"", "path is required"},
+		{"InvalidTime", "sets:\n  - name: day\n    from: noon\n    to: \"18:00\"\n    path: /tmp", "invalid time 'noon'"},
+		{"SunWithoutCoordinates", "sets:\n  - name: night\n    from: sunset\n    to: sunrise\n    path: /tmp", "require latitude and longitude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing '%s', got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestSchedule_PathAt(t *testing.T) {
+	schedule, err := ParseSchedule([]byte(`
+latitude: 0
+longitude: 0
+sets:
+  - name: morning
+    from: sunrise
+    to: "11:00"
+    path: /wallpapers/morning
+  - name: day
+    from: "11:00"
+    to: sunset
+    path: /wallpapers/day
+  - name: night
+    from: sunset
+    to: sunrise
+    path: /wallpapers/night
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		hour     int
+		expected string
+	}{
+		{8, "/wallpapers/morning"},
+		{14, "/wallpapers/day"},
+		{22, "/wallpapers/night"},
+		{3, "/wallpapers/night"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			path, err := schedule.PathAt(time.Date(2024, 3, 20, tt.hour, 0, 0, 0, time.UTC), "/fallback")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if path != tt.expected {
+				t.Errorf("Expected %s at %02d:00, got %s", tt.expected, tt.hour, path)
+			}
+		})
+	}
+}
+
+func TestSchedule_PathAtFallback(t *testing.T) {
+	schedule, err := ParseSchedule([]byte(`
+sets:
+  - name: work
+    from: "09:00"
+    to: "17:00"
+    path: /wallpapers/work
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	evening := time.Date(2024, 3, 20, 20, 0, 0, 0, time.UTC)
+
+	if path, _ := schedule.PathAt(evening, "/fallback"); path != "/fallback" {
+		t.Errorf("Expected fallback path, got %s", path)
+	}
+
+	schedule.Default = "/wallpapers/default"
+	if path, _ := schedule.PathAt(evening, "/fallback"); path != "/wallpapers/default" {
+		t.Errorf("Expected default path, got %s", path)
+	}
+}
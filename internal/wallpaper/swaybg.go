@@ -0,0 +1,70 @@
+package wallpaper
+
+import (
+	"fmt"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/process"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+type swaybgBackend struct {
+	logger         core.Logger
+	shell          shell.Runner
+	processManager process.ProcessManager
+}
+
+func newSwaybgBackend(options BackendOptions) Backend {
+	return &swaybgBackend{
+		logger:         options.Logger,
+		shell:          options.Shell,
+		processManager: options.ProcessManager,
+	}
+}
+
+func (b *swaybgBackend) Name() string {
+	return "swaybg"
+}
+
+// ReplacesAll reports that swaybg only shows the images it was started with.
+func (b *swaybgBackend) ReplacesAll() bool {
+	return true
+}
+
+// Apply replaces the running swaybg with a new instance showing every assignment,
+// since swaybg cannot change its images once started.
+func (b *swaybgBackend) Apply(assignments []Assignment) error {
+	if b.processManager != nil && b.processManager.IsProcessRunning("swaybg") {
+		if err := b.processManager.KillProcess("swaybg"); err != nil {
+			b.logger.Warning("Failed to stop swaybg", "error", err)
+		}
+	}
+
+	_, err := b.shell.Start(shell.RunnerExecutionArgs{
+		Command:   "swaybg",
+		Args:      b.buildArgs(assignments),
+		Setpgid:   true,
+		NilStdout: true,
+		NilStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start swaybg: %w", err)
+	}
+
+	return nil
+}
+
+func (b *swaybgBackend) buildArgs(assignments []Assignment) []string {
+	var args []string
+
+	for _, assignment := range assignments {
+		output := assignment.Monitor
+		if output == "" {
+			output = "*"
+		}
+
+		args = append(args, "-o", output, "-i", assignment.Image, "-m", "fill")
+	}
+
+	return args
+}
@@ -0,0 +1,97 @@
+package wallpaper
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/process"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+type swwwBackend struct {
+	logger         core.Logger
+	shell          shell.Runner
+	processManager process.ProcessManager
+	transition     Transition
+}
+
+func newSwwwBackend(options BackendOptions) Backend {
+	return &swwwBackend{
+		logger:         options.Logger,
+		shell:          options.Shell,
+		processManager: options.ProcessManager,
+		transition:     options.Transition,
+	}
+}
+
+func (b *swwwBackend) Name() string {
+	return "swww"
+}
+
+func (b *swwwBackend) Apply(assignments []Assignment) error {
+	if err := b.ensureDaemon(); err != nil {
+		return err
+	}
+
+	for _, assignment := range assignments {
+		_, err := b.shell.Run(shell.RunnerExecutionArgs{
+			Command: "swww",
+			Args:    b.buildArgs(assignment),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set wallpaper with swww: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (b *swwwBackend) buildArgs(assignment Assignment) []string {
+	args := []string{"img"}
+
+	if assignment.Monitor != "" {
+		args = append(args, "--outputs", assignment.Monitor)
+	}
+
+	if b.transition.Type != "" {
+		args = append(args, "--transition-type", b.transition.Type)
+	}
+
+	if b.transition.Step > 0 {
+		args = append(args, "--transition-step", strconv.Itoa(b.transition.Step))
+	}
+
+	if b.transition.Fps > 0 {
+		args = append(args, "--transition-fps", strconv.Itoa(b.transition.Fps))
+	}
+
+	if b.transition.Duration > 0 {
+		args = append(args, "--transition-duration", strconv.FormatFloat(b.transition.Duration, 'f', -1, 64))
+	}
+
+	return append(args, assignment.Image)
+}
+
+func (b *swwwBackend) ensureDaemon() error {
+	if b.processManager == nil || b.processManager.IsProcessRunning("swww-daemon") {
+		return nil
+	}
+
+	b.logger.Info("🚀 Starting swww-daemon")
+
+	_, err := b.shell.Start(shell.RunnerExecutionArgs{
+		Command:   "swww-daemon",
+		Setpgid:   true,
+		NilStdout: true,
+		NilStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start swww-daemon: %w", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
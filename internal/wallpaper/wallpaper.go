@@ -0,0 +1,292 @@
+package wallpaper
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	core "github.com/williampsena/ebenezer-cli/internal/core"
+)
+
+// Assignment pairs a monitor with the image it should display. An empty monitor means
+// every output, for sessions where monitors cannot be listed.
+type Assignment struct {
+	Monitor string
+	Image   string
+}
+
+type Wallpaper struct {
+	logger   core.Logger
+	backend  Backend
+	monitors MonitorLister
+	rotation *Rotation
+}
+
+func NewWallpaper(logger core.Logger, backend Backend, monitors MonitorLister) *Wallpaper {
+	return &Wallpaper{
+		logger:   logger,
+		backend:  backend,
+		monitors: monitors,
+	}
+}
+
+// Backend returns the backend used to display wallpapers.
+func (w *Wallpaper) Backend() Backend {
+	return w.backend
+}
+
+// SetRotation makes the random selection follow rotation and records every applied wallpaper.
+func (w *Wallpaper) SetRotation(rotation *Rotation) {
+	w.rotation = rotation
+}
+
+// Rotation returns the rotation state, nil when the selection is purely random.
+func (w *Wallpaper) Rotation() *Rotation {
+	return w.rotation
+}
+
+// SetWallpaper sets a random image from wallpaperPath on the focused monitor.
+func (w *Wallpaper) SetWallpaper(wallpaperPath string) error {
+	monitorName, err := w.GetMonitorName()
+	if err != nil {
+		w.logger.Error("Error setting monitor name", "error", err)
+		return err
+	}
+
+	assignments, err := w.AssignWallpapers([]string{monitorName}, wallpaperPath, nil)
+	if err != nil {
+		return err
+	}
+
+	return w.Apply(assignments)
+}
+
+// SetAllWallpapers sets a distinct random image on every connected monitor. Monitors listed in
+// monitorPaths pick from their own directory, the others from wallpaperPath.
+func (w *Wallpaper) SetAllWallpapers(wallpaperPath string, monitorPaths map[string]string) error {
+	monitors, err := w.GetMonitorNames()
+	if err != nil {
+		return err
+	}
+
+	assignments, err := w.AssignWallpapers(monitors, wallpaperPath, monitorPaths)
+	if err != nil {
+		return err
+	}
+
+	return w.Apply(assignments)
+}
+
+// Apply displays the assignments through the configured backend.
+func (w *Wallpaper) Apply(assignments []Assignment) error {
+	w.logger.Debug("Applying wallpapers", "backend", w.backend.Name(), "assignments", assignments)
+
+	if err := w.backend.Apply(w.withCurrent(assignments)); err != nil {
+		w.logger.Error("Error setting wallpaper", "backend", w.backend.Name(), "error", err)
+		return err
+	}
+
+	if w.rotation == nil {
+		return nil
+	}
+
+	w.rotation.Record(assignments)
+	if err := w.rotation.Save(); err != nil {
+		w.logger.Warning("Error saving wallpaper rotation", "error", err)
+	}
+
+	return nil
+}
+
+// Previous restores the wallpaper shown before the current one on each monitor. Monitors
+// without an earlier wallpaper are left untouched.
+func (w *Wallpaper) Previous(monitors []string) error {
+	if w.rotation == nil {
+		return fmt.Errorf("wallpaper rotation is disabled")
+	}
+
+	var assignments []Assignment
+	for _, monitor := range monitors {
+		if image, ok := w.rotation.Previous(monitor); ok {
+			assignments = append(assignments, Assignment{Monitor: monitor, Image: image})
+		}
+	}
+
+	if len(assignments) == 0 {
+		return fmt.Errorf("no previous wallpaper in history")
+	}
+
+	if err := w.backend.Apply(w.withCurrent(assignments)); err != nil {
+		w.logger.Error("Error setting wallpaper", "backend", w.backend.Name(), "error", err)
+		return err
+	}
+
+	return w.rotation.Save()
+}
+
+// withCurrent adds the current wallpaper of the monitors missing from assignments, for
+// backends that would otherwise clear them. It needs the rotation state to know them.
+func (w *Wallpaper) withCurrent(assignments []Assignment) []Assignment {
+	if backend, ok := w.backend.(replacingBackend); !ok || !backend.ReplacesAll() || w.rotation == nil {
+		return assignments
+	}
+
+	given := map[string]bool{}
+	for _, assignment := range assignments {
+		if assignment.Monitor == "" {
+			return assignments
+		}
+		given[assignment.Monitor] = true
+	}
+
+	merged := slices.Clone(assignments)
+	for _, monitor := range slices.Sorted(maps.Keys(w.rotation.state.Current)) {
+		if image, ok := w.rotation.Current(monitor); ok && monitor != "" && !given[monitor] {
+			merged = append(merged, Assignment{Monitor: monitor, Image: image})
+		}
+	}
+
+	return merged
+}
+
+// AssignWallpapers picks an image for each monitor. Monitors sharing a directory get distinct
+// images as long as the directory has enough of them; a file path is used as is. With a
+// rotation, images already shown in the current cycle are skipped.
+func (w *Wallpaper) AssignWallpapers(monitors []string, wallpaperPath string, monitorPaths map[string]string) ([]Assignment, error) {
+	pools := map[string][]string{}
+	used := map[string]int{}
+	taken := map[string]bool{}
+	assignments := make([]Assignment, 0, len(monitors))
+
+	for _, monitor := range monitors {
+		path := wallpaperPath
+		if monitorPath, ok := monitorPaths[monitor]; ok && monitorPath != "" {
+			path = core.ResolvePath(monitorPath)
+		}
+
+		if _, ok := pools[path]; !ok {
+			images, err := w.imagesFor(path)
+			if err != nil {
+				return nil, err
+			}
+			pools[path] = images
+		}
+
+		images := pools[path]
+		image := images[used[path]%len(images)]
+
+		if w.rotation != nil && len(images) > 1 {
+			picked, err := w.rotation.Pick(images, taken)
+			if err != nil {
+				w.logger.Error("Error picking wallpaper", "path", path, "error", err)
+				return nil, err
+			}
+			image = picked
+		}
+
+		assignments = append(assignments, Assignment{Monitor: monitor, Image: image})
+		taken[image] = true
+		used[path]++
+	}
+
+	return assignments, nil
+}
+
+func (w *Wallpaper) imagesFor(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		w.logger.Error("Error reading wallpaper path", "path", path, "error", err)
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	return w.FetchRandomImages(path)
+}
+
+func (w *Wallpaper) FindImageFiles(dir string) ([]string, error) {
+	var imageFiles []string
+	extensions := []string{".jpg", ".jpeg", ".png"}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		for _, validExt := range extensions {
+			if ext == validExt {
+				imageFiles = append(imageFiles, path)
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return imageFiles, err
+}
+
+func (w *Wallpaper) RandomizeImages(imageFiles []string) {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rnd.Shuffle(len(imageFiles), func(i, j int) {
+		imageFiles[i], imageFiles[j] = imageFiles[j], imageFiles[i]
+	})
+}
+
+func (w *Wallpaper) FetchRandomImages(wallpaperPath string) ([]string, error) {
+	imageFiles, err := w.FindImageFiles(wallpaperPath)
+	if err != nil {
+		w.logger.Error("Error finding image files", "error", err)
+		return nil, err
+	}
+
+	if len(imageFiles) == 0 {
+		w.logger.Error("No image files found in the specified directory", "path", wallpaperPath)
+		return nil, fmt.Errorf("no image files found in %s", wallpaperPath)
+	}
+
+	w.RandomizeImages(imageFiles)
+
+	return imageFiles, nil
+}
+
+// GetMonitorNames returns the names of every enabled monitor.
+func (w *Wallpaper) GetMonitorNames() ([]string, error) {
+	names, err := w.monitors.Monitors()
+	if err != nil {
+		w.logger.Error("Error listing monitors", "error", err)
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+
+	if len(names) == 0 {
+		w.logger.Error("No monitors found")
+		return nil, fmt.Errorf("no monitors found")
+	}
+
+	return names, nil
+}
+
+func (w *Wallpaper) GetMonitorName() (string, error) {
+	monitorName, err := w.monitors.FocusedMonitor()
+	if err != nil {
+		w.logger.Error("Error getting current monitor", "error", err)
+		return "", fmt.Errorf("failed to get current monitor: %w", err)
+	}
+
+	w.logger.Debug("Current monitor", "name", monitorName)
+
+	return monitorName, nil
+}
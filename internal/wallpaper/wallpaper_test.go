@@ -0,0 +1,208 @@
+package wallpaper
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/williampsena/ebenezer-cli/internal/core"
+	"github.com/williampsena/ebenezer-cli/internal/hyprland"
+	"github.com/williampsena/ebenezer-cli/internal/shell"
+)
+
+func createImages(t *testing.T, dir string, names ...string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("img"), 0644); err != nil {
+			t.Fatalf("Failed to create image: %v", err)
+		}
+	}
+}
+
+func buildTestWallpaper(monitors []hyprland.Monitor) (*Wallpaper, *hyprland.IPCClientMockImpl) {
+	logger := core.BuildSilentLogger()
+	client := hyprland.NewIPCClientMock(logger, monitors)
+	options := BackendOptions{Logger: logger, IPC: client}
+
+	return NewWallpaper(logger, newHyprpaperBackend(options), NewMonitorLister(SessionHyprland, options)),
+		client.(*hyprland.IPCClientMockImpl)
+}
+
+func TestWallpaper_GetMonitorName(t *testing.T) {
+	t.Run("PrefersFocusedMonitor", func(t *testing.T) {
+		wallpaper, _ := buildTestWallpaper([]hyprland.Monitor{{Name: "eDP-1"}, {Name: "DP-2", Focused: true}})
+		name, err := wallpaper.GetMonitorName()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if name != "DP-2" {
+			t.Errorf("Expected DP-2, got %s", name)
+		}
+	})
+
+	t.Run("NoMonitors", func(t *testing.T) {
+		wallpaper, _ := buildTestWallpaper([]hyprland.Monitor{})
+		if _, err := wallpaper.GetMonitorName(); err == nil {
+			t.Error("Expected error when there are no monitors")
+		}
+	})
+}
+
+func TestWallpaper_GetMonitorNames(t *testing.T) {
+	wallpaper, _ := buildTestWallpaper([]hyprland.Monitor{{Name: "eDP-1"}, {Name: "DP-2", Disabled: true}, {Name: "HDMI-A-1"}})
+
+	names, err := wallpaper.GetMonitorNames()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(names) != 2 || names[0] != "eDP-1" || names[1] != "HDMI-A-1" {
+		t.Errorf("Expected enabled monitors [eDP-1 HDMI-A-1], got %v", names)
+	}
+}
+
+func TestWallpaper_AssignWallpapers(t *testing.T) {
+	wallpaper, _ := buildTestWallpaper(nil)
+
+	root := t.TempDir()
+	shared := filepath.Join(root, "shared")
+	vertical := filepath.Join(root, "vertical")
+	createImages(t, shared, "a.png", "b.jpg", "c.jpeg", "notes.txt")
+	createImages(t, vertical, "tall.png")
+
+	t.Run("DistinctImagesPerMonitor", func(t *testing.T) {
+		assignments, err := wallpaper.AssignWallpapers([]string{"eDP-1", "DP-1", "HDMI-A-1"}, shared, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		seen := map[string]bool{}
+		for _, assignment := range assignments {
+			if seen[assignment.Image] {
+				t.Errorf("Image %s assigned twice", assignment.Image)
+			}
+			seen[assignment.Image] = true
+		}
+	})
+
+	t.Run("ReusesImagesWhenPoolIsSmall", func(t *testing.T) {
+		assignments, err := wallpaper.AssignWallpapers([]string{"eDP-1", "DP-1"}, vertical, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(assignments) != 2 || assignments[0].Image != assignments[1].Image {
+			t.Errorf("Expected both monitors to share the only image, got %+v", assignments)
+		}
+	})
+
+	t.Run("PerMonitorDirectory", func(t *testing.T) {
+		assignments, err := wallpaper.AssignWallpapers(
+			[]string{"eDP-1", "DP-1"},
+			shared,
+			map[string]string{"DP-1": vertical},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if assignments[1].Image != filepath.Join(vertical, "tall.png") {
+			t.Errorf("Expected DP-1 to use the vertical directory, got %s", assignments[1].Image)
+		}
+
+		if filepath.Dir(assignments[0].Image) != shared {
+			t.Errorf("Expected eDP-1 to use the shared directory, got %s", assignments[0].Image)
+		}
+	})
+
+	t.Run("SingleFile", func(t *testing.T) {
+		file := filepath.Join(vertical, "tall.png")
+		assignments, err := wallpaper.AssignWallpapers([]string{"eDP-1"}, file, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if assignments[0].Image != file {
+			t.Errorf("Expected %s, got %s", file, assignments[0].Image)
+		}
+	})
+
+	t.Run("MissingPath", func(t *testing.T) {
+		if _, err := wallpaper.AssignWallpapers([]string{"eDP-1"}, filepath.Join(root, "missing"), nil); err == nil {
+			t.Error("Expected error for a missing wallpaper path")
+		}
+	})
+}
+
+func TestWallpaper_SetAllWallpapers(t *testing.T) {
+	wallpaper, client := buildTestWallpaper([]hyprland.Monitor{{Name: "eDP-1"}, {Name: "DP-1"}})
+
+	dir := t.TempDir()
+	createImages(t, dir, "a.png", "b.png")
+
+	if err := wallpaper.SetAllWallpapers(dir, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	wallpapers := 0
+	for _, request := range client.Requests {
+		if strings.HasPrefix(request, "hyprpaper wallpaper ") {
+			wallpapers++
+		}
+	}
+
+	if wallpapers != 2 {
+		t.Errorf("Expected 2 wallpaper requests, got %v", client.Requests)
+	}
+}
+
+// startRunner records the arguments of the commands started in the background.
+type startRunner struct {
+	shell.Runner
+	started [][]string
+}
+
+func (r *startRunner) Start(args shell.RunnerExecutionArgs) (int, error) {
+	r.started = append(r.started, args.Args)
+	return 1, nil
+}
+
+func TestWallpaper_ApplyKeepsOtherMonitors(t *testing.T) {
+	logger := core.BuildSilentLogger()
+	runner := &startRunner{}
+	options := BackendOptions{Logger: logger, Shell: runner}
+	wallpaper := NewWallpaper(logger, newSwaybgBackend(options), NewMonitorLister(SessionWayland, options))
+
+	rotation, err := LoadRotation(filepath.Join(t.TempDir(), "rotation.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rotation.Record([]Assignment{{Monitor: "eDP-1", Image: "/a.png"}, {Monitor: "DP-1", Image: "/b.png"}})
+	rotation.Record([]Assignment{{Monitor: "DP-1", Image: "/c.png"}})
+	wallpaper.SetRotation(rotation)
+
+	expect := func(want []string) {
+		t.Helper()
+		if got := runner.started[len(runner.started)-1]; !slices.Equal(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+
+	if err := wallpaper.Apply([]Assignment{{Monitor: "DP-1", Image: "/d.png"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expect([]string{"-o", "DP-1", "-i", "/d.png", "-m", "fill", "-o", "eDP-1", "-i", "/a.png", "-m", "fill"})
+
+	if err := wallpaper.Previous([]string{"DP-1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expect([]string{"-o", "DP-1", "-i", "/c.png", "-m", "fill", "-o", "eDP-1", "-i", "/a.png", "-m", "fill"})
+}